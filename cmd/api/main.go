@@ -14,6 +14,10 @@ import (
 	"github.com/sebaactis/powermix-back-mobile/internal/platform/logger"
 	"github.com/sebaactis/powermix-back-mobile/internal/clients/mailer"
 	"github.com/sebaactis/powermix-back-mobile/internal/clients/mercadopago"
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/ocr"
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/sports"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/loginevent"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/mppayment"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/prode"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/proof"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/token"
@@ -23,9 +27,12 @@ import (
 	"github.com/sebaactis/powermix-back-mobile/internal/middlewares"
 	"github.com/sebaactis/powermix-back-mobile/internal/platform/config"
 	"github.com/sebaactis/powermix-back-mobile/internal/platform/database"
+	"github.com/sebaactis/powermix-back-mobile/internal/platform/idempotency"
+	"github.com/sebaactis/powermix-back-mobile/internal/platform/ratelimit"
 	"github.com/sebaactis/powermix-back-mobile/internal/routes"
 	"github.com/sebaactis/powermix-back-mobile/internal/security/auth"
 	jwtx "github.com/sebaactis/powermix-back-mobile/internal/security/jwt"
+	"github.com/sebaactis/powermix-back-mobile/internal/security/password"
 	"github.com/sebaactis/powermix-back-mobile/internal/validations"
 )
 
@@ -64,13 +71,31 @@ func main() {
 		os.Exit(1)
 	}
 	validator := validations.NewValidator()
-	rateLimiter := middlewares.NewRateLimiter(10, 2*time.Minute)
+
+	// Rate limit
+	if err := middlewares.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		slog.Error("TRUSTED_PROXIES inválido", "error", err)
+		os.Exit(1)
+	}
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimitStore == "postgres" {
+		rateLimitStore = ratelimit.NewPostgresStore(db)
+	}
+	rateLimiter := middlewares.NewRateLimiter(rateLimitStore)
 
 	// Mailer
 	mailerClient := mailer.NewResendMailer(cfg.ResendKey, "safeimportsarg@gmail.com", "Powermix")
 
 	// MercadoPago
 	mpClient := mercadopago.NewClient(cfg.MercagoPagoToken)
+	mpClient.Reconcile = mercadopago.ReconcileOptions{
+		SearchWindow:    time.Duration(cfg.MPReconcileWindowMin) * time.Minute,
+		TimeTolerance:   time.Duration(cfg.MPReconcileToleranceMin) * time.Minute,
+		AmountTolerance: cfg.MPReconcileAmountTol,
+	}
+
+	// OCR de comprobantes (tesseract local)
+	ocrEngine := ocr.NewTesseractEngine(cfg.OCRTesseractBin, cfg.OCRLang)
 
 	// Coffeeji
 	coffejiClient := coffeeji.NewClient(cfg.CoffejiKey, cfg.CoffejiSecret)
@@ -80,27 +105,92 @@ func main() {
 	tokenService := token.NewService(tokenRepository, validator, cfg.HashToken)
 	tokenHandler := token.NewHTTPHandler(tokenService)
 
+	// Password policy
+	passwordPolicy := password.Policy{
+		MinLength:     cfg.PasswordMinLength,
+		RequireUpper:  cfg.PasswordRequireUpper,
+		RequireLower:  cfg.PasswordRequireLower,
+		RequireDigit:  cfg.PasswordRequireDigit,
+		RequireSymbol: cfg.PasswordRequireSymbol,
+		HistorySize:   cfg.PasswordHistorySize,
+	}
+	var breachedChecker password.BreachedChecker
+	if cfg.BreachedPasswordsDir != "" {
+		checker, err := password.NewPrefixDirChecker(cfg.BreachedPasswordsDir)
+		if err != nil {
+			slog.Error("dataset de contraseñas filtradas inválido", "error", err)
+			os.Exit(1)
+		}
+		breachedChecker = checker
+	}
+
 	// Users DI
 	userRepository := user.NewRepository(db)
-	userService := user.NewService(userRepository, tokenService, validator, mailerClient)
+	userService := user.NewService(userRepository, tokenService, validator, mailerClient, passwordPolicy, breachedChecker)
 	userHandler := user.NewHTTPHandler(userService, jwt)
+	if promoted, demoted, err := userService.SyncAdmins(context.Background(), cfg.AdminEmails); err != nil {
+		slog.Error("no se pudieron aplicar ADMIN_EMAILS", "error", err)
+		os.Exit(1)
+	} else if promoted > 0 || demoted > 0 {
+		slog.Info("roles de administrador actualizados", "promoted", promoted, "demoted", demoted)
+	}
 
 	// Voucher DI
 	voucherRepository := voucher.NewRepository(db)
 	voucherService := voucher.NewService(voucherRepository, userRepository, mailerClient, coffejiClient)
 	voucherHandler := voucher.NewHTTPHandler(voucherService)
 
+	// Espejo de pagos de Mercado Pago DI
+	mpPaymentRepository := mppayment.NewRepository(db)
+	mpPaymentService := mppayment.NewService(mpPaymentRepository, mpClient)
+
 	// Proof DI
 	proofRepository := proof.NewRepository(db)
-	proofService := proof.NewService(proofRepository, userService, voucherService, validator, mpClient, coffejiClient)
+	proofRules := proof.NewRules(proof.EligibilityConfig{
+		MaxAge:          time.Duration(cfg.ProofMaxAgeDays) * 24 * time.Hour,
+		MinAmount:       cfg.ProofMinAmount,
+		CollectorID:     int64(cfg.MPCollectorID),
+		AllowedProducts: cfg.ProofProducts,
+	})
+	fraudConfig := proof.DefaultFraudConfig()
+	fraudConfig.Threshold = cfg.FraudReviewThreshold
+	var stampPolicy proof.StampPolicy = proof.SingleStamp{}
+	switch cfg.StampMode {
+	case "item":
+		stampPolicy = proof.PerItem{Max: cfg.StampMaxPerProof}
+	case "amount":
+		tiers, err := proof.ParseAmountTiers(cfg.StampAmountTiers)
+		if err != nil {
+			slog.Error("STAMP_AMOUNT_TIERS inválido", "error", err)
+			os.Exit(1)
+		}
+		stampPolicy = tiers
+	}
+	proofService := proof.NewService(proofRepository, userService, voucherService, validator, mpPaymentService, coffejiClient, ocrEngine, cfg.HashToken, mailerClient, proofRules, stampPolicy, fraudConfig)
 	proofHandler := proof.NewHTTPHandler(proofService)
+	var webhookHandler *proof.WebhookHandler
+	if cfg.MPWebhookSecret != "" {
+		webhookHandler = proof.NewWebhookHandler(proofService, mpClient, cfg.MPWebhookSecret)
+	} else {
+		slog.Info("webhook de Mercado Pago deshabilitado (MP_WEBHOOK_SECRET vacío)")
+	}
+
+	// Login events DI
+	loginEventRepository := loginevent.NewRepository(db)
+	loginEventService := loginevent.NewService(loginEventRepository)
+	geoResolver := loginevent.HeaderGeoResolver{Header: cfg.GeoCountryHeader}
 
 	// Auth DI
-	authHandler := auth.NewHTTPHandler(userService, tokenService, jwt, validator, mailerClient)
+	authHandler := auth.NewHTTPHandler(userService, tokenService, jwt, validator, mailerClient, loginEventService, geoResolver)
 
 	// Prode DI
 	prodeRepository := prode.NewRepository(db)
-	prodeService := prode.NewService(prodeRepository, voucherRepository, userRepository, mailerClient, cfg.ProdeAdminEmails)
+	prodeScoring := prode.Scoring{
+		Exact:    cfg.ProdePointsExact,
+		Outcome:  cfg.ProdePointsOutcome,
+		GoalDiff: cfg.ProdePointsGoalDiff,
+	}
+	prodeService := prode.NewService(prodeRepository, voucherRepository, userRepository, mailerClient, cfg.ProdeAdminEmails, prodeScoring)
 	prodeHandler := prode.NewHTTPHandler(prodeService)
 
 	if cfg.IsProdeEnabled() {
@@ -122,12 +212,16 @@ func main() {
 		UserHandler:    userHandler,
 		TokenHandler:   tokenHandler,
 		ProofHandler:   proofHandler,
+		RoleResolver:   userService,
+		WebhookHandler: webhookHandler,
 		VoucherHandler: voucherHandler,
 		AuthHandler:    authHandler,
 		ProdeHandler:   prodeHandler,
 		Config:         cfg,
 		AuthMiddleware: authMiddleware,
 		RateLimiter:    rateLimiter,
+		RateLimits:     ratelimit.DefaultPolicies(),
+		Idempotency:    idempotency.NewPostgresStore(db),
 		Validator:      validator,
 	})
 
@@ -138,6 +232,64 @@ func main() {
 	}
 	defer voucherCron.Stop()
 
+	mpSyncCron := jobs.NewMPSyncCron(mpPaymentService, "@every 1m", 50*time.Second)
+	if err := mpSyncCron.Start(); err != nil {
+		slog.Error("cannot start mp sync cron", "error", err)
+		os.Exit(1)
+	}
+	defer mpSyncCron.Stop()
+
+	reversalCron := jobs.NewReversalCron(mpPaymentService, proofService, "@every 30m", 7*24*time.Hour, 2*time.Minute)
+	if err := reversalCron.Start(); err != nil {
+		slog.Error("cannot start reversal cron", "error", err)
+		os.Exit(1)
+	}
+	defer reversalCron.Stop()
+
+	autoLinkCron := jobs.NewAutoLinkCron(mpPaymentService, proofService, "@every 5m", 2*time.Hour, 2*time.Minute)
+	if err := autoLinkCron.Start(); err != nil {
+		slog.Error("cannot start autolink cron", "error", err)
+		os.Exit(1)
+	}
+	defer autoLinkCron.Stop()
+
+	if cfg.IsProdeEnabled() {
+		openLead := time.Duration(cfg.ProdeOpenLeadHours) * time.Hour
+		prodeLifecycleCron := jobs.NewProdeLifecycleCron(prodeService, "@every 1m", openLead, cfg.ProdeAutoSettle, 2*time.Minute)
+		if err := prodeLifecycleCron.Start(); err != nil {
+			slog.Error("cannot start prode lifecycle cron", "error", err)
+			os.Exit(1)
+		}
+		defer prodeLifecycleCron.Stop()
+
+		if cfg.ProdeReminderLeadHours > 0 {
+			reminderLead := time.Duration(cfg.ProdeReminderLeadHours) * time.Hour
+			prodeReminderCron := jobs.NewProdeReminderCron(prodeService, "@every 10m", reminderLead, 5*time.Minute)
+			if err := prodeReminderCron.Start(); err != nil {
+				slog.Error("cannot start prode reminder cron", "error", err)
+				os.Exit(1)
+			}
+			defer prodeReminderCron.Stop()
+		}
+
+		if cfg.SportsAPIToken != "" {
+			tournament, err := prodeRepository.GetTournamentBySlug(context.Background(), cfg.SportsTournamentSlug)
+			if err != nil {
+				slog.Error("cannot resolve prode feed tournament", "slug", cfg.SportsTournamentSlug, "error", err)
+				os.Exit(1)
+			}
+
+			feed := sports.NewFootballData(cfg.SportsAPIURL, cfg.SportsAPIToken)
+			feedSync := prode.NewFeedSync(prodeService, feed, tournament.ID, int64(cfg.SportsTeamID), cfg.SportsCompetition)
+			prodeFeedCron := jobs.NewProdeFeedCron(feedSync, "@every 6h", "@every 5m", 2*time.Minute)
+			if err := prodeFeedCron.Start(); err != nil {
+				slog.Error("cannot start prode feed cron", "error", err)
+				os.Exit(1)
+			}
+			defer prodeFeedCron.Stop()
+		}
+	}
+
 	srv := &http.Server{
 		Addr:         cfg.HTTPAddr,
 		Handler:      r,
@@ -75,20 +75,43 @@ func (c *Client) GetOrderList(ctx context.Context, orderNo string) (*OrderListRe
 }
 
 func (c *Client) GetGoodsNameByOrderNo(ctx context.Context, orderNo string) (string, error) {
-	resp, err := c.GetOrderList(ctx, orderNo)
+	items, err := c.GetOrderItems(ctx, orderNo)
 	if err != nil {
 		return "", err
 	}
 
+	return items[0].Name, nil
+}
+
+// GetOrderItems devuelve las líneas del pedido. Si Coffeeji no informa la
+// cantidad de una línea se cuenta como una unidad.
+func (c *Client) GetOrderItems(ctx context.Context, orderNo string) ([]OrderItem, error) {
+	resp, err := c.GetOrderList(ctx, orderNo)
+	if err != nil {
+		return nil, err
+	}
+
 	if !resp.Success || resp.Code != 200 {
-		return "", fmt.Errorf("respuesta no exitosa: code=%d, msg=%s", resp.Code, resp.Msg)
+		return nil, fmt.Errorf("respuesta no exitosa: code=%d, msg=%s", resp.Code, resp.Msg)
 	}
 
 	if len(resp.Data.Records) == 0 {
-		return "", fmt.Errorf("no se encontraron registros para orderNo %s", orderNo)
+		return nil, fmt.Errorf("no se encontraron registros para orderNo %s", orderNo)
 	}
 
-	return resp.Data.Records[0].GoodsName, nil
+	return toOrderItems(resp.Data.Records), nil
+}
+
+func toOrderItems(records []OrderRecord) []OrderItem {
+	items := make([]OrderItem, 0, len(records))
+	for _, r := range records {
+		qty := r.GoodsNum
+		if qty <= 0 {
+			qty = 1
+		}
+		items = append(items, OrderItem{Name: r.GoodsName, Quantity: qty, Amount: r.PayPrice})
+	}
+	return items
 }
 
 func (c *Client) ValidateVoucherCode(ctx context.Context, voucherCode string) (bool, error) {
@@ -13,8 +13,19 @@ type OrderListData struct {
 	Records []OrderRecord `json:"records"`
 }
 
+// OrderRecord es una línea del pedido. Un pedido con varios productos trae un
+// registro por producto.
 type OrderRecord struct {
-	GoodsName string `json:"goodsName"`
+	GoodsName string  `json:"goodsName"`
+	GoodsNum  int     `json:"goodsNum"`
+	PayPrice  float64 `json:"payPrice"`
+}
+
+// OrderItem es una línea del pedido normalizada.
+type OrderItem struct {
+	Name     string
+	Quantity int
+	Amount   float64
 }
 
 type VoucherResponse struct {
@@ -1,5 +1,7 @@
 package mailer
 
+import "time"
+
 type ContactRequest struct {
 	Name     string `json:"name" validate:"required,min=6,max=30"`
 	Email    string `json:"email" validate:"required,min=6,max=30,email"`
@@ -14,3 +16,30 @@ type ContactResponse struct {
 	Message    string `json:"message"`
 	ApiMessage string `json:"apiMessage"`
 }
+
+// LoginAlert resume un login desde un dispositivo o país no habitual.
+type LoginAlert struct {
+	IP        string
+	Country   string
+	UserAgent string
+	At        time.Time
+}
+
+// ProofReversal describe un comprobante anulado por devolución o contracargo.
+// Outcome indica qué se descontó: un sello, un voucher o nada (revisión).
+type ProofReversal struct {
+	PaymentID string
+	Reason    string
+	Outcome   string
+	At        time.Time
+}
+
+// ProdeParticipation resume cuántos usuarios pronosticaron un partido del
+// prode y a cuántos se les mandó recordatorio.
+type ProdeParticipation struct {
+	Match       string
+	Stage       string
+	CutoffAt    time.Time
+	Predictions int
+	Reminded    int
+}
@@ -1,10 +1,18 @@
 package mailer
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Mailer interface {
 	SendResetPasswordEmail(ctx context.Context, toEmail, resetURL string) error
 	SendVoucherEmail(ctx context.Context, toEmail, voucherUrl string) error
 	SendEmailContact(ctx context.Context, contactRequest *ContactRequest) error
-	SendProdeAdminNotification(ctx context.Context, toEmail, opponent, stage string, pendingCount int) error
+	SendProdeAdminNotification(ctx context.Context, toEmail, matchLabel, stage string, pendingCount int) error
+	SendProdeReminderEmail(ctx context.Context, toEmail, name, matchLabel string, cutoffAt time.Time) error
+	SendProdeParticipationSummary(ctx context.Context, toEmail string, summary ProdeParticipation) error
+	SendLoginCodeEmail(ctx context.Context, toEmail, code, loginURL string) error
+	SendLoginAlertEmail(ctx context.Context, toEmail string, alert LoginAlert, revokeURL string) error
+	SendProofReversedEmail(ctx context.Context, toEmail string, reversal ProofReversal) error
 }
@@ -4,6 +4,7 @@ package mailer
 import (
 	"context"
 	"fmt"
+	htmlstd "html"
 	"strings"
 	"time"
 
@@ -51,6 +52,123 @@ func (m *ResendMailer) SendResetPasswordEmail(ctx context.Context, toEmail, rese
 	return err
 }
 
+func (m *ResendMailer) SendLoginCodeEmail(ctx context.Context, toEmail, code, loginURL string) error {
+	html := fmt.Sprintf(`
+		<div style="font-family: system-ui, -apple-system, BlinkMacSystemFont, 'Segoe UI', sans-serif;">
+			<h2>Tu código de acceso - %s</h2>
+			<p>Usá este código para ingresar. Vence en pocos minutos y sirve una sola vez:</p>
+			<p style="font-size:28px;font-weight:700;letter-spacing:6px;">%s</p>
+			<p>O ingresá directamente desde este botón:</p>
+			<p>
+				<a href="%s" style="display:inline-block;padding:10px 18px;background:#8B003A;color:#ffffff;text-decoration:none;border-radius:6px;font-weight:600;">
+					Ingresar
+				</a>
+			</p>
+			<p>Si no pediste este código, podés ignorar este correo.</p>
+		</div>
+	`, m.appName, code, loginURL)
+
+	params := &resend.SendEmailRequest{
+		From:    "no-reply@powermixstation.com.ar",
+		To:      []string{toEmail},
+		Subject: "Tu código de acceso",
+		Html:    html,
+	}
+
+	_, err := m.client.Emails.Send(params)
+	return err
+}
+
+func (m *ResendMailer) SendProofReversedEmail(ctx context.Context, toEmail string, reversal ProofReversal) error {
+	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	if err != nil {
+		loc = time.UTC
+	}
+
+	var detail string
+	switch reversal.Outcome {
+	case "STAMP_DEBITED":
+		detail = "Descontamos el sello que te había sumado ese pago."
+	case "VOUCHER_REVOKED":
+		detail = "Como ese sello completaba un voucher que todavía no usaste, el voucher quedó anulado."
+	case "NO_STAMPS":
+		detail = "Ese comprobante todavía no había sumado sellos, así que no descontamos nada."
+	default:
+		detail = "El voucher asociado ya fue usado, así que nuestro equipo va a revisar el caso."
+	}
+
+	html := fmt.Sprintf(`
+		<div style="font-family: system-ui, -apple-system, BlinkMacSystemFont, 'Segoe UI', sans-serif;">
+			<h2>Comprobante anulado - %s</h2>
+			<p>El pago <strong>%s</strong> figura como <strong>%s</strong> en Mercado Pago, por lo que anulamos el comprobante que cargaste.</p>
+			<p>%s</p>
+			<p style="color:#6B7280;font-size:12px;">Fecha: %s</p>
+			<p>Si creés que es un error, respondé este correo o escribinos desde la app.</p>
+		</div>
+	`, m.appName,
+		htmlstd.EscapeString(reversal.PaymentID),
+		htmlstd.EscapeString(reversal.Reason),
+		detail,
+		reversal.At.In(loc).Format("02/01/2006 15:04"),
+	)
+
+	params := &resend.SendEmailRequest{
+		From:    "no-reply@powermixstation.com.ar",
+		To:      []string{toEmail},
+		Subject: "Anulamos un comprobante",
+		Html:    html,
+	}
+
+	_, err = m.client.Emails.Send(params)
+	return err
+}
+
+func (m *ResendMailer) SendLoginAlertEmail(ctx context.Context, toEmail string, alert LoginAlert, revokeURL string) error {
+	country := alert.Country
+	if country == "" {
+		country = "desconocido"
+	}
+
+	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	if err != nil {
+		loc = time.UTC
+	}
+
+	html := fmt.Sprintf(`
+		<div style="font-family: system-ui, -apple-system, BlinkMacSystemFont, 'Segoe UI', sans-serif;">
+			<h2>Nuevo inicio de sesión - %s</h2>
+			<p>Detectamos un ingreso a tu cuenta desde un dispositivo o ubicación que no usabas.</p>
+			<ul>
+				<li><strong>Fecha:</strong> %s</li>
+				<li><strong>IP:</strong> %s</li>
+				<li><strong>País:</strong> %s</li>
+				<li><strong>Dispositivo:</strong> %s</li>
+			</ul>
+			<p>Si fuiste vos, no tenés que hacer nada. Si no, cerrá esa sesión y cambiá tu contraseña:</p>
+			<p>
+				<a href="%s" style="display:inline-block;padding:10px 18px;background:#8B003A;color:#ffffff;text-decoration:none;border-radius:6px;font-weight:600;">
+					No fui yo, cerrar la sesión
+				</a>
+			</p>
+		</div>
+	`, m.appName,
+		alert.At.In(loc).Format("02/01/2006 15:04"),
+		htmlstd.EscapeString(alert.IP),
+		htmlstd.EscapeString(country),
+		htmlstd.EscapeString(alert.UserAgent),
+		revokeURL)
+
+	params := &resend.SendEmailRequest{
+		From:    "no-reply@powermixstation.com.ar",
+		To:      []string{toEmail},
+		Subject: "¿Fuiste vos? Nuevo inicio de sesión",
+		Html:    html,
+	}
+
+	_, err = m.client.Emails.Send(params)
+	return err
+}
+
 func (m *ResendMailer) SendVoucherEmail(ctx context.Context, toEmail, voucherUrl string) error {
 	html := fmt.Sprintf(`
         <div style="font-family: system-ui, -apple-system, BlinkMacSystemFont, 'Segoe UI', sans-serif;">
@@ -88,17 +206,73 @@ func (m *ResendMailer) SendVoucherEmail(ctx context.Context, toEmail, voucherUrl
 	return err
 }
 
-func (m *ResendMailer) SendProdeAdminNotification(ctx context.Context, toEmail, opponent, stage string, pendingCount int) error {
-	subject := fmt.Sprintf("[PRODE] Premios pendientes — Argentina vs %s", opponent)
+func (m *ResendMailer) SendProdeAdminNotification(ctx context.Context, toEmail, matchLabel, stage string, pendingCount int) error {
+	subject := fmt.Sprintf("[PRODE] Premios pendientes — %s", matchLabel)
 	html := fmt.Sprintf(`
 		<div style="font-family: system-ui, -apple-system, BlinkMacSystemFont, 'Segoe UI', sans-serif;">
 			<h2>Premios PRODE pendientes</h2>
 			<p>Hay <strong>%d</strong> predicciones correctas que no pudieron ser premiadas por falta de vouchers disponibles.</p>
-			<p><strong>Partido:</strong> Argentina vs %s</p>
+			<p><strong>Partido:</strong> %s</p>
 			<p><strong>Instancia:</strong> %s</p>
 			<p>Ingresá al panel admin de PRODE para cargar más vouchers y ejecutar el reintento de premios pendientes.</p>
 		</div>
-	`, pendingCount, opponent, stage)
+	`, pendingCount, matchLabel, stage)
+
+	params := &resend.SendEmailRequest{
+		From:    "no-reply@powermixstation.com.ar",
+		To:      []string{toEmail},
+		Subject: subject,
+		Html:    html,
+	}
+
+	_, err := m.client.Emails.Send(params)
+	return err
+}
+
+func (m *ResendMailer) SendProdeReminderEmail(ctx context.Context, toEmail, name, matchLabel string, cutoffAt time.Time) error {
+	html := fmt.Sprintf(`
+		<div style="font-family: system-ui, -apple-system, BlinkMacSystemFont, 'Segoe UI', sans-serif;">
+			<h2>¡Todavía no hiciste tu pronóstico! - %s</h2>
+			<p>Hola %s, te falta pronosticar <strong>%s</strong>.</p>
+			<p>Tenés tiempo hasta el <strong>%s</strong>. Entrá a la app y cargá tu resultado.</p>
+			<p style="color:#6B7280;font-size:12px;">Si no querés recibir más recordatorios, podés desactivarlos desde el PRODE en la app.</p>
+		</div>
+	`, m.appName,
+		htmlstd.EscapeString(name),
+		htmlstd.EscapeString(matchLabel),
+		cutoffAt.Format("02/01/2006 15:04"),
+	)
+
+	params := &resend.SendEmailRequest{
+		From:    "no-reply@powermixstation.com.ar",
+		To:      []string{toEmail},
+		Subject: fmt.Sprintf("PRODE: pronosticá %s", matchLabel),
+		Html:    html,
+	}
+
+	_, err := m.client.Emails.Send(params)
+	return err
+}
+
+func (m *ResendMailer) SendProdeParticipationSummary(ctx context.Context, toEmail string, summary ProdeParticipation) error {
+	subject := fmt.Sprintf("[PRODE] Participación — %s", summary.Match)
+	html := fmt.Sprintf(`
+		<div style="font-family: system-ui, -apple-system, BlinkMacSystemFont, 'Segoe UI', sans-serif;">
+			<h2>Participación PRODE</h2>
+			<p><strong>Partido:</strong> %s</p>
+			<p><strong>Instancia:</strong> %s</p>
+			<p><strong>Cierre de pronósticos:</strong> %s</p>
+			<ul>
+				<li><strong>Pronósticos cargados:</strong> %d</li>
+				<li><strong>Recordatorios enviados:</strong> %d</li>
+			</ul>
+		</div>
+	`, htmlstd.EscapeString(summary.Match),
+		htmlstd.EscapeString(summary.Stage),
+		summary.CutoffAt.Format("02/01/2006 15:04 MST"),
+		summary.Predictions,
+		summary.Reminded,
+	)
 
 	params := &resend.SendEmailRequest{
 		From:    "no-reply@powermixstation.com.ar",
@@ -8,19 +8,42 @@ import (
 	"math"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 )
 
 type Client struct {
-	Token  string
-	Client *http.Client
+	Token     string
+	Client    *http.Client
+	Reconcile ReconcileOptions
+}
+
+// ReconcileOptions ajusta la búsqueda de pagos de ReconcileOthers.
+type ReconcileOptions struct {
+	// SearchWindow es el margen hacia cada lado de la hora informada con el
+	// que se consulta a Mercado Pago. Cubre relojes de celular desfasados.
+	SearchWindow time.Duration
+	// TimeTolerance es la diferencia máxima entre la hora informada y la del
+	// pago para considerarlo candidato.
+	TimeTolerance time.Duration
+	// AmountTolerance es la diferencia máxima de monto aceptada.
+	AmountTolerance float64
+}
+
+func DefaultReconcileOptions() ReconcileOptions {
+	return ReconcileOptions{
+		SearchWindow:    10 * time.Minute,
+		TimeTolerance:   5 * time.Minute,
+		AmountTolerance: 0.01,
+	}
 }
 
 func NewClient(token string) *Client {
 	return &Client{
-		Token:  token,
-		Client: &http.Client{Timeout: 10 * time.Second},
+		Token:     token,
+		Client:    &http.Client{Timeout: 10 * time.Second},
+		Reconcile: DefaultReconcileOptions(),
 	}
 }
 
@@ -73,32 +96,88 @@ func (c *Client) ReconcileOthers(
 	req ReconcileOthersRequest,
 ) (*ReconcileOthersResult, error) {
 
+	tLocal, err := ReconcileTime(req)
+	if err != nil {
+		return nil, err
+	}
+
+	begin, end := tLocal.Add(-c.Reconcile.SearchWindow), tLocal.Add(c.Reconcile.SearchWindow)
+
+	payments, err := c.searchPaymentsInWindow(ctx, begin, end, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return MatchPayments(payments, req, tLocal, c.Reconcile)
+}
+
+// ReconcileTime valida el pedido y devuelve la hora informada por el usuario.
+func ReconcileTime(req ReconcileOthersRequest) (time.Time, error) {
 	if strings.TrimSpace(req.Date) == "" ||
 		strings.TrimSpace(req.Time) == "" ||
 		req.Amount <= 0 {
-		return nil, fmt.Errorf("la fecha, hora y monto del comprobante son datos obligatorios")
+		return time.Time{}, fmt.Errorf("la fecha, hora y monto del comprobante son datos obligatorios")
 	}
 
 	tLocal, err := parseUserDateTime(req.Date, req.Time)
 	if err != nil {
-		return nil, fmt.Errorf("formato fecha/hora inválido: %w", err)
+		return time.Time{}, fmt.Errorf("formato fecha/hora inválido: %w", err)
 	}
+	return tLocal, nil
+}
 
-	begin, end := buildWindow(tLocal, 2)
+// MatchPayments elige el pago del comprobante entre payments. Devuelve nil
+// si ninguno coincide y *AmbiguousMatchError si hay más de uno. Se usa tanto
+// con resultados de la API como con el espejo local.
+func MatchPayments(payments []MercadoPagoPayment, req ReconcileOthersRequest, tLocal time.Time, opts ReconcileOptions) (*ReconcileOthersResult, error) {
+	candidates := scoreCandidates(payments, req, tLocal, opts)
 
-	payments, err := c.searchPaymentsInWindow(ctx, begin, end, req.Amount)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	if len(candidates) > 1 {
+		if len(candidates) > maxCandidates {
+			candidates = candidates[:maxCandidates]
+		}
+		return nil, &AmbiguousMatchError{Candidates: candidates}
+	}
+
+	return candidates[0].Payment, nil
+}
+
+// SearchUpdatedSince lista pagos modificados desde since, del más viejo al
+// más nuevo. Devuelve también el total informado por la API.
+func (c *Client) SearchUpdatedSince(ctx context.Context, since time.Time, offset, limit int) ([]MercadoPagoPayment, int, error) {
+	params := url.Values{}
+	params.Add("range", "date_last_updated")
+	params.Add("begin_date", formatMPDate(since))
+	params.Add("end_date", "NOW")
+	params.Add("sort", "date_last_updated")
+	params.Add("criteria", "asc")
+	params.Add("limit", fmt.Sprintf("%d", limit))
+	params.Add("offset", fmt.Sprintf("%d", offset))
+
+	mpResp, err := c.search(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
+	return mpResp.Results, mpResp.Paging.Total, nil
+}
 
-	loc, _ := time.LoadLocation("America/Argentina/Buenos_Aires")
+// maxCandidates limita cuántos pagos se le muestran al usuario para elegir.
+const maxCandidates = 5
 
-	var candidates []MercadoPagoPayment
+// scoreCandidates filtra los pagos compatibles con el comprobante y los
+// ordena por puntaje: cuanto más cerca la hora y el monto, mayor.
+func scoreCandidates(payments []MercadoPagoPayment, req ReconcileOthersRequest, tLocal time.Time, opts ReconcileOptions) []Candidate {
+	var candidates []Candidate
 
 	for _, p := range payments {
-
-		if math.Abs(p.TransactionDetails.TotalPaidAmount-req.Amount) > 0.01 &&
-			math.Abs(p.TransactionAmount-req.Amount) > 0.01 {
+		amountDiff := math.Min(
+			math.Abs(p.TransactionDetails.TotalPaidAmount-req.Amount),
+			math.Abs(p.TransactionAmount-req.Amount),
+		)
+		if amountDiff > opts.AmountTolerance {
 			continue
 		}
 
@@ -117,43 +196,58 @@ func (c *Client) ReconcileOthers(
 		}
 
 		var tMP time.Time
-		if !p.DateApproved.IsZero() {
+		if p.DateApproved != nil && !p.DateApproved.IsZero() {
 			tMP = *p.DateApproved
 		} else {
 			tMP = p.DateCreated
 		}
 
-		tMPLocal := tMP.In(loc)
-		diff := tMPLocal.Sub(tLocal)
-		if diff < -3*time.Minute || diff > 3*time.Minute {
+		diff := tMP.Sub(tLocal)
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > opts.TimeTolerance {
 			continue
 		}
 
-		candidates = append(candidates, p)
-	}
+		timeScore := 1.0
+		if opts.TimeTolerance > 0 {
+			timeScore = 1 - float64(diff)/float64(opts.TimeTolerance)
+		}
+		amountScore := 1.0
+		if opts.AmountTolerance > 0 {
+			amountScore = 1 - amountDiff/opts.AmountTolerance
+		}
 
-	if len(candidates) == 0 {
-		return nil, nil
-	}
-	if len(candidates) > 1 {
-		return nil, fmt.Errorf("se encontraron múltiples pagos que podrían matchear el comprobante, por favor, de ser posible, agregue mas información")
+		candidates = append(candidates, Candidate{
+			Payment:  toReconcileResult(&p, tMP),
+			TimeDiff: diff,
+			Score:    math.Round((0.7*timeScore+0.3*amountScore)*100) / 100,
+		})
 	}
 
-	p := candidates[0]
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	return candidates
+}
 
+func toReconcileResult(p *MercadoPagoPayment, approved time.Time) *ReconcileOthersResult {
 	return &ReconcileOthersResult{
 		PaymentID:       p.ID,
 		Status:          p.Status,
 		TotalPaidAmount: p.TransactionDetails.TotalPaidAmount,
 		OperationType:   p.OperationType,
-		DateApproved:    *p.DateApproved,
+		DateApproved:    approved,
 		PayerEmail:      p.Payer.Email,
-		PayerDNI:        extractDNI(&p),
-		CardLast4:       extractCardLast4(&p),
+		PayerDNI:        extractDNI(p),
+		CardLast4:       extractCardLast4(p),
 		CardId:          &p.PaymentMethodId,
 		CardType:        &p.PaymentTypeId,
 		ExternalID:      &p.ExternalReference,
-	}, nil
+		CollectorID:     p.CollectorID,
+	}
 }
 
 //************* FUNCIONES PRIVADAS DEL CLIENTE *************//
@@ -164,8 +258,6 @@ func (c *Client) searchPaymentsInWindow(
 	amount float64,
 ) ([]MercadoPagoPayment, error) {
 
-	baseURL := "https://api.mercadopago.com/v1/payments/search"
-
 	beginStr := formatMPDate(begin)
 	endStr := formatMPDate(end)
 
@@ -187,32 +279,11 @@ func (c *Client) searchPaymentsInWindow(
 		params.Add("offset", fmt.Sprintf("%d", offset))
 		params.Add("transaction_amount", fmt.Sprintf("%.2f", amount))
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"?"+params.Encode(), nil)
-		if err != nil {
-			return nil, err
-		}
-		req.Header.Set("Authorization", "Bearer "+c.Token)
-
-		resp, err := c.Client.Do(req)
-		if err != nil {
-			return nil, err
-		}
-
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
+		mpResp, err := c.search(ctx, params)
 		if err != nil {
 			return nil, err
 		}
 
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("mercado pago search error %d: %s", resp.StatusCode, string(body))
-		}
-
-		var mpResp mpSearchResponse
-		if err := json.Unmarshal(body, &mpResp); err != nil {
-			return nil, err
-		}
-
 		all = append(all, mpResp.Results...)
 
 		if len(mpResp.Results) < limit || offset+limit >= mpResp.Paging.Total {
@@ -224,6 +295,37 @@ func (c *Client) searchPaymentsInWindow(
 	return all, nil
 }
 
+func (c *Client) search(ctx context.Context, params url.Values) (*mpSearchResponse, error) {
+	baseURL := "https://api.mercadopago.com/v1/payments/search"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("mercado pago search error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var mpResp mpSearchResponse
+	if err := json.Unmarshal(body, &mpResp); err != nil {
+		return nil, err
+	}
+	return &mpResp, nil
+}
+
 func parseUserDateTime(dateStr, timeStr string) (time.Time, error) {
 
 	timeStr = strings.ReplaceAll(timeStr, ".", ":")
@@ -237,11 +339,6 @@ func parseUserDateTime(dateStr, timeStr string) (time.Time, error) {
 	return time.ParseInLocation(layout, dateStr+" "+timeStr, loc)
 }
 
-func buildWindow(t time.Time, mins int) (time.Time, time.Time) {
-	d := time.Duration(mins) * time.Minute
-	return t.Add(-d), t.Add(d)
-}
-
 func extractDNI(p *MercadoPagoPayment) *string {
 
 	if p.Card.Cardholder != nil &&
@@ -0,0 +1,40 @@
+package mercadopago
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScoreCandidates(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 11, 18, 12, 11, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { v := base.Add(d); return &v }
+	payment := func(id int64, amount float64, approved *time.Time) MercadoPagoPayment {
+		return MercadoPagoPayment{
+			ID:                 id,
+			DateApproved:       approved,
+			TransactionAmount:  amount,
+			TransactionDetails: TransactionDetails{TotalPaidAmount: amount},
+		}
+	}
+
+	payments := []MercadoPagoPayment{
+		payment(1, 1500, at(4*time.Minute)),
+		payment(2, 1500, at(30*time.Second)),
+		payment(3, 1500, at(8*time.Minute)), // fuera de tolerancia
+		payment(4, 1600, at(0)),             // otro monto
+	}
+
+	got := scoreCandidates(payments, ReconcileOthersRequest{Amount: 1500}, base, DefaultReconcileOptions())
+
+	if len(got) != 2 {
+		t.Fatalf("candidates = %d, want 2", len(got))
+	}
+	if got[0].Payment.PaymentID != 2 || got[1].Payment.PaymentID != 1 {
+		t.Fatalf("order = [%d %d], want [2 1]", got[0].Payment.PaymentID, got[1].Payment.PaymentID)
+	}
+	if got[0].Score <= got[1].Score {
+		t.Fatalf("scores = [%v %v], want descending", got[0].Score, got[1].Score)
+	}
+}
@@ -1,6 +1,9 @@
 package mercadopago
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type Identification struct {
 	Number *string `json:"number"`
@@ -52,8 +55,9 @@ type MercadoPagoPayment struct {
 	Status        string `json:"status"`
 	OperationType string `json:"operation_type"`
 
-	DateApproved *time.Time `json:"date_approved"`
-	DateCreated  time.Time  `json:"date_created"`
+	DateApproved    *time.Time `json:"date_approved"`
+	DateCreated     time.Time  `json:"date_created"`
+	DateLastUpdated *time.Time `json:"date_last_updated"`
 
 	TransactionAmount  float64            `json:"transaction_amount"`
 	TransactionDetails TransactionDetails `json:"transaction_details"`
@@ -66,6 +70,7 @@ type MercadoPagoPayment struct {
 	PaymentMethodId   string `json:"payment_method_id"`
 	PaymentTypeId     string `json:"payment_type_id"`
 	ExternalReference string `json:"external_reference"`
+	CollectorID       int64  `json:"collector_id"`
 }
 
 type PaymentDTO struct {
@@ -80,6 +85,7 @@ type PaymentDTO struct {
 	CardId          *string   `json:"card_id"`
 	CardType        *string   `json:"card_type"`
 	ExternalID      *string   `json:"external_id"`
+	CollectorID     int64     `json:"collector_id"`
 }
 
 func (mp *MercadoPagoPayment) ToDTO() *PaymentDTO {
@@ -100,6 +106,7 @@ func (mp *MercadoPagoPayment) ToDTO() *PaymentDTO {
 		OperationType:   mp.OperationType,
 		Status:          mp.Status,
 		TotalPaidAmount: mp.TransactionDetails.TotalPaidAmount,
+		CollectorID:     mp.CollectorID,
 
 	}
 }
@@ -125,4 +132,23 @@ type ReconcileOthersResult struct {
 	CardId          *string   `json:"card_id"`
 	CardType        *string   `json:"card_type"`
 	ExternalID      *string   `json:"external_id"`
+	CollectorID     int64     `json:"collector_id"`
+}
+
+// Candidate es un pago que podría corresponder al comprobante.
+type Candidate struct {
+	Payment  *ReconcileOthersResult
+	TimeDiff time.Duration
+	// Score va de 0 a 1; más alto es más probable.
+	Score float64
+}
+
+// AmbiguousMatchError se devuelve cuando más de un pago coincide con el
+// comprobante. Candidates viene ordenado por Score descendente.
+type AmbiguousMatchError struct {
+	Candidates []Candidate
+}
+
+func (e *AmbiguousMatchError) Error() string {
+	return fmt.Sprintf("mercado pago: %d pagos coinciden con el comprobante", len(e.Candidates))
 }
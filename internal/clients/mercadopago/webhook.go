@@ -0,0 +1,119 @@
+package mercadopago
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Estados de un pago que ya no cuenta como cobrado.
+const (
+	StatusRefunded    = "refunded"
+	StatusChargedBack = "charged_back"
+	StatusCancelled   = "cancelled"
+)
+
+var ErrInvalidSignature = errors.New("mercado pago: firma de webhook inválida")
+
+// IsReversedStatus indica si el estado corresponde a un pago devuelto,
+// desconocido por el titular o cancelado.
+func IsReversedStatus(status string) bool {
+	switch status {
+	case StatusRefunded, StatusChargedBack, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// WebhookNotification es el cuerpo de las notificaciones de Mercado Pago.
+// Type es "payment" o "chargebacks"; Data.ID es el id del recurso.
+type WebhookNotification struct {
+	Type   string `json:"type"`
+	Action string `json:"action"`
+	Data   struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// VerifyWebhookSignature valida el header x-signature ("ts=...,v1=...")
+// según el manifiesto documentado por Mercado Pago:
+// "id:{data.id};request-id:{x-request-id};ts:{ts};".
+func VerifyWebhookSignature(secret, xSignature, xRequestID, dataID string) error {
+	var ts, v1 string
+	for _, part := range strings.Split(xSignature, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch k {
+		case "ts":
+			ts = v
+		case "v1":
+			v1 = v
+		}
+	}
+	if secret == "" || ts == "" || v1 == "" {
+		return ErrInvalidSignature
+	}
+
+	var manifest strings.Builder
+	if dataID != "" {
+		// Mercado Pago firma el id en minúsculas cuando es alfanumérico.
+		fmt.Fprintf(&manifest, "id:%s;", strings.ToLower(dataID))
+	}
+	if xRequestID != "" {
+		fmt.Fprintf(&manifest, "request-id:%s;", xRequestID)
+	}
+	fmt.Fprintf(&manifest, "ts:%s;", ts)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(manifest.String()))
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(v1))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// GetChargebackPayments devuelve los ids de pago afectados por un contracargo.
+func (c *Client) GetChargebackPayments(ctx context.Context, chargebackID string) ([]int64, error) {
+	url := fmt.Sprintf("https://api.mercadopago.com/v1/chargebacks/%s", chargebackID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("mercado pago chargeback error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var chargeback struct {
+		Payments []int64 `json:"payments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&chargeback); err != nil {
+		return nil, fmt.Errorf("error decoding chargeback: %w", err)
+	}
+
+	return chargeback.Payments, nil
+}
@@ -0,0 +1,56 @@
+package mercadopago
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	t.Parallel()
+
+	const secret = "s3cr3t"
+	sign := func(manifest string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(manifest))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	valid := "ts=1704908010,v1=" + sign("id:123456;request-id:req-1;ts:1704908010;")
+
+	if err := VerifyWebhookSignature(secret, valid, "req-1", "123456"); err != nil {
+		t.Fatalf("firma válida rechazada: %v", err)
+	}
+
+	cases := map[string]struct {
+		secret, header, requestID, dataID string
+	}{
+		"otro id":         {secret, valid, "req-1", "999"},
+		"otro request id": {secret, valid, "req-2", "123456"},
+		"otro secreto":    {"otro", valid, "req-1", "123456"},
+		"sin v1":          {secret, "ts=1704908010", "req-1", "123456"},
+		"sin secreto":     {"", valid, "req-1", "123456"},
+	}
+	for name, tc := range cases {
+		if err := VerifyWebhookSignature(tc.secret, tc.header, tc.requestID, tc.dataID); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: err = %v, quería ErrInvalidSignature", name, err)
+		}
+	}
+}
+
+func TestIsReversedStatus(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"refunded", "charged_back", "cancelled"} {
+		if !IsReversedStatus(s) {
+			t.Errorf("%s debería contar como revertido", s)
+		}
+	}
+	for _, s := range []string{"approved", "in_mediation", "pending"} {
+		if IsReversedStatus(s) {
+			t.Errorf("%s no debería contar como revertido", s)
+		}
+	}
+}
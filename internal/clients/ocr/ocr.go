@@ -0,0 +1,29 @@
+// Package ocr extrae texto de imágenes de comprobantes.
+package ocr
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("ocr: formato de imagen no soportado")
+	ErrNoText           = errors.New("ocr: no se encontró texto en la imagen")
+)
+
+// Line es una línea reconocida con su confianza promedio (0 a 1).
+type Line struct {
+	Text       string
+	Confidence float64
+}
+
+// Result es el texto reconocido, línea por línea.
+type Result struct {
+	Lines []Line
+}
+
+// Engine reconoce el texto de una imagen. Permite cambiar el motor local por
+// un servicio externo sin tocar el parseo.
+type Engine interface {
+	Recognize(ctx context.Context, image []byte, contentType string) (*Result, error)
+}
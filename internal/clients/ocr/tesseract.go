@@ -0,0 +1,102 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// TesseractEngine ejecuta el binario tesseract local, sin depender de red.
+type TesseractEngine struct {
+	binary string
+	lang   string
+}
+
+// NewTesseractEngine usa "tesseract" del PATH si binary está vacío.
+func NewTesseractEngine(binary, lang string) *TesseractEngine {
+	if binary == "" {
+		binary = "tesseract"
+	}
+	if lang == "" {
+		lang = "spa"
+	}
+	return &TesseractEngine{binary: binary, lang: lang}
+}
+
+func (e *TesseractEngine) Recognize(ctx context.Context, image []byte, contentType string) (*Result, error) {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/webp":
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedImage, contentType)
+	}
+
+	// stdin/stdout evitan archivos temporales; tsv trae la confianza por palabra.
+	cmd := exec.CommandContext(ctx, e.binary, "stdin", "stdout", "-l", e.lang, "tsv")
+	cmd.Stdin = bytes.NewReader(image)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ocr: tesseract: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	res := parseTSV(stdout.String())
+	if len(res.Lines) == 0 {
+		return nil, ErrNoText
+	}
+	return res, nil
+}
+
+// parseTSV arma líneas a partir de la salida tsv de tesseract
+// (level, page, block, par, line, word, left, top, width, height, conf, text).
+func parseTSV(out string) *Result {
+	type lineAcc struct {
+		words []string
+		conf  float64
+	}
+
+	var (
+		order []string
+		lines = map[string]*lineAcc{}
+	)
+
+	for i, row := range strings.Split(out, "\n") {
+		if i == 0 {
+			continue // encabezado
+		}
+		cols := strings.Split(row, "\t")
+		if len(cols) < 12 || cols[0] != "5" {
+			continue
+		}
+		text := strings.TrimSpace(cols[11])
+		conf, err := strconv.ParseFloat(cols[10], 64)
+		if text == "" || err != nil || conf < 0 {
+			continue
+		}
+
+		key := cols[1] + "." + cols[2] + "." + cols[3] + "." + cols[4]
+		acc, ok := lines[key]
+		if !ok {
+			acc = &lineAcc{}
+			lines[key] = acc
+			order = append(order, key)
+		}
+		acc.words = append(acc.words, text)
+		acc.conf += conf
+	}
+
+	res := &Result{}
+	for _, key := range order {
+		acc := lines[key]
+		res.Lines = append(res.Lines, Line{
+			Text:       strings.Join(acc.words, " "),
+			Confidence: acc.conf / float64(len(acc.words)) / 100,
+		})
+	}
+	return res
+}
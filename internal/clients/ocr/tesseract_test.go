@@ -0,0 +1,24 @@
+package ocr
+
+import "testing"
+
+func TestParseTSV(t *testing.T) {
+	t.Parallel()
+
+	out := "level\tpage_num\tblock_num\tpar_num\tline_num\tword_num\tleft\ttop\twidth\theight\tconf\ttext\n" +
+		"4\t1\t1\t1\t1\t0\t0\t0\t0\t0\t-1\t\n" +
+		"5\t1\t1\t1\t1\t1\t0\t0\t0\t0\t90\tTotal\n" +
+		"5\t1\t1\t1\t1\t2\t0\t0\t0\t0\t70\t$1.500\n" +
+		"5\t1\t1\t1\t2\t1\t0\t0\t0\t0\t95\t18/11/2025\n"
+
+	res := parseTSV(out)
+	if len(res.Lines) != 2 {
+		t.Fatalf("lines = %d, want 2", len(res.Lines))
+	}
+	if res.Lines[0].Text != "Total $1.500" {
+		t.Fatalf("line 0 = %q", res.Lines[0].Text)
+	}
+	if got := res.Lines[0].Confidence; got < 0.799 || got > 0.801 {
+		t.Fatalf("line 0 confidence = %v, want 0.8", got)
+	}
+}
@@ -0,0 +1,170 @@
+package sports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// FootballData implementa Client sobre la API v4 de football-data.org.
+type FootballData struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+func NewFootballData(baseURL, token string) *FootballData {
+	if baseURL == "" {
+		baseURL = "https://api.football-data.org"
+	}
+	return &FootballData{
+		baseURL: baseURL,
+		token:   token,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (c *FootballData) TeamMatches(ctx context.Context, teamID int64, competition string) ([]Match, error) {
+	q := url.Values{}
+	if competition != "" {
+		q.Set("competitions", competition)
+	}
+
+	var parsed fdMatchList
+	if err := c.get(ctx, "/v4/teams/"+strconv.FormatInt(teamID, 10)+"/matches", q, &parsed); err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0, len(parsed.Matches))
+	for _, m := range parsed.Matches {
+		matches = append(matches, m.toMatch())
+	}
+	return matches, nil
+}
+
+func (c *FootballData) Match(ctx context.Context, externalID string) (*Match, error) {
+	var parsed fdMatch
+	if err := c.get(ctx, "/v4/matches/"+url.PathEscape(externalID), nil, &parsed); err != nil {
+		return nil, err
+	}
+
+	m := parsed.toMatch()
+	return &m, nil
+}
+
+func (c *FootballData) get(ctx context.Context, path string, q url.Values, out any) error {
+	u := c.baseURL + path
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("crear request: %w", err)
+	}
+	req.Header.Set("X-Auth-Token", c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("llamando %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("leyendo body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrMatchNotFound
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("status no exitoso: %s, body: %s", resp.Status, string(body))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("parseando json: %w", err)
+	}
+	return nil
+}
+
+type fdMatchList struct {
+	Matches []fdMatch `json:"matches"`
+}
+
+type fdTeam struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type fdScorePair struct {
+	Home *int `json:"home"`
+	Away *int `json:"away"`
+}
+
+type fdMatch struct {
+	ID       int64     `json:"id"`
+	UTCDate  time.Time `json:"utcDate"`
+	Status   string    `json:"status"`
+	Stage    string    `json:"stage"`
+	HomeTeam fdTeam    `json:"homeTeam"`
+	AwayTeam fdTeam    `json:"awayTeam"`
+	Score    struct {
+		Duration    string       `json:"duration"`
+		FullTime    fdScorePair  `json:"fullTime"`
+		RegularTime *fdScorePair `json:"regularTime"`
+	} `json:"score"`
+}
+
+func (m fdMatch) toMatch() Match {
+	out := Match{
+		ExternalID: strconv.FormatInt(m.ID, 10),
+		Stage:      m.Stage,
+		HomeTeam:   Team{ID: m.HomeTeam.ID, Name: m.HomeTeam.Name},
+		AwayTeam:   Team{ID: m.AwayTeam.ID, Name: m.AwayTeam.Name},
+		KickoffAt:  m.UTCDate,
+		Status:     normalizeStatus(m.Status),
+		Duration:   m.Score.Duration,
+	}
+	if out.Duration == "" {
+		out.Duration = DurationRegular
+	}
+
+	if out.Status != StatusFinished {
+		return out
+	}
+
+	// Con alargue o penales fullTime incluye el tiempo extra; los 90 minutos
+	// vienen aparte en regularTime.
+	ninety := &m.Score.FullTime
+	if out.Duration != DurationRegular {
+		ninety = m.Score.RegularTime
+	}
+	if ninety != nil && ninety.Home != nil && ninety.Away != nil {
+		out.RegularTime = &Score{Home: *ninety.Home, Away: *ninety.Away}
+	}
+	return out
+}
+
+func normalizeStatus(s string) string {
+	switch s {
+	case "IN_PLAY", "PAUSED", "LIVE":
+		return StatusLive
+	case "FINISHED", "AWARDED":
+		return StatusFinished
+	case "POSTPONED", "SUSPENDED":
+		return StatusPostponed
+	case "CANCELLED":
+		return StatusCancelled
+	default:
+		return StatusScheduled
+	}
+}
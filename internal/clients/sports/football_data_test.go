@@ -0,0 +1,91 @@
+package sports
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fixtureServer responde con las respuestas grabadas en testdata.
+func fixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	routes := map[string]string{
+		"/v4/teams/762/matches": "testdata/team_matches.json",
+		"/v4/matches/500230":    "testdata/match_live.json",
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		file, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		http.ServeFile(w, r, file)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFootballDataTeamMatches(t *testing.T) {
+	t.Parallel()
+
+	c := NewFootballData(fixtureServer(t).URL, "test-token")
+
+	matches, err := c.TeamMatches(context.Background(), 762, "WC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 3 {
+		t.Fatalf("matches = %d, want 3", len(matches))
+	}
+
+	group := matches[0]
+	if group.Status != StatusFinished || group.RegularTime == nil || *group.RegularTime != (Score{Home: 2, Away: 0}) {
+		t.Errorf("fase de grupos: %+v", group)
+	}
+
+	// Con penales se toma el marcador de los 90 minutos, no el final.
+	ko := matches[1]
+	if ko.Duration != DurationPenalties || ko.RegularTime == nil || *ko.RegularTime != (Score{Home: 1, Away: 1}) {
+		t.Errorf("octavos: %+v regular=%+v", ko, ko.RegularTime)
+	}
+	if ko.AwayTeam.ID != 762 || ko.ExternalID != "500177" {
+		t.Errorf("octavos: %+v", ko)
+	}
+
+	upcoming := matches[2]
+	if upcoming.Status != StatusScheduled || upcoming.RegularTime != nil {
+		t.Errorf("cuartos: %+v", upcoming)
+	}
+}
+
+func TestFootballDataMatch(t *testing.T) {
+	t.Parallel()
+
+	c := NewFootballData(fixtureServer(t).URL, "test-token")
+
+	m, err := c.Match(context.Background(), "500230")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Status != StatusLive || m.RegularTime != nil {
+		t.Errorf("partido en juego: %+v", m)
+	}
+
+	if _, err := c.Match(context.Background(), "1"); !errors.Is(err, ErrMatchNotFound) {
+		t.Errorf("err = %v, want ErrMatchNotFound", err)
+	}
+
+	bad := NewFootballData(c.baseURL, "otro")
+	if _, err := bad.Match(context.Background(), "500230"); err == nil {
+		t.Error("token inválido debería fallar")
+	}
+}
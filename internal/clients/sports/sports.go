@@ -0,0 +1,56 @@
+package sports
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Estados de partido normalizados.
+const (
+	StatusScheduled = "SCHEDULED"
+	StatusLive      = "LIVE"
+	StatusFinished  = "FINISHED"
+	StatusPostponed = "POSTPONED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Cómo terminó un partido definido.
+const (
+	DurationRegular   = "REGULAR"
+	DurationExtraTime = "EXTRA_TIME"
+	DurationPenalties = "PENALTY_SHOOTOUT"
+)
+
+var ErrMatchNotFound = errors.New("sports: partido no encontrado")
+
+type Team struct {
+	ID   int64
+	Name string
+}
+
+type Score struct {
+	Home int
+	Away int
+}
+
+// Match es un partido tal como lo informa el proveedor. RegularTime es el
+// marcador a los 90 minutos y solo viene cuando el partido terminó.
+type Match struct {
+	ExternalID  string
+	Stage       string
+	HomeTeam    Team
+	AwayTeam    Team
+	KickoffAt   time.Time
+	Status      string
+	Duration    string
+	RegularTime *Score
+}
+
+// Client obtiene fixtures y resultados de un proveedor de datos deportivos.
+type Client interface {
+	// TeamMatches devuelve los partidos de un equipo en una competencia.
+	TeamMatches(ctx context.Context, teamID int64, competition string) ([]Match, error)
+	// Match devuelve un partido por su id en el proveedor.
+	Match(ctx context.Context, externalID string) (*Match, error)
+}
@@ -0,0 +1,5 @@
+package loginevent
+
+import "errors"
+
+var ErrInternal = errors.New("loginevent: error interno de persistencia")
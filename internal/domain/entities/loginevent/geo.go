@@ -0,0 +1,30 @@
+package loginevent
+
+import (
+	"net/http"
+	"strings"
+)
+
+// GeoResolver obtiene el país (ISO 3166-1 alpha-2) desde el que llega un request.
+type GeoResolver interface {
+	Country(r *http.Request) string
+}
+
+// HeaderGeoResolver lee el país de un header que agrega el proxy o CDN
+// (por ejemplo CF-IPCountry). Sin header configurado no resuelve nada.
+type HeaderGeoResolver struct {
+	Header string
+}
+
+func (g HeaderGeoResolver) Country(r *http.Request) string {
+	if g.Header == "" {
+		return ""
+	}
+
+	country := strings.ToUpper(strings.TrimSpace(r.Header.Get(g.Header)))
+	// XX y T1 son los valores que usan los CDNs para "desconocido" y Tor.
+	if len(country) != 2 || country == "XX" || country == "T1" {
+		return ""
+	}
+	return country
+}
@@ -0,0 +1,29 @@
+package loginevent
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MethodPassword  = "PASSWORD"
+	MethodOAuth     = "OAUTH"
+	MethodEmailCode = "EMAIL_CODE"
+	MethodMFA       = "MFA"
+)
+
+// LoginEvent registra cada intento de login, exitoso o no. Los fallidos con un
+// email inexistente quedan sin UserID pero cuentan igual para el bloqueo por IP.
+type LoginEvent struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID    *uuid.UUID `gorm:"type:uuid;index:idx_login_events_user_created"`
+	Email     string     `gorm:"type:varchar(255)"`
+	IP        string     `gorm:"type:varchar(64);not null;index:idx_login_events_ip_created"`
+	UserAgent string     `gorm:"type:varchar(512)"`
+	DeviceKey string     `gorm:"type:varchar(64);index"`
+	Country   string     `gorm:"type:varchar(2)"`
+	Method    string     `gorm:"type:varchar(20);not null"`
+	Success   bool       `gorm:"not null;default:false"`
+	CreatedAt time.Time  `gorm:"index:idx_login_events_user_created;index:idx_login_events_ip_created"`
+}
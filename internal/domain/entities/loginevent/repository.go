@@ -0,0 +1,103 @@
+package loginevent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// WithTx devuelve un nuevo Repository que usa la transacción que le pasamos
+func (r *Repository) WithTx(tx *gorm.DB) *Repository {
+	return &Repository{db: tx}
+}
+
+func (r *Repository) Create(ctx context.Context, event *LoginEvent) error {
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		return mapLoginEventRepoErr(ctx, "create", err)
+	}
+	return nil
+}
+
+// FailureStatsByIP devuelve la cantidad de fallos desde una IP en la ventana y
+// el momento del último.
+func (r *Repository) FailureStatsByIP(ctx context.Context, ip string, since time.Time) (int64, time.Time, error) {
+	var row struct {
+		Count int64
+		Last  *time.Time
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&LoginEvent{}).
+		Select("COUNT(*) AS count, MAX(created_at) AS last").
+		Where("ip = ? AND success = ? AND created_at > ?", ip, false, since).
+		Scan(&row).Error
+
+	if err != nil {
+		return 0, time.Time{}, mapLoginEventRepoErr(ctx, "failure stats by ip", err)
+	}
+
+	if row.Last == nil {
+		return row.Count, time.Time{}, nil
+	}
+	return row.Count, *row.Last, nil
+}
+
+func (r *Repository) CountSuccessByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&LoginEvent{}).
+		Where("user_id = ? AND success = ?", userID, true).
+		Count(&count).Error
+
+	if err != nil {
+		return 0, mapLoginEventRepoErr(ctx, "count success by user", err)
+	}
+	return count, nil
+}
+
+// HasSuccessWith indica si el usuario ya entró alguna vez con ese valor en la
+// columna indicada (device_key o country).
+func (r *Repository) HasSuccessWith(ctx context.Context, userID uuid.UUID, column, value string) (bool, error) {
+	if column != "device_key" && column != "country" {
+		return false, fmt.Errorf("loginevent: has success with: columna no permitida %q", column)
+	}
+
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&LoginEvent{}).
+		Where("user_id = ? AND success = ?", userID, true).
+		Where(column+" = ?", value).
+		Limit(1).
+		Count(&count).Error
+
+	if err != nil {
+		return false, mapLoginEventRepoErr(ctx, "has success with "+column, err)
+	}
+	return count > 0, nil
+}
+
+func mapLoginEventRepoErr(ctx context.Context, action string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("loginevent: %s: %w", action, err)
+	}
+	slog.ErrorContext(ctx, "loginevent repository", "action", action, "error", err)
+	return fmt.Errorf("loginevent: %s: %w", action, ErrInternal)
+}
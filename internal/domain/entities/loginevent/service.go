@@ -0,0 +1,164 @@
+package loginevent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ipWindow    = time.Hour
+	ipThreshold = 10
+	ipBaseDelay = 30 * time.Second
+	ipMaxDelay  = time.Hour
+)
+
+// Attempt describe un intento de login tal como lo ve el handler.
+type Attempt struct {
+	UserID    *uuid.UUID
+	Email     string
+	IP        string
+	UserAgent string
+	DeviceID  string
+	Country   string
+	Method    string
+}
+
+// Anomaly indica qué tiene de nuevo un login exitoso respecto al historial.
+type Anomaly struct {
+	NewDevice  bool
+	NewCountry bool
+}
+
+func (a Anomaly) Any() bool {
+	return a.NewDevice || a.NewCountry
+}
+
+type Service struct {
+	repo *Repository
+	now  func() time.Time
+}
+
+func NewService(repo *Repository) *Service {
+	return &Service{repo: repo, now: time.Now}
+}
+
+func (s *Service) RecordFailure(ctx context.Context, a Attempt) error {
+	if err := s.repo.Create(ctx, s.toEvent(a, false)); err != nil {
+		return fmt.Errorf("loginevent service: record failure: %w", err)
+	}
+	return nil
+}
+
+// RecordSuccess guarda el login y avisa si viene de un dispositivo o país que
+// el usuario no usó antes. El primer login de una cuenta nunca es anómalo.
+func (s *Service) RecordSuccess(ctx context.Context, a Attempt) (Anomaly, error) {
+	var anomaly Anomaly
+	event := s.toEvent(a, true)
+
+	if a.UserID != nil {
+		previous, err := s.repo.CountSuccessByUser(ctx, *a.UserID)
+		if err != nil {
+			return anomaly, fmt.Errorf("loginevent service: record success: %w", err)
+		}
+
+		if previous > 0 {
+			known, err := s.repo.HasSuccessWith(ctx, *a.UserID, "device_key", event.DeviceKey)
+			if err != nil {
+				return anomaly, fmt.Errorf("loginevent service: record success: %w", err)
+			}
+			anomaly.NewDevice = !known
+
+			if event.Country != "" {
+				known, err := s.repo.HasSuccessWith(ctx, *a.UserID, "country", event.Country)
+				if err != nil {
+					return anomaly, fmt.Errorf("loginevent service: record success: %w", err)
+				}
+				anomaly.NewCountry = !known
+			}
+		}
+	}
+
+	if err := s.repo.Create(ctx, event); err != nil {
+		return anomaly, fmt.Errorf("loginevent service: record success: %w", err)
+	}
+
+	return anomaly, nil
+}
+
+// IPLockout devuelve cuánto falta para que la IP pueda volver a intentar.
+// Cero significa que no está bloqueada.
+func (s *Service) IPLockout(ctx context.Context, ip string) (time.Duration, error) {
+	now := s.now()
+
+	count, last, err := s.repo.FailureStatsByIP(ctx, ip, now.Add(-ipWindow))
+	if err != nil {
+		return 0, fmt.Errorf("loginevent service: ip lockout: %w", err)
+	}
+
+	delay := Backoff(int(count), ipThreshold, ipBaseDelay, ipMaxDelay)
+	if delay == 0 {
+		return 0, nil
+	}
+
+	if remaining := last.Add(delay).Sub(now); remaining > 0 {
+		return remaining, nil
+	}
+	return 0, nil
+}
+
+func (s *Service) toEvent(a Attempt, success bool) *LoginEvent {
+	return &LoginEvent{
+		UserID:    a.UserID,
+		Email:     strings.ToLower(strings.TrimSpace(a.Email)),
+		IP:        a.IP,
+		UserAgent: truncate(a.UserAgent, 512),
+		DeviceKey: DeviceKey(a.DeviceID, a.UserAgent),
+		Country:   a.Country,
+		Method:    a.Method,
+		Success:   success,
+		CreatedAt: s.now(),
+	}
+}
+
+// Backoff calcula una espera exponencial: 0 por debajo del umbral, base al
+// alcanzarlo y el doble por cada fallo extra, con tope en max.
+func Backoff(failures, threshold int, base, max time.Duration) time.Duration {
+	if failures < threshold {
+		return 0
+	}
+
+	d := base
+	for i := threshold; i < failures && d < max; i++ {
+		d *= 2
+	}
+
+	if d > max {
+		return max
+	}
+	return d
+}
+
+// DeviceKey identifica el dispositivo. La app envía un ID propio; si no está,
+// se usa el user agent como aproximación.
+func DeviceKey(deviceID, userAgent string) string {
+	source := strings.TrimSpace(deviceID)
+	if source == "" {
+		source = strings.TrimSpace(userAgent)
+	}
+
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:16])
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
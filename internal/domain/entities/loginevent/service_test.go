@@ -0,0 +1,68 @@
+package loginevent
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{4, 0},
+		{5, time.Minute},
+		{6, 2 * time.Minute},
+		{8, 8 * time.Minute},
+		{30, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := Backoff(tt.failures, 5, time.Minute, time.Hour); got != tt.want {
+			t.Fatalf("Backoff(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestDeviceKey(t *testing.T) {
+	t.Parallel()
+
+	if DeviceKey("abc", "ua-1") != DeviceKey("abc", "ua-2") {
+		t.Fatal("DeviceKey should prefer the device id over the user agent")
+	}
+	if DeviceKey("", "ua-1") == DeviceKey("", "ua-2") {
+		t.Fatal("DeviceKey should fall back to the user agent")
+	}
+	if got := len(DeviceKey("", "")); got != 32 {
+		t.Fatalf("len(DeviceKey) = %d, want 32", got)
+	}
+}
+
+func TestHeaderGeoResolver(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		want   string
+	}{
+		{"sin header configurado", "", "AR", ""},
+		{"país válido", "CF-IPCountry", "ar", "AR"},
+		{"desconocido", "CF-IPCountry", "XX", ""},
+		{"valor inválido", "CF-IPCountry", "ARG", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/login", nil)
+		req.Header.Set("CF-IPCountry", tt.value)
+
+		if got := (HeaderGeoResolver{Header: tt.header}).Country(req); got != tt.want {
+			t.Fatalf("%s: Country() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+package mppayment
+
+import "errors"
+
+var ErrInternal = errors.New("mppayment: error interno de persistencia")
@@ -0,0 +1,68 @@
+package mppayment
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/mercadopago"
+)
+
+// Payment es la copia local de un pago de Mercado Pago. Las columnas sirven
+// para filtrar; Raw guarda el pago completo tal como lo devolvió la API.
+type Payment struct {
+	ID                int64      `gorm:"primaryKey;autoIncrement:false"`
+	Status            string     `gorm:"type:varchar(30);not null;index"`
+	TransactionAmount float64    `gorm:"not null"`
+	DateCreated       time.Time  `gorm:"not null;index"`
+	DateApproved      *time.Time `gorm:"index"`
+	DateLastUpdated   time.Time  `gorm:"not null;index"`
+	Raw               string     `gorm:"type:jsonb;not null"`
+	SyncedAt          time.Time  `gorm:"not null"`
+}
+
+func (Payment) TableName() string {
+	return "mp_payments"
+}
+
+// SyncState guarda hasta dónde se sincronizó el espejo.
+type SyncState struct {
+	Name      string    `gorm:"primaryKey;type:varchar(50)"`
+	Cursor    time.Time `gorm:"not null"`
+	UpdatedAt time.Time
+}
+
+func (SyncState) TableName() string {
+	return "mp_sync_state"
+}
+
+func fromAPI(p *mercadopago.MercadoPagoPayment, now time.Time) (Payment, error) {
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return Payment{}, fmt.Errorf("mppayment: marshal %d: %w", p.ID, err)
+	}
+
+	updated := p.DateCreated
+	if p.DateLastUpdated != nil {
+		updated = *p.DateLastUpdated
+	}
+
+	return Payment{
+		ID:                p.ID,
+		Status:            p.Status,
+		TransactionAmount: p.TransactionAmount,
+		DateCreated:       p.DateCreated,
+		DateApproved:      p.DateApproved,
+		DateLastUpdated:   updated,
+		Raw:               string(raw),
+		SyncedAt:          now,
+	}, nil
+}
+
+func (p *Payment) toAPI() (*mercadopago.MercadoPagoPayment, error) {
+	var out mercadopago.MercadoPagoPayment
+	if err := json.Unmarshal([]byte(p.Raw), &out); err != nil {
+		return nil, fmt.Errorf("mppayment: unmarshal %d: %w", p.ID, err)
+	}
+	return &out, nil
+}
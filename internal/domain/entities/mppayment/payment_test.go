@@ -0,0 +1,39 @@
+package mppayment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/mercadopago"
+)
+
+func TestPayment_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2025, 11, 18, 12, 11, 0, 0, time.UTC)
+	last4 := "4321"
+	in := &mercadopago.MercadoPagoPayment{
+		ID:                123,
+		Status:            "approved",
+		DateCreated:       created,
+		TransactionAmount: 1500,
+		Card:              mercadopago.CardInfo{LastFourDigits: &last4},
+	}
+
+	p, err := fromAPI(in, created)
+	if err != nil {
+		t.Fatalf("fromAPI: %v", err)
+	}
+	// Sin date_last_updated se usa la fecha de creación como cursor.
+	if !p.DateLastUpdated.Equal(created) {
+		t.Fatalf("DateLastUpdated = %v, want %v", p.DateLastUpdated, created)
+	}
+
+	out, err := p.toAPI()
+	if err != nil {
+		t.Fatalf("toAPI: %v", err)
+	}
+	if out.ID != 123 || out.Card.LastFourDigits == nil || *out.Card.LastFourDigits != "4321" {
+		t.Fatalf("round trip = %+v", out)
+	}
+}
@@ -0,0 +1,126 @@
+package mppayment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// syncStateName identifica el cursor del espejo de pagos.
+const syncStateName = "payments"
+
+type Repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// WithTx devuelve un nuevo Repository que usa la transacción que le pasamos
+func (r *Repository) WithTx(tx *gorm.DB) *Repository {
+	return &Repository{db: tx}
+}
+
+func (r *Repository) DB() *gorm.DB {
+	return r.db
+}
+
+// Upsert inserta o actualiza los pagos por ID.
+func (r *Repository) Upsert(ctx context.Context, payments []Payment) error {
+	if len(payments) == 0 {
+		return nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "transaction_amount", "date_approved", "date_last_updated", "raw", "synced_at"}),
+		}).
+		Create(&payments).Error
+	if err != nil {
+		return mapMPPaymentRepoErr(ctx, "upsert", err)
+	}
+	return nil
+}
+
+// GetByID devuelve nil si el pago no está en el espejo.
+func (r *Repository) GetByID(ctx context.Context, id int64) (*Payment, error) {
+	var p Payment
+	err := r.db.WithContext(ctx).First(&p, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapMPPaymentRepoErr(ctx, "get by id", err)
+	}
+	return &p, nil
+}
+
+// FindApprovedCreatedBetween lista pagos aprobados creados en la ventana.
+func (r *Repository) FindApprovedCreatedBetween(ctx context.Context, begin, end time.Time) ([]Payment, error) {
+	var out []Payment
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND date_created BETWEEN ? AND ?", "approved", begin, end).
+		Order("date_created DESC").
+		Find(&out).Error
+	if err != nil {
+		return nil, mapMPPaymentRepoErr(ctx, "find approved created between", err)
+	}
+	return out, nil
+}
+
+// FindByStatusUpdatedSince devuelve los pagos en alguno de los estados dados
+// que cambiaron desde since.
+func (r *Repository) FindByStatusUpdatedSince(ctx context.Context, statuses []string, since time.Time) ([]Payment, error) {
+	var out []Payment
+	err := r.db.WithContext(ctx).
+		Where("status IN ? AND date_last_updated >= ?", statuses, since).
+		Order("date_last_updated ASC").
+		Find(&out).Error
+	if err != nil {
+		return nil, mapMPPaymentRepoErr(ctx, "find by status updated since", err)
+	}
+	return out, nil
+}
+
+// Cursor devuelve el último date_last_updated sincronizado; ok es false si
+// el espejo nunca corrió.
+func (r *Repository) Cursor(ctx context.Context) (time.Time, bool, error) {
+	var st SyncState
+	err := r.db.WithContext(ctx).First(&st, "name = ?", syncStateName).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, mapMPPaymentRepoErr(ctx, "get cursor", err)
+	}
+	return st.Cursor, true, nil
+}
+
+func (r *Repository) SetCursor(ctx context.Context, cursor time.Time) error {
+	st := SyncState{Name: syncStateName, Cursor: cursor}
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"cursor", "updated_at"}),
+		}).
+		Create(&st).Error
+	if err != nil {
+		return mapMPPaymentRepoErr(ctx, "set cursor", err)
+	}
+	return nil
+}
+
+func mapMPPaymentRepoErr(ctx context.Context, action string, err error) error {
+	if err == nil {
+		return nil
+	}
+	slog.ErrorContext(ctx, "mppayment repository", "action", action, "error", err)
+	return fmt.Errorf("mppayment: %s: %w", action, ErrInternal)
+}
@@ -0,0 +1,186 @@
+package mppayment
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/mercadopago"
+)
+
+const (
+	// initialLookback es cuánto hacia atrás copia la primera sincronización.
+	initialLookback = 7 * 24 * time.Hour
+	syncPageSize    = 50
+	// syncMaxPages acota cada corrida; la siguiente sigue desde el cursor.
+	syncMaxPages = 20
+)
+
+// Service mantiene el espejo local de pagos y responde las consultas de
+// conciliación leyendo primero de él. Si el espejo no tiene el dato cae a
+// la API de Mercado Pago.
+type Service struct {
+	repo *Repository
+	api  *mercadopago.Client
+	now  func() time.Time
+}
+
+func NewService(repo *Repository, api *mercadopago.Client) *Service {
+	return &Service{repo: repo, api: api, now: time.Now}
+}
+
+// Sync copia los pagos modificados desde el último cursor y devuelve cuántos
+// se guardaron.
+func (s *Service) Sync(ctx context.Context) (int, error) {
+	cursor, ok, err := s.repo.Cursor(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		cursor = s.now().Add(-initialLookback)
+	}
+
+	synced := 0
+	next := cursor
+
+	for page := 0; page < syncMaxPages; page++ {
+		results, total, err := s.api.SearchUpdatedSince(ctx, cursor, page*syncPageSize, syncPageSize)
+		if err != nil {
+			return synced, fmt.Errorf("mppayment: sync: %w", err)
+		}
+
+		now := s.now()
+		batch := make([]Payment, 0, len(results))
+		for i := range results {
+			p, err := fromAPI(&results[i], now)
+			if err != nil {
+				return synced, err
+			}
+			batch = append(batch, p)
+			if p.DateLastUpdated.After(next) {
+				next = p.DateLastUpdated
+			}
+		}
+
+		if err := s.repo.Upsert(ctx, batch); err != nil {
+			return synced, err
+		}
+		synced += len(batch)
+
+		if len(results) < syncPageSize || (page+1)*syncPageSize >= total {
+			break
+		}
+	}
+
+	// El cursor avanza solo después de guardar: si algo falla, la próxima
+	// corrida repite la ventana y el upsert lo hace idempotente.
+	if next.After(cursor) || !ok {
+		if err := s.repo.SetCursor(ctx, next); err != nil {
+			return synced, err
+		}
+	}
+
+	return synced, nil
+}
+
+func (s *Service) ValidatePaymentExists(ctx context.Context, paymentID string) (*mercadopago.PaymentDTO, error) {
+	if id, err := strconv.ParseInt(paymentID, 10, 64); err == nil {
+		local, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			slog.WarnContext(ctx, "espejo de pagos no disponible, uso la API", "error", err)
+		} else if local != nil {
+			p, err := local.toAPI()
+			if err == nil {
+				return p.ToDTO(), nil
+			}
+			slog.WarnContext(ctx, "pago del espejo ilegible, uso la API", "payment_id", id, "error", err)
+		}
+	}
+
+	return s.api.ValidatePaymentExists(ctx, paymentID)
+}
+
+// ReversedSince devuelve los pagos devueltos, contracargados o cancelados
+// que el espejo vio cambiar desde since.
+func (s *Service) ReversedSince(ctx context.Context, since time.Time) ([]Payment, error) {
+	return s.repo.FindByStatusUpdatedSince(ctx, []string{
+		mercadopago.StatusRefunded,
+		mercadopago.StatusChargedBack,
+		mercadopago.StatusCancelled,
+	}, since)
+}
+
+// ApprovedSince devuelve los pagos aprobados que el espejo vio cambiar desde
+// since. Los que no se pueden leer se omiten.
+func (s *Service) ApprovedSince(ctx context.Context, since time.Time) ([]*mercadopago.PaymentDTO, error) {
+	payments, err := s.repo.FindByStatusUpdatedSince(ctx, []string{"approved"}, since)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*mercadopago.PaymentDTO, 0, len(payments))
+	for i := range payments {
+		p, err := payments[i].toAPI()
+		if err != nil {
+			slog.WarnContext(ctx, "pago del espejo ilegible", "payment_id", payments[i].ID, "error", err)
+			continue
+		}
+		out = append(out, p.ToDTO())
+	}
+	return out, nil
+}
+
+func (s *Service) ReconcileOthers(ctx context.Context, req mercadopago.ReconcileOthersRequest) (*mercadopago.ReconcileOthersResult, error) {
+	tLocal, err := mercadopago.ReconcileTime(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, found, err := s.reconcileLocal(ctx, req, tLocal)
+	if found {
+		return result, err
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "conciliación local falló, uso la API", "error", err)
+	}
+
+	return s.api.ReconcileOthers(ctx, req)
+}
+
+// reconcileLocal concilia contra el espejo. found es false cuando el espejo
+// todavía no cubre la ventana o no encontró ningún pago.
+func (s *Service) reconcileLocal(ctx context.Context, req mercadopago.ReconcileOthersRequest, tLocal time.Time) (*mercadopago.ReconcileOthersResult, bool, error) {
+	opts := s.api.Reconcile
+	begin, end := tLocal.Add(-opts.SearchWindow), tLocal.Add(opts.SearchWindow)
+
+	cursor, ok, err := s.repo.Cursor(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok || cursor.Before(end) {
+		return nil, false, nil
+	}
+
+	rows, err := s.repo.FindApprovedCreatedBetween(ctx, begin, end)
+	if err != nil {
+		return nil, false, err
+	}
+
+	payments := make([]mercadopago.MercadoPagoPayment, 0, len(rows))
+	for i := range rows {
+		p, err := rows[i].toAPI()
+		if err != nil {
+			return nil, false, err
+		}
+		payments = append(payments, *p)
+	}
+
+	result, err := mercadopago.MatchPayments(payments, req, tLocal, opts)
+	if err != nil {
+		// Ambigüedad: es una respuesta válida, no un fallo del espejo.
+		return nil, true, err
+	}
+	return result, result != nil, nil
+}
@@ -0,0 +1,243 @@
+package prode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/voucher"
+	"gorm.io/gorm"
+)
+
+// CorrectResult cambia el resultado de un partido ya evaluado. Vuelve a
+// puntuar todas las predicciones y concilia los premios: las que pasan a ser
+// exactas reciben su voucher y las que dejan de serlo pierden el premio. Un
+// voucher ya usado no se puede anular; queda marcado para revisión. En
+// torneos sin premios solo cambian los puntos.
+//
+// Las predicciones se corrigen por tandas, cada una en su transacción como en
+// el settlement. El resultado nuevo y la transición se guardan al final en
+// otra transacción: si una tanda falla el partido conserva el resultado
+// anterior y repetir la corrección retoma sin duplicar premios.
+func (s *Service) CorrectResult(ctx context.Context, matchID uuid.UUID, req RecordResultRequest) (*CorrectionResponse, error) {
+	match, err := s.repo.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if match.Status != MatchStatusEvaluated {
+		return nil, &TransitionError{MatchID: matchID, From: match.Status, To: MatchStatusEvaluated}
+	}
+	if req.HomeGoals < 0 || req.AwayGoals < 0 {
+		return nil, ErrInvalidScore
+	}
+
+	resp := &CorrectionResponse{MatchID: matchID.String()}
+	if *match.HomeGoals == req.HomeGoals && *match.AwayGoals == req.AwayGoals {
+		return resp, nil
+	}
+
+	cursor := uuid.Nil
+	for {
+		preds, err := s.repo.GetPredictionsAfter(ctx, matchID, cursor, s.chunkSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(preds) == 0 {
+			break
+		}
+
+		res, err := s.correctChunk(ctx, match, preds, req.HomeGoals, req.AwayGoals)
+		if err != nil {
+			return nil, fmt.Errorf("prode: corrección %s: %w", matchID, err)
+		}
+
+		cursor = preds[len(preds)-1].ID
+		resp.Rescored += len(preds)
+		resp.Granted += res.granted
+		resp.Revoked += res.revoked
+		resp.Flagged += res.flagged
+
+		for _, v := range res.vouchers {
+			if err := s.sendVoucherEmail(ctx, v.userID, v.voucher); err != nil {
+				slog.ErrorContext(ctx, "error al enviar email del voucher", "match_id", matchID,
+					"user_id", v.userID,
+					"error", err)
+			}
+		}
+	}
+
+	reason := fmt.Sprintf("resultado corregido de %d-%d a %d-%d",
+		*match.HomeGoals, *match.AwayGoals, req.HomeGoals, req.AwayGoals)
+	err = s.repo.CorrectMatchResult(ctx, matchID, *match.HomeGoals, *match.AwayGoals,
+		req.HomeGoals, req.AwayGoals, ActorAdmin, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "resultado corregido", "match_id", matchID,
+		"reason", reason,
+		"rescored", resp.Rescored,
+		"granted", resp.Granted,
+		"revoked", resp.Revoked,
+		"flagged", resp.Flagged)
+
+	return resp, nil
+}
+
+// correctionResult son los conteos de una tanda de corrección ya confirmada.
+type correctionResult struct {
+	granted, revoked, flagged int
+	vouchers                  []awardedVoucher
+}
+
+// correctChunk vuelve a puntuar una tanda de predicciones con el resultado
+// nuevo en una sola transacción: puntos, premios y vouchers se guardan juntos.
+// Igual que en el settlement, cada predicción se puntúa con su última
+// revisión antes del corte y sin revisión no suma.
+func (s *Service) correctChunk(ctx context.Context, match *ProdeMatch, preds []ProdePrediction, homeGoals, awayGoals int) (correctionResult, error) {
+	var res correctionResult
+
+	ids := make([]uuid.UUID, len(preds))
+	for i := range preds {
+		ids[i] = preds[i].ID
+	}
+	cutoff, err := match.CutoffAt()
+	if err != nil {
+		return correctionResult{}, err
+	}
+	locked, err := s.repo.GetLockedRevisions(ctx, ids, cutoff)
+	if err != nil {
+		return correctionResult{}, err
+	}
+
+	err = s.repo.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		txVoucherRepo := s.voucherRepo.WithTx(tx)
+
+		for i := range preds {
+			pred := &preds[i]
+
+			points, exact := 0, false
+			if rev, ok := locked[pred.ID]; ok {
+				pred.HomeGoals, pred.AwayGoals = rev.HomeGoals, rev.AwayGoals
+				points = s.scoring.Points(pred.HomeGoals, pred.AwayGoals, homeGoals, awayGoals)
+				exact = pred.HomeGoals == homeGoals && pred.AwayGoals == awayGoals
+			}
+
+			reward, err := txRepo.GetRewardByPredictionID(ctx, pred.ID)
+			if err != nil {
+				return err
+			}
+
+			if exact && !match.Tournament.GivesVouchers() {
+				pred.Status = PredStatusCorrect
+			} else if exact {
+				pred.Status = PredStatusCorrect
+				granted, v, err := regrantReward(ctx, txRepo, txVoucherRepo, pred, reward)
+				if err != nil {
+					return err
+				}
+				if granted {
+					res.granted++
+				}
+				if v != nil {
+					res.vouchers = append(res.vouchers, awardedVoucher{userID: pred.UserID, voucher: v})
+				}
+			} else {
+				pred.Status = PredStatusIncorrect
+				if reward != nil && reward.Status != RewardStatusRevoked && reward.Status != RewardStatusSkipped {
+					flagged, err := revokeReward(ctx, txRepo, txVoucherRepo, reward, s.clock.Now())
+					if err != nil {
+						return err
+					}
+					res.revoked++
+					if flagged {
+						res.flagged++
+					}
+				}
+			}
+
+			pred.Points = &points
+			if err := txRepo.UpdatePrediction(ctx, pred); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return correctionResult{}, err
+	}
+	return res, nil
+}
+
+// regrantReward crea el premio de una predicción que pasó a ser exacta, o
+// reabre uno anulado, y le asigna un voucher si hay stock. Devuelve true si
+// hubo un premio nuevo que asignar y el voucher asignado, si lo hubo.
+func regrantReward(ctx context.Context, repo *Repository, voucherRepo *voucher.Repository, pred *ProdePrediction, reward *ProdeReward) (bool, *voucher.Voucher, error) {
+	switch {
+	case reward == nil:
+		reward = &ProdeReward{
+			PredictionID: pred.ID,
+			UserID:       pred.UserID,
+		}
+	case reward.Status == RewardStatusRevoked || reward.Status == RewardStatusSkipped:
+		reward.VoucherID = nil
+		reward.FailureReason = ""
+	default:
+		return false, nil, nil
+	}
+
+	reward.Status = RewardStatusPendingInventory
+	v, err := voucherRepo.AssignNextVoucher(ctx, &voucher.VoucherRequest{UserID: pred.UserID})
+	switch {
+	case errors.Is(err, voucher.ErrNoAvailableVouchers):
+		v = nil
+	case err != nil:
+		return false, nil, err
+	default:
+		reward.Status = RewardStatusFulfilled
+		reward.VoucherID = &v.ID
+	}
+
+	if reward.ID == uuid.Nil {
+		err = repo.CreateReward(ctx, reward)
+	} else {
+		err = repo.UpdateReward(ctx, reward)
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	return true, v, nil
+}
+
+// revokeReward anula el premio de una predicción que dejó de ser exacta.
+// Devuelve true si el voucher ya estaba usado y solo se pudo marcar.
+func revokeReward(ctx context.Context, repo *Repository, voucherRepo *voucher.Repository, reward *ProdeReward, now time.Time) (bool, error) {
+	flagged := false
+	if reward.Status == RewardStatusFulfilled && reward.VoucherID != nil {
+		revoked, err := voucherRepo.RevokeOrFlag(ctx, *reward.VoucherID, "prode: resultado corregido", now)
+		if err != nil {
+			return false, err
+		}
+		flagged = !revoked
+	}
+
+	reward.Status = RewardStatusRevoked
+	reward.FailureReason = "resultado del partido corregido"
+	if err := repo.UpdateReward(ctx, reward); err != nil {
+		return false, err
+	}
+	return flagged, nil
+}
+
+// GetMatchTransitions devuelve el historial de estados de un partido.
+func (s *Service) GetMatchTransitions(ctx context.Context, matchID uuid.UUID) ([]ProdeMatchTransition, error) {
+	if _, err := s.repo.GetMatchByID(ctx, matchID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMatchTransitions(ctx, matchID)
+}
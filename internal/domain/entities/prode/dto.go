@@ -1,85 +1,301 @@
 package prode
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TeamResponse es un equipo tal como lo ve el usuario.
+type TeamResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ShortName string `json:"short_name,omitempty"`
+}
 
 // MatchResponse devuelve la información de un partido al usuario.
 type MatchResponse struct {
-	ID               string     `json:"id"`
-	Stage            string     `json:"stage"`
-	Opponent         string     `json:"opponent"`
-	KickoffAt        time.Time  `json:"kickoff_at"`
-	CutoffAt         time.Time  `json:"cutoff_at"`
-	Status           string     `json:"status"`
-	IsOpen           bool       `json:"is_open"`
-	ArgentinaGoals   *int       `json:"argentina_goals,omitempty"`
-	OpponentGoals    *int       `json:"opponent_goals,omitempty"`
-	MyPrediction     *PredictionResponse `json:"my_prediction,omitempty"`
+	ID           string              `json:"id"`
+	TournamentID string              `json:"tournament_id"`
+	Stage        string              `json:"stage"`
+	HomeTeam     TeamResponse        `json:"home_team"`
+	AwayTeam     TeamResponse        `json:"away_team"`
+	KickoffAt    time.Time           `json:"kickoff_at"`
+	CutoffAt     time.Time           `json:"cutoff_at"`
+	Status       string              `json:"status"`
+	IsOpen       bool                `json:"is_open"`
+	HomeGoals    *int                `json:"home_goals,omitempty"`
+	AwayGoals    *int                `json:"away_goals,omitempty"`
+	MyPrediction *PredictionResponse `json:"my_prediction,omitempty"`
 }
 
 // PredictionRequest es el body para crear o editar una predicción.
 type PredictionRequest struct {
-	ArgentinaGoals int `json:"argentina_goals"`
-	OpponentGoals  int `json:"opponent_goals"`
+	HomeGoals int `json:"home_goals"`
+	AwayGoals int `json:"away_goals"`
 }
 
 // PredictionResponse devuelve los datos de la predicción del usuario.
 type PredictionResponse struct {
-	ID             string    `json:"id"`
-	MatchID        string    `json:"match_id"`
-	ArgentinaGoals int       `json:"argentina_goals"`
-	OpponentGoals  int       `json:"opponent_goals"`
-	Status         string    `json:"status"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID        string    `json:"id"`
+	MatchID   string    `json:"match_id"`
+	HomeGoals int       `json:"home_goals"`
+	AwayGoals int       `json:"away_goals"`
+	Status    string    `json:"status"`
+	Points    *int      `json:"points,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PredictionHistoryResponse es el historial de cambios de una predicción.
+type PredictionHistoryResponse struct {
+	MatchID   string                       `json:"match_id"`
+	CutoffAt  time.Time                    `json:"cutoff_at"`
+	Revisions []PredictionRevisionResponse `json:"revisions"`
+}
+
+// PredictionRevisionResponse es una versión de la predicción. Locked marca
+// la última antes del corte, que es la que se evalúa.
+type PredictionRevisionResponse struct {
+	HomeGoals int       `json:"home_goals"`
+	AwayGoals int       `json:"away_goals"`
+	RequestID string    `json:"request_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Locked    bool      `json:"locked"`
+}
+
+// LeaderboardEntry es la posición de un usuario en la tabla general.
+type LeaderboardEntry struct {
+	Rank        int    `json:"rank"`
+	UserID      string `json:"user_id"`
+	Name        string `json:"name"`
+	Points      int    `json:"points"`
+	ExactHits   int    `json:"exact_hits"`
+	Predictions int    `json:"predictions"`
+}
+
+// LeaderboardResponse es una página de la tabla general.
+type LeaderboardResponse struct {
+	Items    []LeaderboardEntry `json:"items"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+	Total    int64              `json:"total"`
+	HasMore  bool               `json:"has_more"`
+}
+
+// CreateLeagueRequest es el body para crear una liga privada. max_members es
+// opcional.
+type CreateLeagueRequest struct {
+	Tournament string `json:"tournament"`
+	Name       string `json:"name"`
+	MaxMembers int    `json:"max_members"`
+}
+
+// JoinLeagueRequest es el body para unirse a una liga con su código.
+type JoinLeagueRequest struct {
+	InviteCode string `json:"invite_code"`
+}
+
+// ReminderPreferenceRequest activa o desactiva los recordatorios del prode.
+type ReminderPreferenceRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// ReminderPreferenceResponse indica si el usuario recibe recordatorios.
+type ReminderPreferenceResponse struct {
+	Enabled bool `json:"enabled"`
+}
+
+// LeagueResponse es una liga privada tal como la ve un miembro.
+type LeagueResponse struct {
+	ID           string    `json:"id"`
+	TournamentID string    `json:"tournament_id"`
+	Name         string    `json:"name"`
+	InviteCode   string    `json:"invite_code"`
+	OwnerID      string    `json:"owner_id"`
+	IsOwner      bool      `json:"is_owner"`
+	MaxMembers   int       `json:"max_members"`
+	MemberCount  int       `json:"member_count"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// LeagueMemberResponse es un miembro de una liga.
+type LeagueMemberResponse struct {
+	UserID   string    `json:"user_id"`
+	Name     string    `json:"name"`
+	IsOwner  bool      `json:"is_owner"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// LeaguePrizeResponse es un premio de liga y, si ya se entregó, su ganador.
+type LeaguePrizeResponse struct {
+	Rank      int        `json:"rank"`
+	Status    string     `json:"status"`
+	UserID    string     `json:"user_id,omitempty"`
+	AwardedAt *time.Time `json:"awarded_at,omitempty"`
+}
+
+// LeagueDetailResponse es una liga con sus miembros y premios.
+type LeagueDetailResponse struct {
+	LeagueResponse
+	Members []LeagueMemberResponse `json:"members"`
+	Prizes  []LeaguePrizeResponse  `json:"prizes"`
 }
 
 // ---- Admin DTOs ----
 
+// CreateTournamentRequest es el body para crear un torneo. Timezone,
+// cutoff_minutes y reward_rule son opcionales.
+type CreateTournamentRequest struct {
+	Slug          string `json:"slug"`
+	Name          string `json:"name"`
+	Timezone      string `json:"timezone"`
+	CutoffMinutes *int   `json:"cutoff_minutes"`
+	RewardRule    string `json:"reward_rule"`
+}
+
+// UpdateTournamentRequest es el body para actualizar un torneo.
+type UpdateTournamentRequest struct {
+	Name          *string `json:"name,omitempty"`
+	Timezone      *string `json:"timezone,omitempty"`
+	CutoffMinutes *int    `json:"cutoff_minutes,omitempty"`
+	RewardRule    *string `json:"reward_rule,omitempty"`
+	IsActive      *bool   `json:"is_active,omitempty"`
+}
+
+// CreateTeamRequest es el body para crear un equipo.
+type CreateTeamRequest struct {
+	Name       string `json:"name"`
+	ShortName  string `json:"short_name"`
+	ExternalID string `json:"external_id"`
+}
+
 // CreateMatchRequest es el body para crear un partido.
 type CreateMatchRequest struct {
-	Stage     string    `json:"stage"`
-	Opponent  string    `json:"opponent"`
-	KickoffAt time.Time `json:"kickoff_at"`
-	IsVisible bool      `json:"is_visible"`
+	TournamentID uuid.UUID `json:"tournament_id"`
+	HomeTeamID   uuid.UUID `json:"home_team_id"`
+	AwayTeamID   uuid.UUID `json:"away_team_id"`
+	Stage        string    `json:"stage"`
+	KickoffAt    time.Time `json:"kickoff_at"`
+	IsVisible    bool      `json:"is_visible"`
 }
 
 // UpdateMatchRequest es el body para actualizar un partido.
 type UpdateMatchRequest struct {
-	Stage     *string    `json:"stage,omitempty"`
-	Opponent  *string    `json:"opponent,omitempty"`
-	KickoffAt *time.Time `json:"kickoff_at,omitempty"`
-	IsVisible *bool      `json:"is_visible,omitempty"`
-	Status    *string    `json:"status,omitempty"`
+	Stage      *string    `json:"stage,omitempty"`
+	HomeTeamID *uuid.UUID `json:"home_team_id,omitempty"`
+	AwayTeamID *uuid.UUID `json:"away_team_id,omitempty"`
+	KickoffAt  *time.Time `json:"kickoff_at,omitempty"`
+	IsVisible  *bool      `json:"is_visible,omitempty"`
+	Status     *string    `json:"status,omitempty"`
 }
 
 // RecordResultRequest es el body para cargar el resultado de un partido.
 type RecordResultRequest struct {
-	ArgentinaGoals int `json:"argentina_goals"`
-	OpponentGoals  int `json:"opponent_goals"`
+	HomeGoals int `json:"home_goals"`
+	AwayGoals int `json:"away_goals"`
 }
 
 // AdminMatchResponse devuelve la info completa de un partido para el admin.
 type AdminMatchResponse struct {
-	ID             string     `json:"id"`
-	Stage          string     `json:"stage"`
-	Opponent       string     `json:"opponent"`
-	KickoffAt      time.Time  `json:"kickoff_at"`
-	CutoffAt       time.Time  `json:"cutoff_at"`
-	Status         string     `json:"status"`
-	IsVisible      bool       `json:"is_visible"`
-	ArgentinaGoals *int       `json:"argentina_goals,omitempty"`
-	OpponentGoals  *int       `json:"opponent_goals,omitempty"`
-	ExternalID     string     `json:"external_id,omitempty"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
-}
-
-// SettlementResponse devuelve el resultado de un settlement.
+	ID                string       `json:"id"`
+	TournamentID      string       `json:"tournament_id"`
+	Stage             string       `json:"stage"`
+	HomeTeam          TeamResponse `json:"home_team"`
+	AwayTeam          TeamResponse `json:"away_team"`
+	KickoffAt         time.Time    `json:"kickoff_at"`
+	CutoffAt          time.Time    `json:"cutoff_at"`
+	Status            string       `json:"status"`
+	IsVisible         bool         `json:"is_visible"`
+	HomeGoals         *int         `json:"home_goals,omitempty"`
+	AwayGoals         *int         `json:"away_goals,omitempty"`
+	ExternalID        string       `json:"external_id,omitempty"`
+	ResultSource      string       `json:"result_source"`
+	ResultConfirmedAt *time.Time   `json:"result_confirmed_at,omitempty"`
+	ExtraTime         bool         `json:"extra_time"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+// SettlementResponse devuelve el resultado de un settlement. Los conteos son
+// de las predicciones evaluadas en esta corrida.
 type SettlementResponse struct {
-	MatchID     string `json:"match_id"`
-	Status      string `json:"status"`
-	TotalPreds  int    `json:"total_predictions"`
-	Correct     int    `json:"correct"`
+	MatchID          string `json:"match_id"`
+	RunID            string `json:"run_id"`
+	Status           string `json:"status"`
+	TotalPreds       int    `json:"total_predictions"`
+	Correct          int    `json:"correct"`
+	Points           int    `json:"points"`
+	PendingInventory int    `json:"pending_inventory"`
+}
+
+// SettlementStatusResponse es el estado del settlement de un partido.
+type SettlementStatusResponse struct {
+	MatchID     string               `json:"match_id"`
+	MatchStatus string               `json:"match_status"`
+	Unsettled   int64                `json:"unsettled"`
+	Runs        []ProdeSettlementRun `json:"runs"`
+}
+
+// CorrectionResponse resume la corrección del resultado de un partido.
+type CorrectionResponse struct {
+	MatchID  string `json:"match_id"`
+	Rescored int    `json:"rescored"`
+	Granted  int    `json:"granted"`
+	Revoked  int    `json:"revoked"`
+	Flagged  int    `json:"flagged"`
+}
+
+// SetLeaguePrizesRequest es el body para definir los puestos premiados de una
+// liga.
+type SetLeaguePrizesRequest struct {
+	Ranks []int `json:"ranks"`
+}
+
+// LeaguePrizeAwardResponse resume la entrega de premios de una liga.
+type LeaguePrizeAwardResponse struct {
+	LeagueID         string `json:"league_id"`
+	Awarded          int    `json:"awarded"`
+	PendingInventory int    `json:"pending_inventory"`
+	Skipped          int    `json:"skipped"`
+}
+
+// MatchStatsResponse es el reporte de predicciones de un partido.
+type MatchStatsResponse struct {
+	MatchID       string                `json:"match_id"`
+	Match         string                `json:"match"`
+	Status        string                `json:"status"`
+	HomeGoals     *int                  `json:"home_goals,omitempty"`
+	AwayGoals     *int                  `json:"away_goals,omitempty"`
+	Total         int64                 `json:"total_predictions"`
+	Correct       int64                 `json:"correct"`
+	Outcomes      OutcomeCounts         `json:"outcomes"`
+	Scores        []ScoreCount          `json:"scores"`
+	Participation []ParticipationBucket `json:"participation"`
+}
+
+// OutcomeCounts reparte las predicciones entre victoria local, empate y
+// victoria visitante.
+type OutcomeCounts struct {
+	HomeWin int64 `json:"home_win"`
+	Draw    int64 `json:"draw"`
+	AwayWin int64 `json:"away_win"`
+}
+
+// ScoreCount es cuántos usuarios predijeron un resultado.
+type ScoreCount struct {
+	HomeGoals int   `json:"home_goals"`
+	AwayGoals int   `json:"away_goals"`
+	Count     int64 `json:"count"`
+	IsResult  bool  `json:"is_result"`
+}
+
+// ParticipationBucket son las predicciones creadas en una hora y el
+// acumulado hasta ese momento.
+type ParticipationBucket struct {
+	Hour       time.Time `json:"hour"`
+	Count      int64     `json:"count"`
+	Cumulative int64     `json:"cumulative"`
 }
 
 // RewardRetryResponse devuelve el resultado de reintentar premios pendientes.
@@ -17,5 +17,23 @@ var (
 	ErrMaintenanceDisabled     = errors.New("prode: el modo mantenimiento está deshabilitado")
 	ErrInvalidAdminKey         = errors.New("prode: clave de administración inválida")
 	ErrProdeDisabled           = errors.New("prode: la funcionalidad está deshabilitada")
+	ErrInvalidTransition       = errors.New("prode: cambio de estado del partido no permitido")
+	ErrResultUnconfirmed       = errors.New("prode: el resultado del feed espera confirmación")
+	ErrSettlementRunning       = errors.New("prode: ya hay un settlement en curso para el partido")
+	ErrResultSettled           = errors.New("prode: el resultado ya puntuó predicciones y solo se cambia con una corrección")
+	ErrTournamentNotFound      = errors.New("prode: torneo no encontrado")
+	ErrTournamentNotLoaded     = errors.New("prode: el partido no tiene el torneo cargado")
+	ErrTournamentExists        = errors.New("prode: ya existe un torneo con ese slug")
+	ErrInvalidTournament       = errors.New("prode: datos del torneo inválidos")
+	ErrTeamNotFound            = errors.New("prode: equipo no encontrado")
+	ErrSameTeams               = errors.New("prode: local y visitante deben ser equipos distintos")
+	ErrTeamExists              = errors.New("prode: ya existe un equipo con ese nombre")
+	ErrLeagueNotFound          = errors.New("prode: liga no encontrada")
+	ErrLeagueFull              = errors.New("prode: la liga está completa")
+	ErrAlreadyInLeague         = errors.New("prode: el usuario ya es miembro de la liga")
+	ErrNotLeagueMember         = errors.New("prode: el usuario no es miembro de la liga")
+	ErrNotLeagueOwner          = errors.New("prode: solo el dueño puede administrar la liga")
+	ErrInvalidLeague           = errors.New("prode: datos de la liga inválidos")
+	ErrNotRanked               = errors.New("prode: el usuario todavía no suma puntos")
 	ErrInternal                = errors.New("prode: error interno de persistencia")
 )
@@ -0,0 +1,180 @@
+package prode
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/sports"
+)
+
+// reschedulableStatuses son los estados en los que el feed puede mover el
+// horario de un partido.
+var reschedulableStatuses = []string{MatchStatusDraft, MatchStatusScheduled, MatchStatusOpen}
+
+// FeedSync trae del proveedor deportivo los partidos de un equipo y sus
+// resultados, y los carga en un torneo. Los partidos importados quedan en
+// DRAFT hasta que un admin los publique, y los resultados esperan
+// confirmación antes de evaluarse.
+type FeedSync struct {
+	service      *Service
+	feed         sports.Client
+	tournamentID uuid.UUID
+	teamID       int64
+	competition  string
+}
+
+func NewFeedSync(service *Service, feed sports.Client, tournamentID uuid.UUID, teamID int64, competition string) *FeedSync {
+	return &FeedSync{
+		service:      service,
+		feed:         feed,
+		tournamentID: tournamentID,
+		teamID:       teamID,
+		competition:  competition,
+	}
+}
+
+// FeedImportResult resume una importación de fixtures.
+type FeedImportResult struct {
+	Created int
+	Updated int
+}
+
+// FeedPollResult resume una pasada de resultados.
+type FeedPollResult struct {
+	Recorded  int
+	ExtraTime int
+}
+
+// ImportFixtures crea los partidos nuevos del feed y actualiza el horario de
+// los que todavía aceptan predicciones.
+func (f *FeedSync) ImportFixtures(ctx context.Context) (FeedImportResult, error) {
+	var res FeedImportResult
+	repo := f.service.repo
+
+	fixtures, err := f.feed.TeamMatches(ctx, f.teamID, f.competition)
+	if err != nil {
+		return res, err
+	}
+
+	for _, fx := range fixtures {
+		if fx.Status == sports.StatusCancelled {
+			continue
+		}
+
+		match, err := repo.GetMatchByExternalID(ctx, fx.ExternalID)
+		if err != nil {
+			return res, err
+		}
+
+		if match == nil {
+			home, err := f.team(ctx, fx.HomeTeam)
+			if err != nil {
+				return res, err
+			}
+			away, err := f.team(ctx, fx.AwayTeam)
+			if err != nil {
+				return res, err
+			}
+
+			match = &ProdeMatch{
+				TournamentID: f.tournamentID,
+				HomeTeamID:   home.ID,
+				AwayTeamID:   away.ID,
+				Stage:        fx.Stage,
+				KickoffAt:    fx.KickoffAt,
+				Status:       MatchStatusDraft,
+				ExternalID:   fx.ExternalID,
+			}
+			if err := repo.CreateMatch(ctx, match); err != nil {
+				return res, err
+			}
+			res.Created++
+			continue
+		}
+
+		if !slices.Contains(reschedulableStatuses, match.Status) {
+			continue
+		}
+		if match.KickoffAt.Equal(fx.KickoffAt) {
+			continue
+		}
+
+		// Solo se toca el horario y solo si el partido sigue sin cerrar: un
+		// cambio de estado o una edición del admin entre la lectura y este
+		// update no se pisan.
+		updated, err := repo.RescheduleMatch(ctx, match.ID, fx.KickoffAt, reschedulableStatuses)
+		if err != nil {
+			return res, err
+		}
+		if !updated {
+			continue
+		}
+
+		slog.InfoContext(ctx, "horario de partido actualizado por el feed", "match_id", match.ID,
+			"from", match.KickoffAt,
+			"to", fx.KickoffAt)
+		res.Updated++
+	}
+
+	return res, nil
+}
+
+// PollResults consulta los partidos que ya empezaron y registra el marcador
+// de los 90 minutos de los que terminaron.
+func (f *FeedSync) PollResults(ctx context.Context) (FeedPollResult, error) {
+	var res FeedPollResult
+
+	pending, err := f.service.repo.GetMatchesAwaitingResult(ctx, f.service.clock.Now())
+	if err != nil {
+		return res, err
+	}
+
+	var errs []error
+	for i := range pending {
+		match := &pending[i]
+
+		fx, err := f.feed.Match(ctx, match.ExternalID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if fx.Status != sports.StatusFinished || fx.RegularTime == nil {
+			continue
+		}
+
+		match.ExtraTime = fx.Duration != sports.DurationRegular
+		if err := f.service.recordResult(ctx, match, fx.RegularTime.Home, fx.RegularTime.Away, ActorFeed, ResultSourceFeed); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		res.Recorded++
+
+		if match.ExtraTime {
+			res.ExtraTime++
+			slog.WarnContext(ctx, "partido con alargue o penales: revisar antes de confirmar", "match_id", match.ID,
+				"duration", fx.Duration)
+		}
+	}
+
+	return res, errors.Join(errs...)
+}
+
+// team devuelve el equipo del feed, creándolo la primera vez que aparece.
+func (f *FeedSync) team(ctx context.Context, t sports.Team) (*ProdeTeam, error) {
+	externalID := strconv.FormatInt(t.ID, 10)
+
+	team, err := f.service.repo.FindTeam(ctx, externalID, t.Name)
+	if err != nil || team != nil {
+		return team, err
+	}
+
+	team = &ProdeTeam{Name: t.Name, ExternalID: externalID}
+	if err := f.service.repo.CreateTeam(ctx, team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
@@ -1,10 +1,13 @@
 package prode
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -20,10 +23,50 @@ func NewHTTPHandler(service *Service) *HTTPHandler {
 	return &HTTPHandler{service: service}
 }
 
-// ListMatches devuelve los partidos visibles con la predicción del usuario autenticado.
+// ListTournaments devuelve los torneos activos.
+func (h *HTTPHandler) ListTournaments(w http.ResponseWriter, r *http.Request) {
+	tournaments, err := h.service.ListTournaments(r.Context(), true)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al listar torneos", "error", err)
+		writeProdeInternal(w, "Error al obtener los torneos")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, tournaments)
+}
+
+// ActiveTournamentAlias atiende las rutas anteriores a los torneos
+// (/prode/matches, /prode/predictions/me) con el torneo activo más reciente.
+// Quedan durante la transición de la app; la respuesta avisa la ruta nueva.
+func (h *HTTPHandler) ActiveTournamentAlias(next http.HandlerFunc, successor string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tournament, err := h.service.ActiveTournament(r.Context())
+		if err != nil {
+			if errors.Is(err, ErrTournamentNotFound) {
+				writeProdeNotFound(w, "No hay un torneo activo")
+				return
+			}
+			slog.ErrorContext(r.Context(), "error al obtener el torneo activo", "error", err)
+			writeProdeInternal(w, "Error al obtener el torneo")
+			return
+		}
+
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", fmt.Sprintf(`</api/v1/prode/tournaments/%s/%s>; rel="successor-version"`, tournament.Slug, successor))
+
+		chi.RouteContext(r.Context()).URLParams.Add("tournament", tournament.Slug)
+		next(w, r)
+	}
+}
+
+// ListMatches devuelve los partidos visibles del torneo con la predicción del usuario autenticado.
 func (h *HTTPHandler) ListMatches(w http.ResponseWriter, r *http.Request) {
-	matches, err := h.service.ListMatches(r.Context())
+	matches, err := h.service.ListMatches(r.Context(), chi.URLParam(r, "tournament"))
 	if err != nil {
+		if errors.Is(err, ErrTournamentNotFound) {
+			writeProdeNotFound(w, "Torneo no encontrado")
+			return
+		}
 		slog.ErrorContext(r.Context(), "error al listar partidos", "error", err)
 		writeProdeInternal(w, "Error al obtener los partidos")
 		return
@@ -97,10 +140,14 @@ func (h *HTTPHandler) CreateOrUpdatePrediction(w http.ResponseWriter, r *http.Re
 	utils.WriteSuccess(w, http.StatusOK, pred)
 }
 
-// GetMyPredictions devuelve todas las predicciones del usuario autenticado.
+// GetMyPredictions devuelve las predicciones del usuario autenticado en el torneo.
 func (h *HTTPHandler) GetMyPredictions(w http.ResponseWriter, r *http.Request) {
-	predictions, err := h.service.GetMyPredictions(r.Context())
+	predictions, err := h.service.GetMyPredictions(r.Context(), chi.URLParam(r, "tournament"))
 	if err != nil {
+		if errors.Is(err, ErrTournamentNotFound) {
+			writeProdeNotFound(w, "Torneo no encontrado")
+			return
+		}
 		slog.ErrorContext(r.Context(), "error al obtener predicciones del usuario", "error", err)
 		writeProdeInternal(w, "Error al obtener las predicciones")
 		return
@@ -109,8 +156,428 @@ func (h *HTTPHandler) GetMyPredictions(w http.ResponseWriter, r *http.Request) {
 	utils.WriteSuccess(w, http.StatusOK, predictions)
 }
 
+// GetPredictionHistory devuelve los cambios de la predicción del usuario
+// autenticado para un partido.
+func (h *HTTPHandler) GetPredictionHistory(w http.ResponseWriter, r *http.Request) {
+	matchID, err := uuid.Parse(chi.URLParam(r, "matchID"))
+	if err != nil {
+		writeProdeValidation(w, "ID de partido inválido", nil)
+		return
+	}
+
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProdeUnauthorized(w)
+		return
+	}
+
+	history, err := h.service.GetPredictionHistory(r.Context(), userID, matchID)
+	if err != nil {
+		if errors.Is(err, ErrMatchNotFound) {
+			writeProdeNotFound(w, "Partido no encontrado")
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al obtener historial de predicción", "match_id", matchID, "user_id", userID, "error", err)
+		writeProdeInternal(w, "Error al obtener el historial de la predicción")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, history)
+}
+
+// GetLeaderboard devuelve la tabla de puntos del torneo, paginada.
+func (h *HTTPHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	page := 1
+	pageSize := 20
+
+	if v, err := strconv.Atoi(q.Get("page")); err == nil && v > 0 {
+		page = v
+	}
+	if v, err := strconv.Atoi(q.Get("page_size")); err == nil && v > 0 && v <= 100 {
+		pageSize = v
+	}
+
+	board, err := h.service.GetLeaderboard(r.Context(), chi.URLParam(r, "tournament"), page, pageSize)
+	if err != nil {
+		if errors.Is(err, ErrTournamentNotFound) {
+			writeProdeNotFound(w, "Torneo no encontrado")
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al obtener la tabla de posiciones", "error", err)
+		writeProdeInternal(w, "Error al obtener la tabla de posiciones")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, board)
+}
+
+// GetMyLeaderboardEntry devuelve la posición del usuario autenticado.
+func (h *HTTPHandler) GetMyLeaderboardEntry(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProdeUnauthorized(w)
+		return
+	}
+
+	entry, err := h.service.GetMyLeaderboardEntry(r.Context(), chi.URLParam(r, "tournament"), userID)
+	if err != nil {
+		if errors.Is(err, ErrTournamentNotFound) {
+			writeProdeNotFound(w, "Torneo no encontrado")
+			return
+		}
+		if errors.Is(err, ErrNotRanked) {
+			writeProdeNotFound(w, "Todavía no sumás puntos en este torneo")
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al obtener la posición del usuario", "user_id", userID, "error", err)
+		writeProdeInternal(w, "Error al obtener tu posición")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, entry)
+}
+
+// CreateLeague crea una liga privada con el usuario autenticado como dueño.
+func (h *HTTPHandler) CreateLeague(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProdeUnauthorized(w)
+		return
+	}
+
+	var req CreateLeagueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProdeValidation(w, "Error al parsear el request, por favor validar el mismo", nil)
+		return
+	}
+
+	if req.Tournament == "" || req.Name == "" {
+		writeProdeValidation(w, "Faltan campos obligatorios: tournament, name", nil)
+		return
+	}
+
+	league, err := h.service.CreateLeague(r.Context(), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTournamentNotFound):
+			writeProdeNotFound(w, "Torneo no encontrado")
+		case errors.Is(err, ErrInvalidLeague):
+			writeProdeValidation(w, "El nombre debe tener hasta 60 caracteres y el cupo entre 2 y 200 miembros", nil)
+		default:
+			slog.ErrorContext(r.Context(), "error al crear liga", "user_id", userID, "error", err)
+			writeProdeInternal(w, "Error al crear la liga")
+		}
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusCreated, league)
+}
+
+// JoinLeague suma al usuario autenticado a una liga con su código de invitación.
+func (h *HTTPHandler) JoinLeague(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProdeUnauthorized(w)
+		return
+	}
+
+	var req JoinLeagueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProdeValidation(w, "Error al parsear el request, por favor validar el mismo", nil)
+		return
+	}
+
+	if req.InviteCode == "" {
+		writeProdeValidation(w, "Falta el campo obligatorio: invite_code", nil)
+		return
+	}
+
+	league, err := h.service.JoinLeague(r.Context(), userID, req.InviteCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrLeagueNotFound):
+			writeProdeNotFound(w, "Código de invitación inválido")
+		case errors.Is(err, ErrLeagueFull):
+			writeProdeConflict(w, "La liga está completa")
+		case errors.Is(err, ErrAlreadyInLeague):
+			writeProdeConflict(w, "Ya sos miembro de esta liga")
+		default:
+			slog.ErrorContext(r.Context(), "error al unirse a liga", "user_id", userID, "error", err)
+			writeProdeInternal(w, "Error al unirse a la liga")
+		}
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, league)
+}
+
+// ListMyLeagues devuelve las ligas del usuario autenticado.
+func (h *HTTPHandler) ListMyLeagues(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProdeUnauthorized(w)
+		return
+	}
+
+	leagues, err := h.service.ListMyLeagues(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al listar ligas del usuario", "user_id", userID, "error", err)
+		writeProdeInternal(w, "Error al obtener tus ligas")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, leagues)
+}
+
+// GetLeague devuelve una liga con sus miembros y premios.
+func (h *HTTPHandler) GetLeague(w http.ResponseWriter, r *http.Request) {
+	userID, leagueID, ok := leagueRequest(w, r)
+	if !ok {
+		return
+	}
+
+	league, err := h.service.GetLeague(r.Context(), userID, leagueID)
+	if err != nil {
+		if !writeLeagueAccessErr(w, err) {
+			slog.ErrorContext(r.Context(), "error al obtener liga", "league_id", leagueID, "error", err)
+			writeProdeInternal(w, "Error al obtener la liga")
+		}
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, league)
+}
+
+// GetLeagueLeaderboard devuelve la tabla de posiciones de una liga.
+func (h *HTTPHandler) GetLeagueLeaderboard(w http.ResponseWriter, r *http.Request) {
+	userID, leagueID, ok := leagueRequest(w, r)
+	if !ok {
+		return
+	}
+
+	board, err := h.service.GetLeagueLeaderboard(r.Context(), userID, leagueID)
+	if err != nil {
+		if !writeLeagueAccessErr(w, err) {
+			slog.ErrorContext(r.Context(), "error al obtener tabla de liga", "league_id", leagueID, "error", err)
+			writeProdeInternal(w, "Error al obtener la tabla de la liga")
+		}
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, board)
+}
+
+// LeaveLeague saca al usuario autenticado de una liga.
+func (h *HTTPHandler) LeaveLeague(w http.ResponseWriter, r *http.Request) {
+	userID, leagueID, ok := leagueRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.LeaveLeague(r.Context(), userID, leagueID); err != nil {
+		if !writeLeagueAccessErr(w, err) {
+			slog.ErrorContext(r.Context(), "error al dejar liga", "league_id", leagueID, "user_id", userID, "error", err)
+			writeProdeInternal(w, "Error al dejar la liga")
+		}
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, map[string]string{"message": "Dejaste la liga"})
+}
+
+// KickLeagueMember saca a un miembro de la liga. Solo para el dueño.
+func (h *HTTPHandler) KickLeagueMember(w http.ResponseWriter, r *http.Request) {
+	userID, leagueID, ok := leagueRequest(w, r)
+	if !ok {
+		return
+	}
+
+	memberID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		writeProdeValidation(w, "ID de usuario inválido", nil)
+		return
+	}
+
+	if err := h.service.KickMember(r.Context(), userID, leagueID, memberID); err != nil {
+		switch {
+		case errors.Is(err, ErrNotLeagueOwner):
+			writeProdeForbidden(w, "Solo el dueño de la liga puede expulsar miembros")
+		case errors.Is(err, ErrInvalidLeague):
+			writeProdeValidation(w, "Para salir de tu propia liga usá la opción de abandonarla", nil)
+		case writeLeagueAccessErr(w, err):
+		default:
+			slog.ErrorContext(r.Context(), "error al expulsar miembro", "league_id", leagueID, "user_id", memberID, "error", err)
+			writeProdeInternal(w, "Error al expulsar al miembro")
+		}
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, map[string]string{"message": "Miembro expulsado"})
+}
+
+// GetReminderPreference indica si el usuario autenticado recibe recordatorios.
+func (h *HTTPHandler) GetReminderPreference(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProdeUnauthorized(w)
+		return
+	}
+
+	pref, err := h.service.GetReminderPreference(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al obtener preferencia de recordatorios", "user_id", userID, "error", err)
+		writeProdeInternal(w, "Error al obtener la preferencia de recordatorios")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, pref)
+}
+
+// SetReminderPreference activa o desactiva los recordatorios del usuario
+// autenticado.
+func (h *HTTPHandler) SetReminderPreference(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProdeUnauthorized(w)
+		return
+	}
+
+	var req ReminderPreferenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProdeValidation(w, "Error al parsear el request, por favor validar el mismo", nil)
+		return
+	}
+
+	if req.Enabled == nil {
+		writeProdeValidation(w, "Faltan campos obligatorios: enabled", nil)
+		return
+	}
+
+	pref, err := h.service.SetReminderPreference(r.Context(), userID, *req.Enabled)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al guardar preferencia de recordatorios", "user_id", userID, "error", err)
+		writeProdeInternal(w, "Error al guardar la preferencia de recordatorios")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, pref)
+}
+
 // ---- Admin handlers ----
 
+// AdminListTournaments devuelve todos los torneos, activos o no.
+func (h *HTTPHandler) AdminListTournaments(w http.ResponseWriter, r *http.Request) {
+	tournaments, err := h.service.ListTournaments(r.Context(), false)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al listar torneos", "error", err)
+		writeProdeInternal(w, "Error al obtener los torneos")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, tournaments)
+}
+
+// AdminCreateTournament crea un nuevo torneo.
+func (h *HTTPHandler) AdminCreateTournament(w http.ResponseWriter, r *http.Request) {
+	var req CreateTournamentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProdeValidation(w, "Error al parsear el request, por favor validar el mismo", nil)
+		return
+	}
+
+	if req.Slug == "" || req.Name == "" {
+		writeProdeValidation(w, "Faltan campos obligatorios: slug, name", nil)
+		return
+	}
+
+	tournament, err := h.service.CreateTournament(r.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidTournament):
+			writeProdeValidation(w, err.Error(), nil)
+		case errors.Is(err, ErrTournamentExists):
+			writeProdeConflict(w, "Ya existe un torneo con ese slug")
+		default:
+			slog.ErrorContext(r.Context(), "error al crear torneo", "error", err)
+			writeProdeInternal(w, "Error al crear el torneo")
+		}
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusCreated, tournament)
+}
+
+// AdminUpdateTournament actualiza las reglas de un torneo.
+func (h *HTTPHandler) AdminUpdateTournament(w http.ResponseWriter, r *http.Request) {
+	tournamentID, err := uuid.Parse(chi.URLParam(r, "tournamentID"))
+	if err != nil {
+		writeProdeValidation(w, "ID de torneo inválido", nil)
+		return
+	}
+
+	var req UpdateTournamentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProdeValidation(w, "Error al parsear el request", nil)
+		return
+	}
+
+	tournament, err := h.service.UpdateTournament(r.Context(), tournamentID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTournamentNotFound):
+			writeProdeNotFound(w, "Torneo no encontrado")
+		case errors.Is(err, ErrInvalidTournament):
+			writeProdeValidation(w, err.Error(), nil)
+		default:
+			slog.ErrorContext(r.Context(), "error al actualizar torneo", "tournament_id", tournamentID, "error", err)
+			writeProdeInternal(w, "Error al actualizar el torneo")
+		}
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, tournament)
+}
+
+// AdminListTeams devuelve todos los equipos cargados.
+func (h *HTTPHandler) AdminListTeams(w http.ResponseWriter, r *http.Request) {
+	teams, err := h.service.ListTeams(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al listar equipos", "error", err)
+		writeProdeInternal(w, "Error al obtener los equipos")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, teams)
+}
+
+// AdminCreateTeam crea un nuevo equipo.
+func (h *HTTPHandler) AdminCreateTeam(w http.ResponseWriter, r *http.Request) {
+	var req CreateTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProdeValidation(w, "Error al parsear el request, por favor validar el mismo", nil)
+		return
+	}
+
+	if req.Name == "" {
+		writeProdeValidation(w, "Falta el campo obligatorio: name", nil)
+		return
+	}
+
+	team, err := h.service.CreateTeam(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, ErrTeamExists) {
+			writeProdeConflict(w, "Ya existe un equipo con ese nombre")
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al crear equipo", "error", err)
+		writeProdeInternal(w, "Error al crear el equipo")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusCreated, team)
+}
+
 // AdminCreateMatch crea un nuevo partido.
 func (h *HTTPHandler) AdminCreateMatch(w http.ResponseWriter, r *http.Request) {
 	var req CreateMatchRequest
@@ -119,19 +586,31 @@ func (h *HTTPHandler) AdminCreateMatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Stage == "" || req.Opponent == "" || req.KickoffAt.IsZero() {
-		writeProdeValidation(w, "Faltan campos obligatorios: stage, opponent, kickoff_at", nil)
+	if req.TournamentID == uuid.Nil || req.HomeTeamID == uuid.Nil || req.AwayTeamID == uuid.Nil ||
+		req.Stage == "" || req.KickoffAt.IsZero() {
+		writeProdeValidation(w, "Faltan campos obligatorios: tournament_id, home_team_id, away_team_id, stage, kickoff_at", nil)
 		return
 	}
 
 	match, err := h.service.CreateMatch(r.Context(), req)
 	if err != nil {
-		slog.ErrorContext(r.Context(), "error al crear partido", "error", err)
-		writeProdeInternal(w, "Error al crear el partido")
+		switch {
+		case errors.Is(err, ErrTournamentNotFound):
+			writeProdeNotFound(w, "Torneo no encontrado")
+		case errors.Is(err, ErrTeamNotFound):
+			writeProdeNotFound(w, "Equipo no encontrado")
+		case errors.Is(err, ErrSameTeams):
+			writeProdeValidation(w, "Local y visitante deben ser equipos distintos", nil)
+		default:
+			slog.ErrorContext(r.Context(), "error al crear partido", "error", err)
+			writeProdeInternal(w, "Error al crear el partido")
+		}
 		return
 	}
 
-	slog.InfoContext(r.Context(), "partido creado por admin", "match_id", match.ID, "opponent", match.Opponent)
+	slog.InfoContext(r.Context(), "partido creado por admin", "match_id", match.ID,
+		"home_team", match.HomeTeam.Name,
+		"away_team", match.AwayTeam.Name)
 	utils.WriteSuccess(w, http.StatusCreated, match)
 }
 
@@ -155,6 +634,18 @@ func (h *HTTPHandler) AdminUpdateMatch(w http.ResponseWriter, r *http.Request) {
 			writeProdeNotFound(w, "Partido no encontrado")
 			return
 		}
+		if errors.Is(err, ErrInvalidTransition) {
+			writeProdeConflict(w, "El partido no puede pasar a ese estado")
+			return
+		}
+		if errors.Is(err, ErrTeamNotFound) {
+			writeProdeNotFound(w, "Equipo no encontrado")
+			return
+		}
+		if errors.Is(err, ErrSameTeams) {
+			writeProdeValidation(w, "Local y visitante deben ser equipos distintos", nil)
+			return
+		}
 		slog.ErrorContext(r.Context(), "error al actualizar partido", "match_id", matchID, "error", err)
 		writeProdeInternal(w, "Error al actualizar el partido")
 		return
@@ -187,14 +678,22 @@ func (h *HTTPHandler) AdminRecordResult(w http.ResponseWriter, r *http.Request)
 			writeProdeValidation(w, "El resultado debe ser no negativo", nil)
 			return
 		}
+		if errors.Is(err, ErrInvalidTransition) {
+			writeProdeConflict(w, "El partido no admite cargar resultado en su estado actual")
+			return
+		}
+		if errors.Is(err, ErrResultSettled) {
+			writeProdeConflict(w, "El partido ya tiene predicciones evaluadas; corregí el resultado cuando termine el settlement")
+			return
+		}
 		slog.ErrorContext(r.Context(), "error al registrar resultado", "match_id", matchID, "error", err)
 		writeProdeInternal(w, "Error al registrar el resultado")
 		return
 	}
 
 	slog.InfoContext(r.Context(), "resultado registrado por admin", "match_id", matchID,
-		"argentina", req.ArgentinaGoals,
-		"opponent", req.OpponentGoals,)
+		"home_goals", req.HomeGoals,
+		"away_goals", req.AwayGoals,)
 	utils.WriteSuccess(w, http.StatusOK, match)
 }
 
@@ -216,6 +715,14 @@ func (h *HTTPHandler) AdminSettleMatch(w http.ResponseWriter, r *http.Request) {
 			writeProdeConflict(w, "El partido aún no tiene resultado cargado")
 			return
 		}
+		if errors.Is(err, ErrResultUnconfirmed) {
+			writeProdeConflict(w, "El resultado del feed debe confirmarse antes de evaluar")
+			return
+		}
+		if errors.Is(err, ErrSettlementRunning) {
+			writeProdeConflict(w, "Ya hay un settlement en curso para este partido")
+			return
+		}
 		slog.ErrorContext(r.Context(), "error al ejecutar settlement", "match_id", matchID, "error", err)
 		writeProdeInternal(w, "Error al procesar las predicciones")
 		return
@@ -224,6 +731,223 @@ func (h *HTTPHandler) AdminSettleMatch(w http.ResponseWriter, r *http.Request) {
 	utils.WriteSuccess(w, http.StatusOK, result)
 }
 
+// AdminGetSettlementStatus devuelve el avance y las corridas de settlement de un partido.
+func (h *HTTPHandler) AdminGetSettlementStatus(w http.ResponseWriter, r *http.Request) {
+	matchID, err := uuid.Parse(chi.URLParam(r, "matchID"))
+	if err != nil {
+		writeProdeValidation(w, "ID de partido inválido", nil)
+		return
+	}
+
+	status, err := h.service.GetSettlementStatus(r.Context(), matchID)
+	if err != nil {
+		if errors.Is(err, ErrMatchNotFound) {
+			writeProdeNotFound(w, "Partido no encontrado")
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al obtener estado del settlement", "match_id", matchID, "error", err)
+		writeProdeInternal(w, "Error al obtener el estado del settlement")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, status)
+}
+
+// AdminGetMatchStats devuelve el reporte de predicciones de un partido.
+func (h *HTTPHandler) AdminGetMatchStats(w http.ResponseWriter, r *http.Request) {
+	matchID, err := uuid.Parse(chi.URLParam(r, "matchID"))
+	if err != nil {
+		writeProdeValidation(w, "ID de partido inválido", nil)
+		return
+	}
+
+	stats, err := h.service.GetMatchStats(r.Context(), matchID)
+	if err != nil {
+		if errors.Is(err, ErrMatchNotFound) {
+			writeProdeNotFound(w, "Partido no encontrado")
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al obtener estadísticas del partido", "match_id", matchID, "error", err)
+		writeProdeInternal(w, "Error al obtener las estadísticas")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, stats)
+}
+
+// AdminExportWinners descarga el CSV de ganadores de un partido.
+func (h *HTTPHandler) AdminExportWinners(w http.ResponseWriter, r *http.Request) {
+	matchID, err := uuid.Parse(chi.URLParam(r, "matchID"))
+	if err != nil {
+		writeProdeValidation(w, "ID de partido inválido", nil)
+		return
+	}
+
+	// Se arma en memoria para poder responder un error JSON si falla la
+	// consulta, antes de mandar los headers del CSV.
+	var buf bytes.Buffer
+	if err := h.service.ExportWinners(r.Context(), matchID, &buf); err != nil {
+		if errors.Is(err, ErrMatchNotFound) {
+			writeProdeNotFound(w, "Partido no encontrado")
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al exportar ganadores", "match_id", matchID, "error", err)
+		writeProdeInternal(w, "Error al exportar los ganadores")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="prode-ganadores-%s.csv"`, matchID))
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
+
+// AdminConfirmResult confirma el resultado que trajo el feed.
+func (h *HTTPHandler) AdminConfirmResult(w http.ResponseWriter, r *http.Request) {
+	matchID, err := uuid.Parse(chi.URLParam(r, "matchID"))
+	if err != nil {
+		writeProdeValidation(w, "ID de partido inválido", nil)
+		return
+	}
+
+	match, err := h.service.ConfirmResult(r.Context(), matchID)
+	if err != nil {
+		if errors.Is(err, ErrMatchNotFound) {
+			writeProdeNotFound(w, "Partido no encontrado")
+			return
+		}
+		if errors.Is(err, ErrResultMissing) {
+			writeProdeConflict(w, "El partido aún no tiene resultado cargado")
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al confirmar resultado", "match_id", matchID, "error", err)
+		writeProdeInternal(w, "Error al confirmar el resultado")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, match)
+}
+
+// AdminCorrectResult corrige el resultado de un partido ya evaluado.
+func (h *HTTPHandler) AdminCorrectResult(w http.ResponseWriter, r *http.Request) {
+	matchID, err := uuid.Parse(chi.URLParam(r, "matchID"))
+	if err != nil {
+		writeProdeValidation(w, "ID de partido inválido", nil)
+		return
+	}
+
+	var req RecordResultRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProdeValidation(w, "Error al parsear el request", nil)
+		return
+	}
+
+	result, err := h.service.CorrectResult(r.Context(), matchID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrMatchNotFound):
+			writeProdeNotFound(w, "Partido no encontrado")
+		case errors.Is(err, ErrInvalidScore):
+			writeProdeValidation(w, "El resultado debe ser no negativo", nil)
+		case errors.Is(err, ErrInvalidTransition):
+			writeProdeConflict(w, "Solo se puede corregir el resultado de un partido evaluado")
+		default:
+			slog.ErrorContext(r.Context(), "error al corregir resultado", "match_id", matchID, "error", err)
+			writeProdeInternal(w, "Error al corregir el resultado")
+		}
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, result)
+}
+
+// AdminGetMatchTransitions devuelve el historial de estados de un partido.
+func (h *HTTPHandler) AdminGetMatchTransitions(w http.ResponseWriter, r *http.Request) {
+	matchID, err := uuid.Parse(chi.URLParam(r, "matchID"))
+	if err != nil {
+		writeProdeValidation(w, "ID de partido inválido", nil)
+		return
+	}
+
+	history, err := h.service.GetMatchTransitions(r.Context(), matchID)
+	if err != nil {
+		if errors.Is(err, ErrMatchNotFound) {
+			writeProdeNotFound(w, "Partido no encontrado")
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al obtener historial del partido", "match_id", matchID, "error", err)
+		writeProdeInternal(w, "Error al obtener el historial del partido")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, history)
+}
+
+// AdminListLeagues devuelve todas las ligas privadas.
+func (h *HTTPHandler) AdminListLeagues(w http.ResponseWriter, r *http.Request) {
+	leagues, err := h.service.ListLeagues(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al listar ligas", "error", err)
+		writeProdeInternal(w, "Error al obtener las ligas")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, leagues)
+}
+
+// AdminSetLeaguePrizes define los puestos premiados de una liga.
+func (h *HTTPHandler) AdminSetLeaguePrizes(w http.ResponseWriter, r *http.Request) {
+	leagueID, err := uuid.Parse(chi.URLParam(r, "leagueID"))
+	if err != nil {
+		writeProdeValidation(w, "ID de liga inválido", nil)
+		return
+	}
+
+	var req SetLeaguePrizesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProdeValidation(w, "Error al parsear el request", nil)
+		return
+	}
+
+	prizes, err := h.service.SetLeaguePrizes(r.Context(), leagueID, req.Ranks)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrLeagueNotFound):
+			writeProdeNotFound(w, "Liga no encontrada")
+		case errors.Is(err, ErrInvalidLeague):
+			writeProdeValidation(w, "Los puestos deben ser positivos, sin repetir y como máximo 10", nil)
+		default:
+			slog.ErrorContext(r.Context(), "error al definir premios de liga", "league_id", leagueID, "error", err)
+			writeProdeInternal(w, "Error al definir los premios")
+		}
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, prizes)
+}
+
+// AdminAwardLeaguePrizes entrega los premios de una liga según su tabla.
+func (h *HTTPHandler) AdminAwardLeaguePrizes(w http.ResponseWriter, r *http.Request) {
+	leagueID, err := uuid.Parse(chi.URLParam(r, "leagueID"))
+	if err != nil {
+		writeProdeValidation(w, "ID de liga inválido", nil)
+		return
+	}
+
+	result, err := h.service.AwardLeaguePrizes(r.Context(), leagueID)
+	if err != nil {
+		if errors.Is(err, ErrLeagueNotFound) {
+			writeProdeNotFound(w, "Liga no encontrada")
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al entregar premios de liga", "league_id", leagueID, "error", err)
+		writeProdeInternal(w, "Error al entregar los premios")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, result)
+}
+
 // AdminRetryPendingRewards reintenta asignar vouchers a premios pendientes.
 func (h *HTTPHandler) AdminRetryPendingRewards(w http.ResponseWriter, r *http.Request) {
 	result, err := h.service.RetryPendingRewards(r.Context())
@@ -236,6 +960,37 @@ func (h *HTTPHandler) AdminRetryPendingRewards(w http.ResponseWriter, r *http.Re
 	utils.WriteSuccess(w, http.StatusOK, result)
 }
 
+// leagueRequest lee el usuario autenticado y el ID de liga de la ruta. Si
+// falta alguno ya escribió la respuesta de error.
+func leagueRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProdeUnauthorized(w)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	leagueID, err := uuid.Parse(chi.URLParam(r, "leagueID"))
+	if err != nil {
+		writeProdeValidation(w, "ID de liga inválido", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, leagueID, true
+}
+
+// writeLeagueAccessErr responde los errores de acceso a una liga. Devuelve
+// false si err no es uno de ellos.
+func writeLeagueAccessErr(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, ErrLeagueNotFound):
+		writeProdeNotFound(w, "Liga no encontrada")
+	case errors.Is(err, ErrNotLeagueMember):
+		writeProdeNotFound(w, "No sos miembro de esta liga")
+	default:
+		return false
+	}
+	return true
+}
+
 func writeProdeUnauthorized(w http.ResponseWriter) {
 	utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
 		Code:    utils.ErrCodeUnauthorized,
@@ -251,6 +1006,13 @@ func writeProdeValidation(w http.ResponseWriter, message string, fields interfac
 	})
 }
 
+func writeProdeForbidden(w http.ResponseWriter, message string) {
+	utils.WriteError(w, http.StatusForbidden, utils.WriteErrorOpts{
+		Code:    utils.ErrCodeUnauthorized,
+		Message: message,
+	})
+}
+
 func writeProdeNotFound(w http.ResponseWriter, message string) {
 	utils.WriteError(w, http.StatusNotFound, utils.WriteErrorOpts{
 		Code:    utils.ErrCodeNotFound,
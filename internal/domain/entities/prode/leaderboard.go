@@ -0,0 +1,64 @@
+package prode
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// GetLeaderboard devuelve una página de la tabla de puntos de un torneo.
+func (s *Service) GetLeaderboard(ctx context.Context, tournamentSlug string, page, pageSize int) (*LeaderboardResponse, error) {
+	tournament, err := s.repo.GetTournamentBySlug(ctx, tournamentSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, total, err := s.repo.GetLeaderboard(ctx, tournament.ID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]LeaderboardEntry, len(rows))
+	for i := range rows {
+		items[i] = leaderboardRowToEntry(&rows[i])
+	}
+
+	return &LeaderboardResponse{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+		HasMore:  int64(page*pageSize) < total,
+	}, nil
+}
+
+// GetMyLeaderboardEntry devuelve la posición del usuario en la tabla de un
+// torneo.
+func (s *Service) GetMyLeaderboardEntry(ctx context.Context, tournamentSlug string, userID uuid.UUID) (*LeaderboardEntry, error) {
+	tournament, err := s.repo.GetTournamentBySlug(ctx, tournamentSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.repo.GetLeaderboardEntry(ctx, tournament.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, ErrNotRanked
+	}
+
+	entry := leaderboardRowToEntry(row)
+	return &entry, nil
+}
+
+func leaderboardRowToEntry(r *LeaderboardRow) LeaderboardEntry {
+	return LeaderboardEntry{
+		Rank:        r.Rank,
+		UserID:      r.UserID.String(),
+		Name:        r.Name,
+		Points:      r.Points,
+		ExactHits:   r.ExactHits,
+		Predictions: r.Predictions,
+	}
+}
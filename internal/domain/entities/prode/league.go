@@ -0,0 +1,473 @@
+package prode
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"log/slog"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/voucher"
+	"gorm.io/gorm"
+)
+
+// Límites de una liga privada.
+const (
+	DefaultLeagueMaxMembers = 50
+	MaxLeagueMembers        = 200
+	MaxLeaguePrizes         = 10
+	inviteCodeLength        = 8
+	// inviteCodeAlphabet deja afuera caracteres que se confunden al dictar
+	// el código (0/O, 1/I/L).
+	inviteCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+)
+
+// ProdeLeague es una liga privada dentro de un torneo. La tabla de la liga se
+// arma con las mismas predicciones del torneo, filtradas por sus miembros.
+type ProdeLeague struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TournamentID uuid.UUID `gorm:"type:uuid;not null;index" json:"tournament_id"`
+	Name         string    `gorm:"type:varchar(60);not null" json:"name"`
+	InviteCode   string    `gorm:"type:varchar(12);not null;uniqueIndex" json:"invite_code"`
+	OwnerID      uuid.UUID `gorm:"type:uuid;not null;index" json:"owner_id"`
+	MaxMembers   int       `gorm:"not null;default:50" json:"max_members"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (ProdeLeague) TableName() string { return "prode_leagues" }
+
+func (l *ProdeLeague) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// ProdeLeagueMember es la pertenencia de un usuario a una liga.
+type ProdeLeagueMember struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	LeagueID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_prode_league_member" json:"league_id"`
+	UserID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_prode_league_member;index" json:"user_id"`
+	JoinedAt time.Time `gorm:"not null" json:"joined_at"`
+}
+
+func (ProdeLeagueMember) TableName() string { return "prode_league_members" }
+
+func (m *ProdeLeagueMember) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// ProdeLeaguePrize es un voucher que un admin reserva para un puesto de la
+// tabla de una liga. Se entrega con el mismo pool de vouchers que los
+// aciertos exactos.
+type ProdeLeaguePrize struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	LeagueID  uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_prode_league_prize_rank" json:"league_id"`
+	Rank      int        `gorm:"not null;uniqueIndex:idx_prode_league_prize_rank" json:"rank"`
+	Status    string     `gorm:"type:varchar(30);not null;default:PENDING" json:"status"`
+	UserID    *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	VoucherID *uuid.UUID `gorm:"type:uuid" json:"voucher_id,omitempty"`
+	AwardedAt *time.Time `gorm:"type:timestamptz" json:"awarded_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (ProdeLeaguePrize) TableName() string { return "prode_league_prizes" }
+
+func (p *ProdeLeaguePrize) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateLeague crea una liga en un torneo con el usuario como dueño y primer
+// miembro.
+func (s *Service) CreateLeague(ctx context.Context, userID uuid.UUID, req CreateLeagueRequest) (*LeagueResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 60 {
+		return nil, ErrInvalidLeague
+	}
+
+	maxMembers := req.MaxMembers
+	if maxMembers == 0 {
+		maxMembers = DefaultLeagueMaxMembers
+	}
+	if maxMembers < 2 || maxMembers > MaxLeagueMembers {
+		return nil, ErrInvalidLeague
+	}
+
+	tournament, err := s.repo.GetTournamentBySlug(ctx, req.Tournament)
+	if err != nil {
+		return nil, err
+	}
+
+	league := ProdeLeague{
+		TournamentID: tournament.ID,
+		Name:         name,
+		OwnerID:      userID,
+		MaxMembers:   maxMembers,
+	}
+
+	// El código es aleatorio; si choca con otro se reintenta con uno nuevo.
+	for attempt := 0; ; attempt++ {
+		league.ID = uuid.Nil
+		league.InviteCode, err = newInviteCode()
+		if err != nil {
+			return nil, err
+		}
+		err = s.repo.CreateLeague(ctx, &league, s.clock.Now())
+		if !errors.Is(err, errInviteCodeTaken) || attempt == 2 {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "liga creada", "league_id", league.ID,
+		"tournament_id", league.TournamentID,
+		"owner_id", userID)
+
+	resp := leagueToResponse(&league, 1, userID)
+	return &resp, nil
+}
+
+// JoinLeague suma al usuario a la liga del código de invitación.
+func (s *Service) JoinLeague(ctx context.Context, userID uuid.UUID, code string) (*LeagueResponse, error) {
+	league, err := s.repo.GetLeagueByInviteCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.AddLeagueMember(ctx, league, userID, s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "usuario unido a liga", "league_id", league.ID, "user_id", userID)
+
+	resp := leagueToResponse(league, count, userID)
+	return &resp, nil
+}
+
+// ListMyLeagues devuelve las ligas a las que pertenece el usuario.
+func (s *Service) ListMyLeagues(ctx context.Context, userID uuid.UUID) ([]LeagueResponse, error) {
+	rows, err := s.repo.ListUserLeagues(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]LeagueResponse, len(rows))
+	for i := range rows {
+		resp[i] = leagueToResponse(&rows[i].ProdeLeague, rows[i].MemberCount, userID)
+	}
+	return resp, nil
+}
+
+// GetLeague devuelve la liga con sus miembros y premios. Solo la ven sus
+// miembros.
+func (s *Service) GetLeague(ctx context.Context, userID, leagueID uuid.UUID) (*LeagueDetailResponse, error) {
+	league, err := s.memberLeague(ctx, userID, leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.GetLeagueMembers(ctx, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	prizes, err := s.repo.GetLeaguePrizes(ctx, leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &LeagueDetailResponse{
+		LeagueResponse: leagueToResponse(league, len(members), userID),
+		Members:        make([]LeagueMemberResponse, len(members)),
+		Prizes:         make([]LeaguePrizeResponse, len(prizes)),
+	}
+	for i, m := range members {
+		resp.Members[i] = LeagueMemberResponse{
+			UserID:   m.UserID.String(),
+			Name:     m.Name,
+			IsOwner:  m.UserID == league.OwnerID,
+			JoinedAt: m.JoinedAt,
+		}
+	}
+	for i := range prizes {
+		resp.Prizes[i] = leaguePrizeToResponse(&prizes[i])
+	}
+	return resp, nil
+}
+
+// GetLeagueLeaderboard devuelve la tabla de la liga. Incluye a los miembros
+// que todavía no suman puntos, al final.
+func (s *Service) GetLeagueLeaderboard(ctx context.Context, userID, leagueID uuid.UUID) (*LeaderboardResponse, error) {
+	league, err := s.memberLeague(ctx, userID, leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.GetLeagueLeaderboard(ctx, league)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]LeaderboardEntry, len(rows))
+	for i := range rows {
+		items[i] = leaderboardRowToEntry(&rows[i])
+	}
+
+	return &LeaderboardResponse{
+		Items:    items,
+		Page:     1,
+		PageSize: len(items),
+		Total:    int64(len(items)),
+	}, nil
+}
+
+// LeaveLeague saca al usuario de la liga. Si se va el dueño, la liga pasa al
+// miembro más antiguo; si no queda nadie, se borra.
+func (s *Service) LeaveLeague(ctx context.Context, userID, leagueID uuid.UUID) error {
+	league, err := s.memberLeague(ctx, userID, leagueID)
+	if err != nil {
+		return err
+	}
+
+	newOwner, err := s.repo.LeaveLeague(ctx, league, userID)
+	if err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "usuario dejó la liga", "league_id", leagueID,
+		"user_id", userID,
+		"new_owner_id", newOwner)
+	return nil
+}
+
+// KickMember saca a otro miembro de la liga. Solo puede hacerlo el dueño.
+func (s *Service) KickMember(ctx context.Context, ownerID, leagueID, memberID uuid.UUID) error {
+	league, err := s.repo.GetLeagueByID(ctx, leagueID)
+	if err != nil {
+		return err
+	}
+	if league.OwnerID != ownerID {
+		return ErrNotLeagueOwner
+	}
+	if memberID == ownerID {
+		return ErrInvalidLeague
+	}
+
+	removed, err := s.repo.RemoveLeagueMember(ctx, leagueID, memberID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrNotLeagueMember
+	}
+
+	slog.InfoContext(ctx, "miembro expulsado de la liga", "league_id", leagueID, "user_id", memberID)
+	return nil
+}
+
+// ---- Admin ----
+
+// ListLeagues devuelve todas las ligas con su cantidad de miembros.
+func (s *Service) ListLeagues(ctx context.Context) ([]LeagueResponse, error) {
+	rows, err := s.repo.ListLeagues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]LeagueResponse, len(rows))
+	for i := range rows {
+		resp[i] = leagueToResponse(&rows[i].ProdeLeague, rows[i].MemberCount, uuid.Nil)
+	}
+	return resp, nil
+}
+
+// SetLeaguePrizes define qué puestos de la liga reciben voucher. Los premios
+// ya entregados se conservan aunque su puesto no venga en la lista.
+func (s *Service) SetLeaguePrizes(ctx context.Context, leagueID uuid.UUID, ranks []int) ([]LeaguePrizeResponse, error) {
+	if len(ranks) > MaxLeaguePrizes {
+		return nil, ErrInvalidLeague
+	}
+	seen := make(map[int]bool, len(ranks))
+	for _, r := range ranks {
+		if r < 1 || seen[r] {
+			return nil, ErrInvalidLeague
+		}
+		seen[r] = true
+	}
+	sort.Ints(ranks)
+
+	if _, err := s.repo.GetLeagueByID(ctx, leagueID); err != nil {
+		return nil, err
+	}
+
+	prizes, err := s.repo.SetLeaguePrizes(ctx, leagueID, ranks)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "premios de liga actualizados", "league_id", leagueID, "ranks", ranks)
+
+	resp := make([]LeaguePrizeResponse, len(prizes))
+	for i := range prizes {
+		resp[i] = leaguePrizeToResponse(&prizes[i])
+	}
+	return resp, nil
+}
+
+// AwardLeaguePrizes entrega los premios de la liga según la tabla actual.
+// Los ya entregados no se tocan, así que se puede volver a correr después de
+// cargar vouchers para los que quedaron sin stock. Cada premio se entrega en
+// su transacción con la fila bloqueada: dos corridas a la vez no asignan dos
+// vouchers al mismo puesto.
+func (s *Service) AwardLeaguePrizes(ctx context.Context, leagueID uuid.UUID) (*LeaguePrizeAwardResponse, error) {
+	league, err := s.repo.GetLeagueByID(ctx, leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	prizes, err := s.repo.GetLeaguePrizes(ctx, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.GetLeagueLeaderboard(ctx, league)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &LeaguePrizeAwardResponse{LeagueID: leagueID.String()}
+	for i := range prizes {
+		if prizes[i].Status == RewardStatusFulfilled {
+			continue
+		}
+
+		// Un puesto sin predicciones evaluadas no gana nada.
+		rank := prizes[i].Rank
+		if rank > len(rows) || rows[rank-1].Predictions == 0 {
+			resp.Skipped++
+			continue
+		}
+		winner := rows[rank-1].UserID
+
+		var awarded *voucher.Voucher
+		err := s.repo.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			txRepo := s.repo.WithTx(tx)
+
+			prize, err := txRepo.LockLeaguePrize(ctx, prizes[i].ID)
+			if err != nil {
+				return err
+			}
+			// Lo entregó otra corrida o se quitó el puesto mientras tanto.
+			if prize == nil || prize.Status == RewardStatusFulfilled {
+				return nil
+			}
+			prize.UserID = &winner
+
+			v, err := s.voucherRepo.WithTx(tx).AssignNextVoucher(ctx, &voucher.VoucherRequest{UserID: winner})
+			switch {
+			case errors.Is(err, voucher.ErrNoAvailableVouchers):
+				prize.Status = RewardStatusPendingInventory
+			case err != nil:
+				return err
+			default:
+				now := s.clock.Now()
+				prize.Status = RewardStatusFulfilled
+				prize.VoucherID = &v.ID
+				prize.AwardedAt = &now
+				awarded = v
+			}
+
+			if err := txRepo.UpdateLeaguePrize(ctx, prize); err != nil {
+				return err
+			}
+			if awarded == nil {
+				resp.PendingInventory++
+			}
+			return nil
+		})
+		if err != nil {
+			return resp, err
+		}
+		if awarded == nil {
+			continue
+		}
+
+		resp.Awarded++
+		if err := s.sendVoucherEmail(ctx, winner, awarded); err != nil {
+			slog.ErrorContext(ctx, "error al enviar email del premio de liga", "league_id", leagueID,
+				"user_id", winner,
+				"error", err)
+		}
+	}
+
+	slog.InfoContext(ctx, "premios de liga procesados", "league_id", leagueID,
+		"awarded", resp.Awarded,
+		"pending_inventory", resp.PendingInventory,
+		"skipped", resp.Skipped)
+	return resp, nil
+}
+
+// memberLeague devuelve la liga si el usuario es miembro.
+func (s *Service) memberLeague(ctx context.Context, userID, leagueID uuid.UUID) (*ProdeLeague, error) {
+	league, err := s.repo.GetLeagueByID(ctx, leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.GetLeagueMember(ctx, leagueID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrNotLeagueMember
+	}
+	return league, nil
+}
+
+func newInviteCode() (string, error) {
+	max := big.NewInt(int64(len(inviteCodeAlphabet)))
+	code := make([]byte, inviteCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = inviteCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+func leagueToResponse(l *ProdeLeague, memberCount int, userID uuid.UUID) LeagueResponse {
+	return LeagueResponse{
+		ID:           l.ID.String(),
+		TournamentID: l.TournamentID.String(),
+		Name:         l.Name,
+		InviteCode:   l.InviteCode,
+		OwnerID:      l.OwnerID.String(),
+		IsOwner:      l.OwnerID == userID,
+		MaxMembers:   l.MaxMembers,
+		MemberCount:  memberCount,
+		CreatedAt:    l.CreatedAt,
+	}
+}
+
+func leaguePrizeToResponse(p *ProdeLeaguePrize) LeaguePrizeResponse {
+	resp := LeaguePrizeResponse{Rank: p.Rank, Status: p.Status, AwardedAt: p.AwardedAt}
+	if p.UserID != nil {
+		resp.UserID = p.UserID.String()
+	}
+	return resp
+}
@@ -0,0 +1,30 @@
+package prode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewInviteCode(t *testing.T) {
+	t.Parallel()
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		code, err := newInviteCode()
+		if err != nil {
+			t.Fatalf("newInviteCode() error = %v", err)
+		}
+		if len(code) != inviteCodeLength {
+			t.Fatalf("len(%q) = %d, want %d", code, len(code), inviteCodeLength)
+		}
+		for _, c := range code {
+			if !strings.ContainsRune(inviteCodeAlphabet, c) {
+				t.Fatalf("código %q tiene un carácter fuera del alfabeto: %q", code, c)
+			}
+		}
+		if seen[code] {
+			t.Fatalf("código repetido: %q", code)
+		}
+		seen[code] = true
+	}
+}
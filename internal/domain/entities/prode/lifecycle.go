@@ -0,0 +1,60 @@
+package prode
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// LifecycleResult resume una pasada del scheduler de partidos.
+type LifecycleResult struct {
+	Opened  int64
+	Closed  int64
+	Settled int
+}
+
+// AdvanceMatches mueve los partidos según el reloj: abre los que arrancan
+// dentro de openLead, cierra los que llegaron al corte y, si autoSettle está
+// activo, evalúa los que ya tienen resultado confirmado. Cada paso es un UPDATE
+// condicionado al estado actual, así correrlo dos veces no cambia nada.
+func (s *Service) AdvanceMatches(ctx context.Context, openLead time.Duration, autoSettle bool) (LifecycleResult, error) {
+	var res LifecycleResult
+	now := s.clock.Now()
+
+	opened, err := s.repo.OpenDueMatches(ctx, now, now.Add(openLead))
+	if err != nil {
+		return res, err
+	}
+	res.Opened = opened
+
+	closed, err := s.repo.CloseDueMatches(ctx, now)
+	if err != nil {
+		return res, err
+	}
+	res.Closed = closed
+
+	if !autoSettle {
+		return res, nil
+	}
+
+	recorded, err := s.repo.GetMatchesByStatus(ctx, MatchStatusResultRecorded)
+	if err != nil {
+		return res, err
+	}
+
+	var errs []error
+	for _, m := range recorded {
+		if m.NeedsConfirmation() {
+			continue
+		}
+		if _, err := s.settleMatch(ctx, m.ID, ActorScheduler); err != nil {
+			slog.ErrorContext(ctx, "error en settlement automático", "match_id", m.ID, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		res.Settled++
+	}
+
+	return res, errors.Join(errs...)
+}
@@ -1,6 +1,7 @@
 package prode
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,18 +19,65 @@ const (
 	MatchStatusCancelled      = "CANCELLED"
 )
 
+// Origen del resultado de un partido.
+const (
+	ResultSourceManual = "MANUAL"
+	ResultSourceFeed   = "FEED"
+)
+
+// matchTransitions son los cambios de estado permitidos. Quedarse en el mismo
+// estado siempre es válido, así reintentar una transición no falla.
+var matchTransitions = map[string][]string{
+	MatchStatusDraft:          {MatchStatusScheduled, MatchStatusCancelled},
+	MatchStatusScheduled:      {MatchStatusDraft, MatchStatusOpen, MatchStatusClosed, MatchStatusCancelled},
+	MatchStatusOpen:           {MatchStatusClosed, MatchStatusCancelled},
+	MatchStatusClosed:         {MatchStatusResultRecorded, MatchStatusCancelled},
+	MatchStatusResultRecorded: {MatchStatusEvaluated},
+}
+
+// CanTransition indica si un partido puede pasar de from a to.
+func CanTransition(from, to string) bool {
+	if from == to {
+		_, known := matchTransitions[from]
+		return known || from == MatchStatusEvaluated || from == MatchStatusCancelled
+	}
+	for _, next := range matchTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ProdeMatch es un partido de un torneo. Los equipos y el torneo se cargan
+// con Preload; al guardar se omiten para no tocar sus filas.
 type ProdeMatch struct {
-	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	Stage          string     `gorm:"type:varchar(50);not null" json:"stage"`
-	Opponent       string     `gorm:"type:varchar(100);not null" json:"opponent"`
-	KickoffAt      time.Time  `gorm:"type:timestamptz;not null" json:"kickoff_at"`
-	Status         string     `gorm:"type:varchar(30);not null;default:DRAFT" json:"status"`
-	ArgentinaGoals *int       `gorm:"type:smallint" json:"argentina_goals,omitempty"`
-	OpponentGoals  *int       `gorm:"type:smallint" json:"opponent_goals,omitempty"`
-	IsVisible      bool       `gorm:"not null;default:false" json:"is_visible"`
-	ExternalID     string     `gorm:"type:varchar(100)" json:"external_id,omitempty"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
+	ID           uuid.UUID        `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TournamentID uuid.UUID        `gorm:"type:uuid;index" json:"tournament_id"`
+	Tournament   *ProdeTournament `gorm:"foreignKey:TournamentID" json:"-"`
+	HomeTeamID   uuid.UUID        `gorm:"type:uuid;index" json:"home_team_id"`
+	HomeTeam     *ProdeTeam       `gorm:"foreignKey:HomeTeamID" json:"-"`
+	AwayTeamID   uuid.UUID        `gorm:"type:uuid;index" json:"away_team_id"`
+	AwayTeam     *ProdeTeam       `gorm:"foreignKey:AwayTeamID" json:"-"`
+	Stage        string           `gorm:"type:varchar(50);not null" json:"stage"`
+	KickoffAt    time.Time        `gorm:"type:timestamptz;not null" json:"kickoff_at"`
+	Status       string           `gorm:"type:varchar(30);not null;default:DRAFT" json:"status"`
+	HomeGoals    *int             `gorm:"type:smallint" json:"home_goals,omitempty"`
+	AwayGoals    *int             `gorm:"type:smallint" json:"away_goals,omitempty"`
+	IsVisible    bool             `gorm:"not null;default:false" json:"is_visible"`
+	ExternalID   string           `gorm:"type:varchar(100)" json:"external_id,omitempty"`
+	// ResultSource indica si el resultado lo cargó un admin o vino del feed.
+	// Los del feed esperan confirmación (ResultConfirmedAt) antes de evaluar.
+	ResultSource      string     `gorm:"type:varchar(10);not null;default:MANUAL" json:"result_source"`
+	ResultConfirmedAt *time.Time `gorm:"type:timestamptz" json:"result_confirmed_at,omitempty"`
+	// ExtraTime marca partidos que fueron a alargue o penales; el resultado
+	// guardado sigue siendo el de los 90 minutos.
+	ExtraTime bool `gorm:"not null;default:false" json:"extra_time"`
+	// RemindedAt es la primera pasada de recordatorios del partido; marca que
+	// ya se mandó el resumen de participación a los admins.
+	RemindedAt *time.Time `gorm:"type:timestamptz" json:"reminded_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
 }
 
 func (ProdeMatch) TableName() string { return "prode_matches" }
@@ -41,27 +89,43 @@ func (m *ProdeMatch) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// NeedsConfirmation indica si el resultado vino del feed y un admin todavía
+// no lo confirmó.
+func (m *ProdeMatch) NeedsConfirmation() bool {
+	return m.ResultSource == ResultSourceFeed && m.ResultConfirmedAt == nil
+}
 
-// CutoffAt devuelve la hora límite para aceptar predicciones.
-// El corte es 1 hora antes del inicio en huso horario argentino.
-func (m *ProdeMatch) CutoffAt() time.Time {
-	loc, _ := time.LoadLocation("America/Argentina/Buenos_Aires")
-	return m.KickoffAt.In(loc).Add(-1 * time.Hour)
+// CutoffAt devuelve la hora límite para aceptar predicciones, en la zona
+// horaria del torneo. El torneo tiene que venir precargado: sin él devuelve
+// ErrTournamentNotLoaded en lugar de suponer un corte.
+func (m *ProdeMatch) CutoffAt() (time.Time, error) {
+	if m.Tournament == nil {
+		return time.Time{}, fmt.Errorf("prode: partido %s: %w", m.ID, ErrTournamentNotLoaded)
+	}
+	return m.KickoffAt.In(m.Tournament.Location()).Add(-m.Tournament.Cutoff()), nil
 }
 
 // IsOpenForPrediction devuelve true si el partido sigue aceptando
 // predicciones en el momento indicado.
-func (m *ProdeMatch) IsOpenForPrediction(now time.Time) bool {
+func (m *ProdeMatch) IsOpenForPrediction(now time.Time) (bool, error) {
 	if m.Status != MatchStatusScheduled && m.Status != MatchStatusOpen {
-		return false
+		return false, nil
 	}
-	return now.Before(m.CutoffAt())
+	cutoff, err := m.CutoffAt()
+	if err != nil {
+		return false, err
+	}
+	return now.Before(cutoff), nil
 }
 
-// BeforeCutoff es un helper independiente que verifica si una hora
-// está antes del corte (kickoff - 1 hora en zona Argentina).
-func BeforeCutoff(kickoff time.Time, now time.Time) bool {
-	loc, _ := time.LoadLocation("America/Argentina/Buenos_Aires")
-	cutoff := kickoff.In(loc).Add(-1 * time.Hour)
-	return now.Before(cutoff)
+// Label devuelve "Local vs Visitante" para mails y logs.
+func (m *ProdeMatch) Label() string {
+	home, away := "?", "?"
+	if m.HomeTeam != nil {
+		home = m.HomeTeam.Name
+	}
+	if m.AwayTeam != nil {
+		away = m.AwayTeam.Name
+	}
+	return home + " vs " + away
 }
@@ -0,0 +1,47 @@
+package prode
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{MatchStatusDraft, MatchStatusScheduled, true},
+		{MatchStatusScheduled, MatchStatusOpen, true},
+		{MatchStatusOpen, MatchStatusClosed, true},
+		{MatchStatusClosed, MatchStatusResultRecorded, true},
+		{MatchStatusResultRecorded, MatchStatusEvaluated, true},
+		{MatchStatusOpen, MatchStatusOpen, true},
+		{MatchStatusEvaluated, MatchStatusEvaluated, true},
+		{MatchStatusClosed, MatchStatusOpen, false},
+		{MatchStatusOpen, MatchStatusEvaluated, false},
+		{MatchStatusEvaluated, MatchStatusOpen, false},
+		{MatchStatusCancelled, MatchStatusScheduled, false},
+		{MatchStatusDraft, "FINISHED", false},
+		{"FINISHED", "FINISHED", false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %t, want %t", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestTransitionErrorIs(t *testing.T) {
+	t.Parallel()
+
+	var err error = &TransitionError{From: MatchStatusEvaluated, To: MatchStatusOpen}
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("errors.Is(%v, ErrInvalidTransition) = false", err)
+	}
+	if errors.Is(err, ErrMatchNotFound) {
+		t.Fatalf("errors.Is(%v, ErrMatchNotFound) = true", err)
+	}
+}
@@ -9,20 +9,21 @@ import (
 
 // Constantes de estado de predicción
 const (
-	PredStatusPending  = "PENDING"
-	PredStatusCorrect  = "CORRECT"
+	PredStatusPending   = "PENDING"
+	PredStatusCorrect   = "CORRECT"
 	PredStatusIncorrect = "INCORRECT"
 )
 
 type ProdePrediction struct {
-	ID             uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	UserID         uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_prode_pred_user_match" json:"user_id"`
-	MatchID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_prode_pred_user_match" json:"match_id"`
-	ArgentinaGoals int       `gorm:"type:smallint;not null" json:"argentina_goals"`
-	OpponentGoals  int       `gorm:"type:smallint;not null" json:"opponent_goals"`
-	Status         string    `gorm:"type:varchar(30);not null;default:PENDING" json:"status"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_prode_pred_user_match" json:"user_id"`
+	MatchID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_prode_pred_user_match" json:"match_id"`
+	HomeGoals int       `gorm:"type:smallint;not null" json:"home_goals"`
+	AwayGoals int       `gorm:"type:smallint;not null" json:"away_goals"`
+	Status    string    `gorm:"type:varchar(30);not null;default:PENDING" json:"status"`
+	Points    *int      `gorm:"type:smallint" json:"points,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 func (ProdePrediction) TableName() string { return "prode_predictions" }
@@ -36,10 +37,10 @@ func (p *ProdePrediction) BeforeCreate(tx *gorm.DB) error {
 
 // ValidateScore verifica que los resultados estén dentro de rangos válidos.
 func (p *ProdePrediction) ValidateScore() error {
-	if p.ArgentinaGoals < 0 || p.OpponentGoals < 0 {
+	if p.HomeGoals < 0 || p.AwayGoals < 0 {
 		return ErrInvalidScore
 	}
-	if p.ArgentinaGoals > 50 || p.OpponentGoals > 50 {
+	if p.HomeGoals > 50 || p.AwayGoals > 50 {
 		return ErrScoreOutOfRange
 	}
 	return nil
@@ -0,0 +1,186 @@
+package prode
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/mailer"
+	"gorm.io/gorm"
+)
+
+// ProdeReminder registra que a un usuario ya se le recordó un partido. El
+// índice único evita mandar dos recordatorios del mismo partido aunque corran
+// dos pasadas a la vez.
+type ProdeReminder struct {
+	ID      uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	MatchID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_prode_reminder_match_user" json:"match_id"`
+	UserID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_prode_reminder_match_user;index" json:"user_id"`
+	SentAt  time.Time `gorm:"type:timestamptz;not null" json:"sent_at"`
+}
+
+func (ProdeReminder) TableName() string { return "prode_reminders" }
+
+func (r *ProdeReminder) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// ProdeReminderOptOut marca a un usuario que no quiere recibir recordatorios.
+type ProdeReminderOptOut struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (ProdeReminderOptOut) TableName() string { return "prode_reminder_opt_outs" }
+
+// ReminderResult resume una pasada del job de recordatorios.
+type ReminderResult struct {
+	Matches int
+	Sent    int
+	Failed  int
+}
+
+// SendReminders avisa a los participantes del torneo que todavía no
+// pronosticaron los partidos visibles cuyo corte cae dentro de lead. A cada
+// usuario se le recuerda un partido una sola vez; si el email falla el
+// registro se borra para reintentar en la próxima pasada. La primera pasada
+// de cada partido manda además un resumen de participación a los admins.
+func (s *Service) SendReminders(ctx context.Context, lead time.Duration) (ReminderResult, error) {
+	var res ReminderResult
+	now := s.clock.Now()
+
+	matches, err := s.repo.GetMatchesToRemind(ctx, now, now.Add(lead))
+	if err != nil {
+		return res, err
+	}
+
+	var errs []error
+	for i := range matches {
+		m := &matches[i]
+		sent, failed, err := s.remindMatch(ctx, m, now)
+		res.Sent += sent
+		res.Failed += failed
+		if err != nil {
+			slog.ErrorContext(ctx, "error al enviar recordatorios", "match_id", m.ID, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		res.Matches++
+
+		first, err := s.repo.MarkMatchReminded(ctx, m.ID, now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if first {
+			s.notifyParticipation(ctx, m)
+		}
+	}
+
+	return res, errors.Join(errs...)
+}
+
+// remindMatch manda el recordatorio de un partido a los usuarios pendientes.
+func (s *Service) remindMatch(ctx context.Context, match *ProdeMatch, now time.Time) (sent, failed int, err error) {
+	cutoff, err := match.CutoffAt()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	candidates, err := s.repo.GetReminderCandidates(ctx, match)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, c := range candidates {
+		if err := ctx.Err(); err != nil {
+			return sent, failed, err
+		}
+
+		claimed, err := s.repo.ClaimReminder(ctx, match.ID, c.UserID, now)
+		if err != nil {
+			return sent, failed, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.mailer.SendProdeReminderEmail(ctx, c.Email, c.Name, match.Label(), cutoff); err != nil {
+			failed++
+			slog.ErrorContext(ctx, "error al enviar recordatorio", "match_id", match.ID, "user_id", c.UserID, "error", err)
+			if rerr := s.repo.ReleaseReminder(ctx, match.ID, c.UserID); rerr != nil {
+				slog.ErrorContext(ctx, "error al liberar recordatorio", "match_id", match.ID, "user_id", c.UserID, "error", rerr)
+			}
+			continue
+		}
+		sent++
+	}
+
+	return sent, failed, nil
+}
+
+// notifyParticipation manda a los admins cuántos pronosticaron el partido y a
+// cuántos se les recordó.
+func (s *Service) notifyParticipation(ctx context.Context, match *ProdeMatch) {
+	if len(s.adminEmails) == 0 {
+		return
+	}
+
+	predictions, err := s.repo.CountPredictions(ctx, match.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "error al contar predicciones", "match_id", match.ID, "error", err)
+		return
+	}
+	reminded, err := s.repo.CountReminders(ctx, match.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "error al contar recordatorios", "match_id", match.ID, "error", err)
+		return
+	}
+
+	summary, err := participationSummary(match, predictions, reminded)
+	if err != nil {
+		slog.ErrorContext(ctx, "error al armar resumen de participación", "match_id", match.ID, "error", err)
+		return
+	}
+	for _, email := range s.adminEmails {
+		if err := s.mailer.SendProdeParticipationSummary(ctx, email, summary); err != nil {
+			slog.ErrorContext(ctx, "error al enviar resumen de participación", "email", email, "error", err)
+		}
+	}
+}
+
+func participationSummary(match *ProdeMatch, predictions, reminded int64) (mailer.ProdeParticipation, error) {
+	cutoff, err := match.CutoffAt()
+	if err != nil {
+		return mailer.ProdeParticipation{}, err
+	}
+	return mailer.ProdeParticipation{
+		Match:       match.Label(),
+		Stage:       match.Stage,
+		CutoffAt:    cutoff,
+		Predictions: int(predictions),
+		Reminded:    int(reminded),
+	}, nil
+}
+
+// GetReminderPreference indica si el usuario recibe recordatorios.
+func (s *Service) GetReminderPreference(ctx context.Context, userID uuid.UUID) (*ReminderPreferenceResponse, error) {
+	optedOut, err := s.repo.IsReminderOptedOut(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &ReminderPreferenceResponse{Enabled: !optedOut}, nil
+}
+
+// SetReminderPreference activa o desactiva los recordatorios del usuario.
+func (s *Service) SetReminderPreference(ctx context.Context, userID uuid.UUID, enabled bool) (*ReminderPreferenceResponse, error) {
+	if err := s.repo.SetReminderOptOut(ctx, userID, !enabled); err != nil {
+		return nil, err
+	}
+	return &ReminderPreferenceResponse{Enabled: enabled}, nil
+}
@@ -0,0 +1,40 @@
+package prode
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParticipationSummary(t *testing.T) {
+	t.Parallel()
+
+	kickoff := time.Date(2026, 6, 14, 19, 0, 0, 0, time.UTC)
+	match := &ProdeMatch{
+		Stage:      "Fase de grupos",
+		KickoffAt:  kickoff,
+		Tournament: &ProdeTournament{Timezone: DefaultTimezone, CutoffMinutes: 30},
+		HomeTeam:   &ProdeTeam{Name: "Argentina"},
+		AwayTeam:   &ProdeTeam{Name: "Argelia"},
+	}
+
+	got, err := participationSummary(match, 120, 35)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Match != match.Label() {
+		t.Errorf("Match = %q, want %q", got.Match, match.Label())
+	}
+	if got.Stage != "Fase de grupos" {
+		t.Errorf("Stage = %q, want Fase de grupos", got.Stage)
+	}
+	if want := kickoff.Add(-30 * time.Minute); !got.CutoffAt.Equal(want) {
+		t.Errorf("CutoffAt = %v, want %v", got.CutoffAt, want)
+	}
+	if loc := got.CutoffAt.Location().String(); loc != DefaultTimezone {
+		t.Errorf("CutoffAt location = %s, want %s", loc, DefaultTimezone)
+	}
+	if got.Predictions != 120 || got.Reminded != 35 {
+		t.Errorf("counts = %d/%d, want 120/35", got.Predictions, got.Reminded)
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Repository brinda persistencia vía GORM para las entidades PRODE.
@@ -30,21 +32,26 @@ func (r *Repository) DB() *gorm.DB {
 	return r.db
 }
 
+// matches arma la consulta de partidos con torneo y equipos cargados.
+func (r *Repository) matches(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Preload("Tournament").Preload("HomeTeam").Preload("AwayTeam")
+}
+
 // GetMatchByID obtiene un partido por su ID.
 func (r *Repository) GetMatchByID(ctx context.Context, id uuid.UUID) (*ProdeMatch, error) {
 	var match ProdeMatch
-	err := r.db.WithContext(ctx).First(&match, "id = ?", id).Error
+	err := r.matches(ctx).First(&match, "id = ?", id).Error
 	if err != nil {
 		return nil, mapProdeMatchErr(ctx, "get match by id", err)
 	}
 	return &match, nil
 }
 
-// GetVisibleMatches obtiene todos los partidos marcados como visibles.
-func (r *Repository) GetVisibleMatches(ctx context.Context) ([]ProdeMatch, error) {
+// GetVisibleMatches obtiene los partidos visibles de un torneo.
+func (r *Repository) GetVisibleMatches(ctx context.Context, tournamentID uuid.UUID) ([]ProdeMatch, error) {
 	var matches []ProdeMatch
-	err := r.db.WithContext(ctx).
-		Where("is_visible = ?", true).
+	err := r.matches(ctx).
+		Where("tournament_id = ? AND is_visible = ?", tournamentID, true).
 		Order("kickoff_at ASC").
 		Find(&matches).Error
 	if err != nil {
@@ -91,45 +98,100 @@ func (r *Repository) GetUserPrediction(ctx context.Context, userID uuid.UUID, ma
 }
 
 // UpsertPrediction crea o actualiza la predicción de un usuario para un partido.
-// Usa el unique index (user_id, match_id) para determinar si es inserción o actualización.
-func (r *Repository) UpsertPrediction(ctx context.Context, pred *ProdePrediction) (*ProdePrediction, error) {
+// Usa el unique index (user_id, match_id) para determinar si es inserción o
+// actualización. En la misma transacción agrega la revisión con la hora at y
+// el request que hizo el cambio.
+func (r *Repository) UpsertPrediction(ctx context.Context, pred *ProdePrediction, at time.Time, requestID string) (*ProdePrediction, error) {
 	if pred.ID == uuid.Nil {
 		pred.ID = uuid.New()
 	}
 
-	// Buscar si ya existe una predicción para este usuario y partido
-	var existing ProdePrediction
-	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND match_id = ?", pred.UserID, pred.MatchID).
-		First(&existing).Error
+	saved := pred
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Buscar si ya existe una predicción para este usuario y partido
+		var existing ProdePrediction
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND match_id = ?", pred.UserID, pred.MatchID).
+			First(&existing).Error
 
-	if err == nil {
-		// Ya existe → actualizar
-		existing.ArgentinaGoals = pred.ArgentinaGoals
-		existing.OpponentGoals = pred.OpponentGoals
-		existing.Status = PredStatusPending
-		if err := r.db.WithContext(ctx).Save(&existing).Error; err != nil {
-			return nil, mapProdeRepoErr(ctx, "upsert prediction save", err)
+		switch {
+		case err == nil:
+			// Ya existe → actualizar
+			existing.HomeGoals = pred.HomeGoals
+			existing.AwayGoals = pred.AwayGoals
+			existing.Status = PredStatusPending
+			if err := tx.Save(&existing).Error; err != nil {
+				return err
+			}
+			saved = &existing
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			// No existe → crear
+			if err := tx.Create(pred).Error; err != nil {
+				return err
+			}
+		default:
+			return err
 		}
-		return &existing, nil
+
+		return tx.Create(&ProdePredictionRevision{
+			PredictionID: saved.ID,
+			UserID:       saved.UserID,
+			MatchID:      saved.MatchID,
+			HomeGoals:    saved.HomeGoals,
+			AwayGoals:    saved.AwayGoals,
+			RequestID:    requestID,
+			CreatedAt:    at,
+		}).Error
+	})
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "upsert prediction", err)
 	}
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		// No existe → crear
-		if err := r.db.WithContext(ctx).Create(pred).Error; err != nil {
-			return nil, mapProdeRepoErr(ctx, "upsert prediction create", err)
-		}
-		return pred, nil
+	return saved, nil
+}
+
+// GetPredictionRevisions obtiene las revisiones de la predicción del usuario
+// para un partido, de la más vieja a la más nueva.
+func (r *Repository) GetPredictionRevisions(ctx context.Context, userID, matchID uuid.UUID) ([]ProdePredictionRevision, error) {
+	var revs []ProdePredictionRevision
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND match_id = ?", userID, matchID).
+		Order("created_at ASC, id ASC").
+		Find(&revs).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get prediction revisions", err)
+	}
+	return revs, nil
+}
+
+// GetLockedRevisions obtiene, para cada predicción, la última revisión
+// guardada antes del corte. Las predicciones sin revisión previa al corte no
+// aparecen en el mapa.
+func (r *Repository) GetLockedRevisions(ctx context.Context, predictionIDs []uuid.UUID, cutoff time.Time) (map[uuid.UUID]ProdePredictionRevision, error) {
+	var revs []ProdePredictionRevision
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT DISTINCT ON (prediction_id) *
+			FROM prode_prediction_revisions
+			WHERE prediction_id IN ? AND created_at < ?
+			ORDER BY prediction_id, created_at DESC, id DESC`, predictionIDs, cutoff).
+		Scan(&revs).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get locked revisions", err)
 	}
 
-	return nil, mapProdeRepoErr(ctx, "upsert prediction lookup", err)
+	locked := make(map[uuid.UUID]ProdePredictionRevision, len(revs))
+	for _, rev := range revs {
+		locked[rev.PredictionID] = rev
+	}
+	return locked, nil
 }
 
-// GetPredictionsByUserID obtiene todas las predicciones de un usuario.
-func (r *Repository) GetPredictionsByUserID(ctx context.Context, userID uuid.UUID) ([]ProdePrediction, error) {
+// GetPredictionsByUserID obtiene las predicciones de un usuario en un torneo.
+func (r *Repository) GetPredictionsByUserID(ctx context.Context, userID, tournamentID uuid.UUID) ([]ProdePrediction, error) {
 	var predictions []ProdePrediction
 	err := r.db.WithContext(ctx).
-		Where("user_id = ?", userID).
-		Order("created_at DESC").
+		Joins("JOIN prode_matches m ON m.id = prode_predictions.match_id").
+		Where("prode_predictions.user_id = ? AND m.tournament_id = ?", userID, tournamentID).
+		Order("prode_predictions.created_at DESC").
 		Find(&predictions).Error
 	if err != nil {
 		return nil, mapProdeRepoErr(ctx, "get predictions by user id", err)
@@ -139,7 +201,7 @@ func (r *Repository) GetPredictionsByUserID(ctx context.Context, userID uuid.UUI
 
 // CreateMatch inserta un nuevo partido.
 func (r *Repository) CreateMatch(ctx context.Context, match *ProdeMatch) error {
-	if err := r.db.WithContext(ctx).Create(match).Error; err != nil {
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Create(match).Error; err != nil {
 		return mapProdeRepoErr(ctx, "create match", err)
 	}
 	return nil
@@ -147,12 +209,618 @@ func (r *Repository) CreateMatch(ctx context.Context, match *ProdeMatch) error {
 
 // UpdateMatch guarda los cambios de un partido existente.
 func (r *Repository) UpdateMatch(ctx context.Context, match *ProdeMatch) error {
-	if err := r.db.WithContext(ctx).Save(match).Error; err != nil {
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Save(match).Error; err != nil {
 		return mapProdeRepoErr(ctx, "update match", err)
 	}
 	return nil
 }
 
+// RescheduleMatch cambia solo el horario de un partido si su estado es uno de
+// statuses. Devuelve false si el partido ya no estaba en esos estados.
+func (r *Repository) RescheduleMatch(ctx context.Context, matchID uuid.UUID, kickoffAt time.Time, statuses []string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&ProdeMatch{}).
+		Where("id = ? AND status IN ?", matchID, statuses).
+		Update("kickoff_at", kickoffAt)
+	if res.Error != nil {
+		return false, mapProdeRepoErr(ctx, "reschedule match", res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// cutoffSQL es la hora de corte de cada partido según los minutos de su
+// torneo.
+const cutoffSQL = `kickoff_at - COALESCE(
+	(SELECT t.cutoff_minutes FROM prode_tournaments t WHERE t.id = prode_matches.tournament_id), 60
+) * INTERVAL '1 minute'`
+
+// OpenDueMatches pasa a OPEN los partidos visibles programados que arrancan
+// antes de openUntil y todavía no llegaron al corte. Devuelve cuántos abrió.
+func (r *Repository) OpenDueMatches(ctx context.Context, now, openUntil time.Time) (int64, error) {
+	return r.transitionDue(ctx, "open due matches", MatchStatusScheduled, MatchStatusOpen, func(db *gorm.DB) *gorm.DB {
+		return db.Where("is_visible = ?", true).
+			Where("kickoff_at <= ?", openUntil).
+			Where(cutoffSQL+" > ?", now)
+	})
+}
+
+// CloseDueMatches pasa a CLOSED los partidos programados o abiertos cuyo
+// corte ya pasó. Devuelve cuántos cerró.
+func (r *Repository) CloseDueMatches(ctx context.Context, now time.Time) (int64, error) {
+	due := func(db *gorm.DB) *gorm.DB {
+		return db.Where(cutoffSQL+" <= ?", now)
+	}
+
+	var total int64
+	for _, from := range []string{MatchStatusScheduled, MatchStatusOpen} {
+		n, err := r.transitionDue(ctx, "close due matches", from, MatchStatusClosed, due)
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// transitionDue mueve de from a to los partidos que cumplen scope y deja
+// registrada cada transición, todo en la misma transacción.
+func (r *Repository) transitionDue(ctx context.Context, action, from, to string, scope func(*gorm.DB) *gorm.DB) (int64, error) {
+	var moved []ProdeMatch
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := scope(tx.Model(&moved)).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Where("status = ?", from).
+			Update("status", to).Error
+		if err != nil || len(moved) == 0 {
+			return err
+		}
+
+		history := make([]ProdeMatchTransition, len(moved))
+		for i, m := range moved {
+			history[i] = ProdeMatchTransition{MatchID: m.ID, FromStatus: from, ToStatus: to, Actor: ActorScheduler}
+		}
+		return tx.Create(&history).Error
+	})
+	if err != nil {
+		return 0, mapProdeRepoErr(ctx, action, err)
+	}
+	return int64(len(moved)), nil
+}
+
+// SaveMatchTransition guarda el partido y registra el paso desde from al
+// estado actual en la misma transacción. Si el estado no cambió y no hay
+// reason, solo guarda el partido.
+func (r *Repository) SaveMatchTransition(ctx context.Context, match *ProdeMatch, from, actor, reason string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(match).Error; err != nil {
+			return err
+		}
+		if from == match.Status && reason == "" {
+			return nil
+		}
+		return tx.Create(&ProdeMatchTransition{
+			MatchID:    match.ID,
+			FromStatus: from,
+			ToStatus:   match.Status,
+			Actor:      actor,
+			Reason:     reason,
+		}).Error
+	})
+	if err != nil {
+		return mapProdeRepoErr(ctx, "save match transition", err)
+	}
+	return nil
+}
+
+// SaveMatchResult guarda el resultado como SaveMatchTransition, pero con el
+// partido bloqueado: si el marcador cambia y el settlement ya puntuó alguna
+// predicción devuelve ErrResultSettled, porque las tandas pendientes se
+// evaluarían con otro resultado.
+func (r *Repository) SaveMatchResult(ctx context.Context, match *ProdeMatch, from, actor, reason string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current ProdeMatch
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&current, "id = ?", match.ID).Error; err != nil {
+			return err
+		}
+		var settled int64
+		if err := tx.Model(&ProdePrediction{}).
+			Where("match_id = ? AND points IS NOT NULL", match.ID).
+			Count(&settled).Error; err != nil {
+			return err
+		}
+		if err := checkResultChange(&current, *match.HomeGoals, *match.AwayGoals, settled); err != nil {
+			return err
+		}
+		if err := tx.Omit(clause.Associations).Save(match).Error; err != nil {
+			return err
+		}
+		return tx.Create(&ProdeMatchTransition{
+			MatchID:    match.ID,
+			FromStatus: from,
+			ToStatus:   match.Status,
+			Actor:      actor,
+			Reason:     reason,
+		}).Error
+	})
+	if errors.Is(err, ErrResultSettled) {
+		return fmt.Errorf("prode: save match result: %w", err)
+	}
+	if err != nil {
+		return mapProdeMatchErr(ctx, "save match result", err)
+	}
+	return nil
+}
+
+// LockRecordedResult toma un bloqueo compartido sobre el partido dentro de la
+// transacción de una tanda, siempre que siga en RESULT_RECORDED con homeGoals
+// a awayGoals. Así SaveMatchResult no puede cambiar el marcador mientras la
+// tanda puntúa. Si el partido cambió devuelve ErrInvalidTransition.
+func (r *Repository) LockRecordedResult(ctx context.Context, matchID uuid.UUID, homeGoals, awayGoals int) error {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&ProdeMatch{}).
+		Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("id = ? AND status = ? AND home_goals = ? AND away_goals = ?",
+			matchID, MatchStatusResultRecorded, homeGoals, awayGoals).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return mapProdeRepoErr(ctx, "lock recorded result", err)
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("prode: lock recorded result: %w", ErrInvalidTransition)
+	}
+	return nil
+}
+
+// MarkMatchEvaluated pasa el partido de RESULT_RECORDED a EVALUATED y
+// registra la transición, siempre que el resultado siga siendo homeGoals a
+// awayGoals. Si el partido cambió devuelve ErrInvalidTransition.
+func (r *Repository) MarkMatchEvaluated(ctx context.Context, matchID uuid.UUID, homeGoals, awayGoals int, actor string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&ProdeMatch{}).
+			Where("id = ? AND status = ? AND home_goals = ? AND away_goals = ?",
+				matchID, MatchStatusResultRecorded, homeGoals, awayGoals).
+			Update("status", MatchStatusEvaluated)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrInvalidTransition
+		}
+		return tx.Create(&ProdeMatchTransition{
+			MatchID:    matchID,
+			FromStatus: MatchStatusResultRecorded,
+			ToStatus:   MatchStatusEvaluated,
+			Actor:      actor,
+		}).Error
+	})
+	if errors.Is(err, ErrInvalidTransition) {
+		return fmt.Errorf("prode: mark match evaluated: %w", err)
+	}
+	if err != nil {
+		return mapProdeRepoErr(ctx, "mark match evaluated", err)
+	}
+	return nil
+}
+
+// CorrectMatchResult cambia el resultado de un partido evaluado y registra la
+// transición en la misma transacción. Solo actualiza si el partido sigue
+// evaluado con el resultado from; si otro proceso lo cambió devuelve
+// ErrInvalidTransition.
+func (r *Repository) CorrectMatchResult(ctx context.Context, matchID uuid.UUID, fromHome, fromAway, toHome, toAway int, actor, reason string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&ProdeMatch{}).
+			Where("id = ? AND status = ? AND home_goals = ? AND away_goals = ?",
+				matchID, MatchStatusEvaluated, fromHome, fromAway).
+			Updates(map[string]any{"home_goals": toHome, "away_goals": toAway})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrInvalidTransition
+		}
+		return tx.Create(&ProdeMatchTransition{
+			MatchID:    matchID,
+			FromStatus: MatchStatusEvaluated,
+			ToStatus:   MatchStatusEvaluated,
+			Actor:      actor,
+			Reason:     reason,
+		}).Error
+	})
+	if errors.Is(err, ErrInvalidTransition) {
+		return fmt.Errorf("prode: correct match result: %w", err)
+	}
+	if err != nil {
+		return mapProdeRepoErr(ctx, "correct match result", err)
+	}
+	return nil
+}
+
+// StartSettlementRun registra una corrida nueva si no hay otra en curso para
+// el partido. Bloquea la fila del partido para que dos corridas no arranquen a
+// la vez; las que quedaron en RUNNING más de staleAfter se dan por fallidas.
+func (r *Repository) StartSettlementRun(ctx context.Context, run *ProdeSettlementRun, staleAfter time.Duration) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var match ProdeMatch
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&match, "id = ?", run.MatchID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&ProdeSettlementRun{}).
+			Where("match_id = ? AND status = ? AND started_at < ?", run.MatchID, SettlementRunning, run.StartedAt.Add(-staleAfter)).
+			Updates(map[string]any{
+				"status":      SettlementFailed,
+				"last_error":  "corrida abandonada",
+				"finished_at": run.StartedAt,
+			}).Error; err != nil {
+			return err
+		}
+
+		var running int64
+		if err := tx.Model(&ProdeSettlementRun{}).
+			Where("match_id = ? AND status = ?", run.MatchID, SettlementRunning).
+			Count(&running).Error; err != nil {
+			return err
+		}
+		if running > 0 {
+			return ErrSettlementRunning
+		}
+
+		var total int64
+		if err := tx.Model(&ProdePrediction{}).
+			Where("match_id = ? AND points IS NULL", run.MatchID).
+			Count(&total).Error; err != nil {
+			return err
+		}
+		run.Total = int(total)
+
+		return tx.Create(run).Error
+	})
+	if errors.Is(err, ErrSettlementRunning) {
+		return fmt.Errorf("prode: start settlement run: %w", ErrSettlementRunning)
+	}
+	if err != nil {
+		return mapProdeMatchErr(ctx, "start settlement run", err)
+	}
+	return nil
+}
+
+// UpdateSettlementRun guarda el avance de una corrida.
+func (r *Repository) UpdateSettlementRun(ctx context.Context, run *ProdeSettlementRun) error {
+	if err := r.db.WithContext(ctx).Save(run).Error; err != nil {
+		return mapProdeRepoErr(ctx, "update settlement run", err)
+	}
+	return nil
+}
+
+// GetSettlementRuns obtiene las últimas corridas de settlement de un partido.
+func (r *Repository) GetSettlementRuns(ctx context.Context, matchID uuid.UUID, limit int) ([]ProdeSettlementRun, error) {
+	var runs []ProdeSettlementRun
+	err := r.db.WithContext(ctx).
+		Where("match_id = ?", matchID).
+		Order("started_at DESC").
+		Limit(limit).
+		Find(&runs).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get settlement runs", err)
+	}
+	return runs, nil
+}
+
+// GetUnsettledPredictions obtiene la siguiente tanda de predicciones sin
+// evaluar de un partido, en orden de ID a partir de after.
+func (r *Repository) GetUnsettledPredictions(ctx context.Context, matchID, after uuid.UUID, limit int) ([]ProdePrediction, error) {
+	var predictions []ProdePrediction
+	err := r.db.WithContext(ctx).
+		Where("match_id = ? AND points IS NULL AND id > ?", matchID, after).
+		Order("id ASC").
+		Limit(limit).
+		Find(&predictions).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get unsettled predictions", err)
+	}
+	return predictions, nil
+}
+
+// GetPredictionsAfter lista las predicciones de un partido con id mayor a
+// after, de a limit y ordenadas por id.
+func (r *Repository) GetPredictionsAfter(ctx context.Context, matchID, after uuid.UUID, limit int) ([]ProdePrediction, error) {
+	var predictions []ProdePrediction
+	err := r.db.WithContext(ctx).
+		Where("match_id = ? AND id > ?", matchID, after).
+		Order("id ASC").
+		Limit(limit).
+		Find(&predictions).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get predictions after", err)
+	}
+	return predictions, nil
+}
+
+// CountUnsettledPredictions cuenta las predicciones sin evaluar de un partido.
+func (r *Repository) CountUnsettledPredictions(ctx context.Context, matchID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&ProdePrediction{}).
+		Where("match_id = ? AND points IS NULL", matchID).
+		Count(&count).Error
+	if err != nil {
+		return 0, mapProdeRepoErr(ctx, "count unsettled predictions", err)
+	}
+	return count, nil
+}
+
+// ScoreRow es la cantidad de predicciones de un resultado.
+type ScoreRow struct {
+	HomeGoals int
+	AwayGoals int
+	Count     int64
+}
+
+// GetScoreDistribution cuenta las predicciones de un partido por resultado,
+// de la más repetida a la menos.
+func (r *Repository) GetScoreDistribution(ctx context.Context, matchID uuid.UUID) ([]ScoreRow, error) {
+	var rows []ScoreRow
+	err := r.db.WithContext(ctx).
+		Model(&ProdePrediction{}).
+		Select("home_goals, away_goals, COUNT(*) AS count").
+		Where("match_id = ?", matchID).
+		Group("home_goals, away_goals").
+		Order("count DESC, home_goals ASC, away_goals ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get score distribution", err)
+	}
+	return rows, nil
+}
+
+// HourRow es la cantidad de predicciones creadas en una hora.
+type HourRow struct {
+	Hour  time.Time
+	Count int64
+}
+
+// GetPredictionsPerHour cuenta las predicciones de un partido por hora de
+// creación.
+func (r *Repository) GetPredictionsPerHour(ctx context.Context, matchID uuid.UUID) ([]HourRow, error) {
+	var rows []HourRow
+	err := r.db.WithContext(ctx).
+		Model(&ProdePrediction{}).
+		Select("date_trunc('hour', created_at) AS hour, COUNT(*) AS count").
+		Where("match_id = ?", matchID).
+		Group("hour").
+		Order("hour ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get predictions per hour", err)
+	}
+	return rows, nil
+}
+
+// WinnerRow es un acierto exacto con los datos del usuario y su premio.
+type WinnerRow struct {
+	Name         string
+	Email        string
+	HomeGoals    int
+	AwayGoals    int
+	Points       *int
+	RewardStatus string
+	VoucherID    *uuid.UUID
+	PredictedAt  time.Time
+}
+
+// GetMatchWinners obtiene las predicciones correctas de un partido con el
+// usuario y, si tiene, el premio.
+func (r *Repository) GetMatchWinners(ctx context.Context, matchID uuid.UUID) ([]WinnerRow, error) {
+	var rows []WinnerRow
+	err := r.db.WithContext(ctx).
+		Table("prode_predictions p").
+		Select(`u.name, u.email, p.home_goals, p.away_goals, p.points,
+			COALESCE(rw.status, '') AS reward_status, rw.voucher_id, p.created_at AS predicted_at`).
+		Joins("JOIN users u ON u.id = p.user_id").
+		Joins("LEFT JOIN prode_rewards rw ON rw.prediction_id = p.id").
+		Where("p.match_id = ? AND p.status = ?", matchID, PredStatusCorrect).
+		Order("u.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get match winners", err)
+	}
+	return rows, nil
+}
+
+// GetMatchTransitions obtiene el historial de estados de un partido.
+func (r *Repository) GetMatchTransitions(ctx context.Context, matchID uuid.UUID) ([]ProdeMatchTransition, error) {
+	var history []ProdeMatchTransition
+	err := r.db.WithContext(ctx).
+		Where("match_id = ?", matchID).
+		Order("created_at ASC").
+		Find(&history).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get match transitions", err)
+	}
+	return history, nil
+}
+
+// GetMatchByExternalID obtiene un partido por su id en el feed. Devuelve nil
+// si no existe.
+func (r *Repository) GetMatchByExternalID(ctx context.Context, externalID string) (*ProdeMatch, error) {
+	var match ProdeMatch
+	err := r.matches(ctx).First(&match, "external_id = ?", externalID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, mapProdeRepoErr(ctx, "get match by external id", err)
+	}
+	return &match, nil
+}
+
+// GetMatchesAwaitingResult obtiene los partidos del feed que ya empezaron y
+// todavía no tienen resultado.
+func (r *Repository) GetMatchesAwaitingResult(ctx context.Context, now time.Time) ([]ProdeMatch, error) {
+	var matches []ProdeMatch
+	err := r.matches(ctx).
+		Where("external_id <> ''").
+		Where("status IN ?", []string{MatchStatusScheduled, MatchStatusOpen, MatchStatusClosed}).
+		Where("kickoff_at <= ?", now).
+		Order("kickoff_at ASC").
+		Find(&matches).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get matches awaiting result", err)
+	}
+	return matches, nil
+}
+
+// GetMatchesByStatus obtiene los partidos en el estado indicado.
+func (r *Repository) GetMatchesByStatus(ctx context.Context, status string) ([]ProdeMatch, error) {
+	var matches []ProdeMatch
+	err := r.matches(ctx).
+		Where("status = ?", status).
+		Order("kickoff_at ASC").
+		Find(&matches).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get matches by status", err)
+	}
+	return matches, nil
+}
+
+// GetMatchesToRemind obtiene los partidos visibles que siguen abiertos a
+// predicciones y cuyo corte cae entre now y until.
+func (r *Repository) GetMatchesToRemind(ctx context.Context, now, until time.Time) ([]ProdeMatch, error) {
+	var matches []ProdeMatch
+	err := r.matches(ctx).
+		Where("is_visible = ?", true).
+		Where("status IN ?", []string{MatchStatusScheduled, MatchStatusOpen}).
+		Where(cutoffSQL+" > ?", now).
+		Where(cutoffSQL+" <= ?", until).
+		Order("kickoff_at ASC").
+		Find(&matches).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get matches to remind", err)
+	}
+	return matches, nil
+}
+
+// ReminderCandidate es un usuario al que falta recordarle un partido.
+type ReminderCandidate struct {
+	UserID uuid.UUID
+	Name   string
+	Email  string
+}
+
+// GetReminderCandidates obtiene los participantes del torneo del partido
+// (pronosticaron algún partido o están en una liga) que no pronosticaron este
+// partido, no desactivaron los recordatorios y todavía no fueron avisados.
+func (r *Repository) GetReminderCandidates(ctx context.Context, match *ProdeMatch) ([]ReminderCandidate, error) {
+	var rows []ReminderCandidate
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT u.id AS user_id, u.name, u.email
+		FROM users u
+		WHERE (
+			EXISTS (
+				SELECT 1 FROM prode_predictions p
+				JOIN prode_matches m ON m.id = p.match_id
+				WHERE p.user_id = u.id AND m.tournament_id = ?
+			) OR EXISTS (
+				SELECT 1 FROM prode_league_members lm
+				JOIN prode_leagues l ON l.id = lm.league_id
+				WHERE lm.user_id = u.id AND l.tournament_id = ?
+			)
+		)
+		AND NOT EXISTS (SELECT 1 FROM prode_predictions p WHERE p.user_id = u.id AND p.match_id = ?)
+		AND NOT EXISTS (SELECT 1 FROM prode_reminder_opt_outs o WHERE o.user_id = u.id)
+		AND NOT EXISTS (SELECT 1 FROM prode_reminders rm WHERE rm.user_id = u.id AND rm.match_id = ?)
+		ORDER BY u.id`, match.TournamentID, match.TournamentID, match.ID, match.ID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get reminder candidates", err)
+	}
+	return rows, nil
+}
+
+// ClaimReminder registra el recordatorio del usuario para el partido. Devuelve
+// false si ya existía, así dos pasadas concurrentes no avisan dos veces.
+func (r *Repository) ClaimReminder(ctx context.Context, matchID, userID uuid.UUID, now time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&ProdeReminder{MatchID: matchID, UserID: userID, SentAt: now})
+	if res.Error != nil {
+		return false, mapProdeRepoErr(ctx, "claim reminder", res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// ReleaseReminder borra el recordatorio para que se reintente.
+func (r *Repository) ReleaseReminder(ctx context.Context, matchID, userID uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Where("match_id = ? AND user_id = ?", matchID, userID).
+		Delete(&ProdeReminder{}).Error
+	if err != nil {
+		return mapProdeRepoErr(ctx, "release reminder", err)
+	}
+	return nil
+}
+
+// MarkMatchReminded guarda la primera pasada de recordatorios del partido.
+// Devuelve true solo para la pasada que lo marcó.
+func (r *Repository) MarkMatchReminded(ctx context.Context, matchID uuid.UUID, now time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&ProdeMatch{}).
+		Where("id = ? AND reminded_at IS NULL", matchID).
+		Update("reminded_at", now)
+	if res.Error != nil {
+		return false, mapProdeRepoErr(ctx, "mark match reminded", res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// CountPredictions cuenta las predicciones de un partido.
+func (r *Repository) CountPredictions(ctx context.Context, matchID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&ProdePrediction{}).Where("match_id = ?", matchID).Count(&count).Error
+	if err != nil {
+		return 0, mapProdeRepoErr(ctx, "count predictions", err)
+	}
+	return count, nil
+}
+
+// CountReminders cuenta los recordatorios enviados de un partido.
+func (r *Repository) CountReminders(ctx context.Context, matchID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&ProdeReminder{}).Where("match_id = ?", matchID).Count(&count).Error
+	if err != nil {
+		return 0, mapProdeRepoErr(ctx, "count reminders", err)
+	}
+	return count, nil
+}
+
+// IsReminderOptedOut indica si el usuario desactivó los recordatorios.
+func (r *Repository) IsReminderOptedOut(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&ProdeReminderOptOut{}).Where("user_id = ?", userID).Count(&count).Error
+	if err != nil {
+		return false, mapProdeRepoErr(ctx, "get reminder opt-out", err)
+	}
+	return count > 0, nil
+}
+
+// SetReminderOptOut guarda o borra la baja de recordatorios del usuario.
+func (r *Repository) SetReminderOptOut(ctx context.Context, userID uuid.UUID, optOut bool) error {
+	db := r.db.WithContext(ctx)
+	var err error
+	if optOut {
+		err = db.Clauses(clause.OnConflict{DoNothing: true}).Create(&ProdeReminderOptOut{UserID: userID}).Error
+	} else {
+		err = db.Where("user_id = ?", userID).Delete(&ProdeReminderOptOut{}).Error
+	}
+	if err != nil {
+		return mapProdeRepoErr(ctx, "set reminder opt-out", err)
+	}
+	return nil
+}
+
 // CreateReward inserta un nuevo premio en el ledger.
 func (r *Repository) CreateReward(ctx context.Context, reward *ProdeReward) error {
 	if err := r.db.WithContext(ctx).Create(reward).Error; err != nil {
@@ -213,6 +881,462 @@ func (r *Repository) CountPendingInventoryRewards(ctx context.Context) (int, err
 	return int(count), nil
 }
 
+// leaderboardCTE suma los puntos de las predicciones ya evaluadas de un
+// torneo (primer parámetro) y numera a
+// los usuarios. Los empates se rompen por cantidad de exactos, después por
+// quién predijo primero y al final por user_id, así el orden es estable.
+const leaderboardCTE = `
+WITH standings AS (
+	SELECT p.user_id,
+	       SUM(p.points) AS points,
+	       COUNT(*) FILTER (WHERE p.status = 'CORRECT') AS exact_hits,
+	       COUNT(*) AS predictions,
+	       MIN(p.created_at) AS first_prediction_at
+	FROM prode_predictions p
+	JOIN prode_matches m ON m.id = p.match_id
+	WHERE p.points IS NOT NULL AND m.tournament_id = ?
+	GROUP BY p.user_id
+)` + rankedCTE
+
+// leagueLeaderboardCTE es la misma tabla restringida a los miembros de una
+// liga (torneo y liga como parámetros). Los miembros sin predicciones
+// evaluadas quedan con 0 puntos al final.
+const leagueLeaderboardCTE = `
+WITH standings AS (
+	SELECT lm.user_id,
+	       COALESCE(SUM(p.points), 0) AS points,
+	       COUNT(p.id) FILTER (WHERE p.status = 'CORRECT') AS exact_hits,
+	       COUNT(p.id) AS predictions,
+	       MIN(p.created_at) AS first_prediction_at
+	FROM prode_league_members lm
+	LEFT JOIN prode_predictions p ON p.user_id = lm.user_id
+	      AND p.points IS NOT NULL
+	      AND p.match_id IN (SELECT id FROM prode_matches WHERE tournament_id = ?)
+	WHERE lm.league_id = ?
+	GROUP BY lm.user_id
+)` + rankedCTE
+
+const rankedCTE = `, ranked AS (
+	SELECT ROW_NUMBER() OVER (
+	           ORDER BY s.points DESC, s.exact_hits DESC, s.first_prediction_at ASC NULLS LAST, s.user_id ASC
+	       ) AS rank,
+	       s.user_id, u.name, s.points, s.exact_hits, s.predictions
+	FROM standings s
+	JOIN users u ON u.id = s.user_id
+)`
+
+// LeaderboardRow es una fila de la tabla general tal como sale de la query.
+type LeaderboardRow struct {
+	Rank        int
+	UserID      uuid.UUID
+	Name        string
+	Points      int
+	ExactHits   int
+	Predictions int
+}
+
+// GetLeaderboard devuelve una página de la tabla de un torneo y el total de
+// usuarios con puntos.
+func (r *Repository) GetLeaderboard(ctx context.Context, tournamentID uuid.UUID, page, pageSize int) ([]LeaderboardRow, int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).
+		Model(&ProdePrediction{}).
+		Joins("JOIN prode_matches m ON m.id = prode_predictions.match_id").
+		Where("prode_predictions.points IS NOT NULL AND m.tournament_id = ?", tournamentID).
+		Distinct("prode_predictions.user_id").
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, mapProdeRepoErr(ctx, "count leaderboard", err)
+	}
+
+	var rows []LeaderboardRow
+	err = r.db.WithContext(ctx).
+		Raw(leaderboardCTE+` SELECT * FROM ranked ORDER BY rank LIMIT ? OFFSET ?`, tournamentID, pageSize, (page-1)*pageSize).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, mapProdeRepoErr(ctx, "get leaderboard", err)
+	}
+	return rows, total, nil
+}
+
+// GetLeaderboardEntry devuelve la posición de un usuario en un torneo o nil
+// si todavía no tiene predicciones evaluadas.
+func (r *Repository) GetLeaderboardEntry(ctx context.Context, tournamentID, userID uuid.UUID) (*LeaderboardRow, error) {
+	var rows []LeaderboardRow
+	err := r.db.WithContext(ctx).
+		Raw(leaderboardCTE+` SELECT * FROM ranked WHERE user_id = ?`, tournamentID, userID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get leaderboard entry", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
+
+// errInviteCodeTaken indica que el código de invitación generado ya existe.
+var errInviteCodeTaken = errors.New("prode: código de invitación repetido")
+
+// LeagueRow es una liga con su cantidad de miembros.
+type LeagueRow struct {
+	ProdeLeague
+	MemberCount int
+}
+
+// LeagueMemberRow es un miembro de una liga con su nombre.
+type LeagueMemberRow struct {
+	UserID   uuid.UUID
+	Name     string
+	JoinedAt time.Time
+}
+
+// CreateLeague inserta la liga y a su dueño como primer miembro.
+func (r *Repository) CreateLeague(ctx context.Context, league *ProdeLeague, now time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(league).Error; err != nil {
+			return err
+		}
+		return tx.Create(&ProdeLeagueMember{LeagueID: league.ID, UserID: league.OwnerID, JoinedAt: now}).Error
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return fmt.Errorf("prode: create league: %w", errInviteCodeTaken)
+	}
+	if err != nil {
+		return mapProdeRepoErr(ctx, "create league", err)
+	}
+	return nil
+}
+
+// GetLeagueByID obtiene una liga por su ID.
+func (r *Repository) GetLeagueByID(ctx context.Context, id uuid.UUID) (*ProdeLeague, error) {
+	var league ProdeLeague
+	if err := r.db.WithContext(ctx).First(&league, "id = ?", id).Error; err != nil {
+		return nil, mapProdeLeagueErr(ctx, "get league by id", err)
+	}
+	return &league, nil
+}
+
+// GetLeagueByInviteCode obtiene una liga por su código de invitación.
+func (r *Repository) GetLeagueByInviteCode(ctx context.Context, code string) (*ProdeLeague, error) {
+	var league ProdeLeague
+	if err := r.db.WithContext(ctx).First(&league, "invite_code = ?", code).Error; err != nil {
+		return nil, mapProdeLeagueErr(ctx, "get league by invite code", err)
+	}
+	return &league, nil
+}
+
+const leagueWithCountSQL = `
+SELECT l.*, (SELECT COUNT(*) FROM prode_league_members c WHERE c.league_id = l.id) AS member_count
+FROM prode_leagues l`
+
+// ListUserLeagues obtiene las ligas del usuario, las más nuevas primero.
+func (r *Repository) ListUserLeagues(ctx context.Context, userID uuid.UUID) ([]LeagueRow, error) {
+	var rows []LeagueRow
+	err := r.db.WithContext(ctx).
+		Raw(leagueWithCountSQL+`
+			JOIN prode_league_members m ON m.league_id = l.id
+			WHERE m.user_id = ?
+			ORDER BY l.created_at DESC`, userID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "list user leagues", err)
+	}
+	return rows, nil
+}
+
+// ListLeagues obtiene todas las ligas, las más nuevas primero.
+func (r *Repository) ListLeagues(ctx context.Context) ([]LeagueRow, error) {
+	var rows []LeagueRow
+	if err := r.db.WithContext(ctx).Raw(leagueWithCountSQL + ` ORDER BY l.created_at DESC`).Scan(&rows).Error; err != nil {
+		return nil, mapProdeRepoErr(ctx, "list leagues", err)
+	}
+	return rows, nil
+}
+
+// AddLeagueMember suma al usuario a la liga si hay lugar. Bloquea la fila de
+// la liga para que dos altas simultáneas no pasen el límite. Devuelve la
+// cantidad de miembros resultante.
+func (r *Repository) AddLeagueMember(ctx context.Context, league *ProdeLeague, userID uuid.UUID, now time.Time) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked ProdeLeague
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, "id = ?", league.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&ProdeLeagueMember{}).Where("league_id = ?", league.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if int(count) >= locked.MaxMembers {
+			return ErrLeagueFull
+		}
+		if err := tx.Create(&ProdeLeagueMember{LeagueID: league.ID, UserID: userID, JoinedAt: now}).Error; err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	switch {
+	case err == nil:
+		return int(count), nil
+	case errors.Is(err, ErrLeagueFull):
+		return 0, fmt.Errorf("prode: add league member: %w", ErrLeagueFull)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return 0, fmt.Errorf("prode: add league member: %w", ErrAlreadyInLeague)
+	default:
+		return 0, mapProdeLeagueErr(ctx, "add league member", err)
+	}
+}
+
+// GetLeagueMember devuelve la membresía del usuario o nil si no pertenece.
+func (r *Repository) GetLeagueMember(ctx context.Context, leagueID, userID uuid.UUID) (*ProdeLeagueMember, error) {
+	var member ProdeLeagueMember
+	err := r.db.WithContext(ctx).First(&member, "league_id = ? AND user_id = ?", leagueID, userID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, mapProdeRepoErr(ctx, "get league member", err)
+	}
+	return &member, nil
+}
+
+// GetLeagueMembers obtiene los miembros de la liga por orden de llegada.
+func (r *Repository) GetLeagueMembers(ctx context.Context, leagueID uuid.UUID) ([]LeagueMemberRow, error) {
+	var rows []LeagueMemberRow
+	err := r.db.WithContext(ctx).
+		Table("prode_league_members m").
+		Select("m.user_id, u.name, m.joined_at").
+		Joins("JOIN users u ON u.id = m.user_id").
+		Where("m.league_id = ?", leagueID).
+		Order("m.joined_at ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get league members", err)
+	}
+	return rows, nil
+}
+
+// RemoveLeagueMember saca al usuario de la liga. Devuelve false si no era
+// miembro.
+func (r *Repository) RemoveLeagueMember(ctx context.Context, leagueID, userID uuid.UUID) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Where("league_id = ? AND user_id = ?", leagueID, userID).
+		Delete(&ProdeLeagueMember{})
+	if res.Error != nil {
+		return false, mapProdeRepoErr(ctx, "remove league member", res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// LeaveLeague saca al usuario de la liga. Si era el dueño, la liga pasa al
+// miembro más antiguo y se devuelve su ID; si no queda nadie, la liga y sus
+// premios se borran.
+func (r *Repository) LeaveLeague(ctx context.Context, league *ProdeLeague, userID uuid.UUID) (*uuid.UUID, error) {
+	var newOwner *uuid.UUID
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("league_id = ? AND user_id = ?", league.ID, userID).Delete(&ProdeLeagueMember{}).Error; err != nil {
+			return err
+		}
+		if league.OwnerID != userID {
+			return nil
+		}
+
+		var next ProdeLeagueMember
+		err := tx.Where("league_id = ?", league.ID).Order("joined_at ASC").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := tx.Where("league_id = ?", league.ID).Delete(&ProdeLeaguePrize{}).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&ProdeLeague{}, "id = ?", league.ID).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		newOwner = &next.UserID
+		return tx.Model(&ProdeLeague{}).Where("id = ?", league.ID).Update("owner_id", next.UserID).Error
+	})
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "leave league", err)
+	}
+	return newOwner, nil
+}
+
+// GetLeagueLeaderboard devuelve la tabla completa de una liga.
+func (r *Repository) GetLeagueLeaderboard(ctx context.Context, league *ProdeLeague) ([]LeaderboardRow, error) {
+	var rows []LeaderboardRow
+	err := r.db.WithContext(ctx).
+		Raw(leagueLeaderboardCTE+` SELECT * FROM ranked ORDER BY rank`, league.TournamentID, league.ID).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "get league leaderboard", err)
+	}
+	return rows, nil
+}
+
+// GetLeaguePrizes obtiene los premios de una liga ordenados por puesto.
+func (r *Repository) GetLeaguePrizes(ctx context.Context, leagueID uuid.UUID) ([]ProdeLeaguePrize, error) {
+	var prizes []ProdeLeaguePrize
+	if err := r.db.WithContext(ctx).Where("league_id = ?", leagueID).Order("rank ASC").Find(&prizes).Error; err != nil {
+		return nil, mapProdeRepoErr(ctx, "get league prizes", err)
+	}
+	return prizes, nil
+}
+
+// SetLeaguePrizes deja como premios pendientes exactamente los puestos
+// indicados, sin tocar los que ya se entregaron.
+func (r *Repository) SetLeaguePrizes(ctx context.Context, leagueID uuid.UUID, ranks []int) ([]ProdeLeaguePrize, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		del := tx.Where("league_id = ? AND status <> ?", leagueID, RewardStatusFulfilled)
+		if len(ranks) > 0 {
+			del = del.Where("rank NOT IN ?", ranks)
+		}
+		if err := del.Delete(&ProdeLeaguePrize{}).Error; err != nil {
+			return err
+		}
+
+		for _, rank := range ranks {
+			prize := ProdeLeaguePrize{LeagueID: leagueID, Rank: rank, Status: RewardStatusPending}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&prize).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, mapProdeRepoErr(ctx, "set league prizes", err)
+	}
+	return r.GetLeaguePrizes(ctx, leagueID)
+}
+
+// LockLeaguePrize relee un premio de liga bloqueando la fila hasta el fin de
+// la transacción. Devuelve nil si el premio ya no existe.
+func (r *Repository) LockLeaguePrize(ctx context.Context, id uuid.UUID) (*ProdeLeaguePrize, error) {
+	var prize ProdeLeaguePrize
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&prize, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, mapProdeRepoErr(ctx, "lock league prize", err)
+	}
+	return &prize, nil
+}
+
+// UpdateLeaguePrize guarda el estado de un premio de liga.
+func (r *Repository) UpdateLeaguePrize(ctx context.Context, prize *ProdeLeaguePrize) error {
+	if err := r.db.WithContext(ctx).Save(prize).Error; err != nil {
+		return mapProdeRepoErr(ctx, "update league prize", err)
+	}
+	return nil
+}
+
+// CreateTournament inserta un torneo nuevo.
+func (r *Repository) CreateTournament(ctx context.Context, t *ProdeTournament) error {
+	// Select("*") para que un corte de 0 minutos no se reemplace por el
+	// default de la columna.
+	if err := r.db.WithContext(ctx).Select("*").Create(t).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("prode: create tournament: %w", ErrTournamentExists)
+		}
+		return mapProdeRepoErr(ctx, "create tournament", err)
+	}
+	return nil
+}
+
+// UpdateTournament guarda los cambios de un torneo.
+func (r *Repository) UpdateTournament(ctx context.Context, t *ProdeTournament) error {
+	if err := r.db.WithContext(ctx).Save(t).Error; err != nil {
+		return mapProdeRepoErr(ctx, "update tournament", err)
+	}
+	return nil
+}
+
+// GetTournamentByID obtiene un torneo por su ID.
+func (r *Repository) GetTournamentByID(ctx context.Context, id uuid.UUID) (*ProdeTournament, error) {
+	var t ProdeTournament
+	if err := r.db.WithContext(ctx).First(&t, "id = ?", id).Error; err != nil {
+		return nil, mapProdeTournamentErr(ctx, "get tournament by id", err)
+	}
+	return &t, nil
+}
+
+// GetTournamentBySlug obtiene un torneo por su slug.
+func (r *Repository) GetTournamentBySlug(ctx context.Context, slug string) (*ProdeTournament, error) {
+	var t ProdeTournament
+	if err := r.db.WithContext(ctx).First(&t, "slug = ?", slug).Error; err != nil {
+		return nil, mapProdeTournamentErr(ctx, "get tournament by slug", err)
+	}
+	return &t, nil
+}
+
+// ListTournaments obtiene los torneos, solo los activos si activeOnly.
+func (r *Repository) ListTournaments(ctx context.Context, activeOnly bool) ([]ProdeTournament, error) {
+	q := r.db.WithContext(ctx).Order("created_at DESC")
+	if activeOnly {
+		q = q.Where("is_active = ?", true)
+	}
+
+	var tournaments []ProdeTournament
+	if err := q.Find(&tournaments).Error; err != nil {
+		return nil, mapProdeRepoErr(ctx, "list tournaments", err)
+	}
+	return tournaments, nil
+}
+
+// CreateTeam inserta un equipo nuevo.
+func (r *Repository) CreateTeam(ctx context.Context, team *ProdeTeam) error {
+	if err := r.db.WithContext(ctx).Create(team).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("prode: create team: %w", ErrTeamExists)
+		}
+		return mapProdeRepoErr(ctx, "create team", err)
+	}
+	return nil
+}
+
+// GetTeamByID obtiene un equipo por su ID.
+func (r *Repository) GetTeamByID(ctx context.Context, id uuid.UUID) (*ProdeTeam, error) {
+	var team ProdeTeam
+	err := r.db.WithContext(ctx).First(&team, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("prode: get team by id: %w", ErrTeamNotFound)
+		}
+		return nil, mapProdeRepoErr(ctx, "get team by id", err)
+	}
+	return &team, nil
+}
+
+// FindTeam busca un equipo por su id en el feed o, si no lo tiene, por
+// nombre. Devuelve nil si no existe.
+func (r *Repository) FindTeam(ctx context.Context, externalID, name string) (*ProdeTeam, error) {
+	var team ProdeTeam
+	err := r.db.WithContext(ctx).
+		Where("(external_id <> '' AND external_id = ?) OR name = ?", externalID, name).
+		Order("external_id DESC").
+		First(&team).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, mapProdeRepoErr(ctx, "find team", err)
+	}
+	return &team, nil
+}
+
+// ListTeams obtiene todos los equipos ordenados por nombre.
+func (r *Repository) ListTeams(ctx context.Context) ([]ProdeTeam, error) {
+	var teams []ProdeTeam
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&teams).Error; err != nil {
+		return nil, mapProdeRepoErr(ctx, "list teams", err)
+	}
+	return teams, nil
+}
+
 func mapProdeMatchErr(ctx context.Context, action string, err error) error {
 	if err == nil {
 		return nil
@@ -224,6 +1348,28 @@ func mapProdeMatchErr(ctx context.Context, action string, err error) error {
 	return fmt.Errorf("prode: %s: %w", action, ErrInternal)
 }
 
+func mapProdeTournamentErr(ctx context.Context, action string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("prode: %s: %w", action, ErrTournamentNotFound)
+	}
+	slog.ErrorContext(ctx, "prode repository", "action", action, "error", err)
+	return fmt.Errorf("prode: %s: %w", action, ErrInternal)
+}
+
+func mapProdeLeagueErr(ctx context.Context, action string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("prode: %s: %w", action, ErrLeagueNotFound)
+	}
+	slog.ErrorContext(ctx, "prode repository", "action", action, "error", err)
+	return fmt.Errorf("prode: %s: %w", action, ErrInternal)
+}
+
 func mapProdePredictionErr(ctx context.Context, action string, err error) error {
 	if err == nil {
 		return nil
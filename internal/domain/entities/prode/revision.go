@@ -0,0 +1,96 @@
+package prode
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RevisionBackfill es el RequestID de las revisiones creadas al migrar las
+// predicciones que existían antes del historial.
+const RevisionBackfill = "backfill"
+
+var errRevisionImmutable = errors.New("prode: las revisiones de predicción no se modifican")
+
+// ProdePredictionRevision es una versión de una predicción tal como la guardó
+// el usuario. Cada cambio agrega una fila y ninguna se edita ni se borra; el
+// settlement usa la última anterior al corte.
+type ProdePredictionRevision struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	PredictionID uuid.UUID `gorm:"type:uuid;not null;index:idx_prode_revision_pred_created" json:"prediction_id"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index:idx_prode_revision_match_user" json:"user_id"`
+	MatchID      uuid.UUID `gorm:"type:uuid;not null;index:idx_prode_revision_match_user" json:"match_id"`
+	HomeGoals    int       `gorm:"type:smallint;not null" json:"home_goals"`
+	AwayGoals    int       `gorm:"type:smallint;not null" json:"away_goals"`
+	RequestID    string    `gorm:"type:varchar(64)" json:"request_id,omitempty"`
+	CreatedAt    time.Time `gorm:"type:timestamptz;not null;index:idx_prode_revision_pred_created" json:"created_at"`
+}
+
+func (ProdePredictionRevision) TableName() string { return "prode_prediction_revisions" }
+
+func (r *ProdePredictionRevision) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *ProdePredictionRevision) BeforeUpdate(tx *gorm.DB) error {
+	return errRevisionImmutable
+}
+
+func (r *ProdePredictionRevision) BeforeDelete(tx *gorm.DB) error {
+	return errRevisionImmutable
+}
+
+// lockedRevision devuelve la última revisión guardada antes del corte, o nil
+// si todas son posteriores. revs tiene que venir ordenado por fecha.
+func lockedRevision(revs []ProdePredictionRevision, cutoff time.Time) *ProdePredictionRevision {
+	var locked *ProdePredictionRevision
+	for i := range revs {
+		if !revs[i].CreatedAt.Before(cutoff) {
+			break
+		}
+		locked = &revs[i]
+	}
+	return locked
+}
+
+// GetPredictionHistory devuelve todas las versiones de la predicción del
+// usuario para un partido y marca la que vale para el settlement.
+func (s *Service) GetPredictionHistory(ctx context.Context, userID, matchID uuid.UUID) (*PredictionHistoryResponse, error) {
+	match, err := s.repo.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	revs, err := s.repo.GetPredictionRevisions(ctx, userID, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff, err := match.CutoffAt()
+	if err != nil {
+		return nil, err
+	}
+	locked := lockedRevision(revs, cutoff)
+
+	resp := &PredictionHistoryResponse{
+		MatchID:   matchID.String(),
+		CutoffAt:  cutoff,
+		Revisions: make([]PredictionRevisionResponse, 0, len(revs)),
+	}
+	for i := range revs {
+		resp.Revisions = append(resp.Revisions, PredictionRevisionResponse{
+			HomeGoals: revs[i].HomeGoals,
+			AwayGoals: revs[i].AwayGoals,
+			RequestID: revs[i].RequestID,
+			CreatedAt: revs[i].CreatedAt,
+			Locked:    locked == &revs[i],
+		})
+	}
+	return resp, nil
+}
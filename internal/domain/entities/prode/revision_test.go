@@ -0,0 +1,50 @@
+package prode
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockedRevision(t *testing.T) {
+	t.Parallel()
+
+	cutoff := time.Date(2026, 6, 14, 18, 0, 0, 0, time.UTC)
+	revs := []ProdePredictionRevision{
+		{HomeGoals: 1, AwayGoals: 0, CreatedAt: cutoff.Add(-2 * time.Hour)},
+		{HomeGoals: 2, AwayGoals: 1, CreatedAt: cutoff.Add(-time.Second)},
+		{HomeGoals: 3, AwayGoals: 3, CreatedAt: cutoff},
+		{HomeGoals: 4, AwayGoals: 0, CreatedAt: cutoff.Add(time.Minute)},
+	}
+
+	tests := []struct {
+		name      string
+		revs      []ProdePredictionRevision
+		wantNil   bool
+		wantScore [2]int
+	}{
+		{"última antes del corte", revs, false, [2]int{2, 1}},
+		{"la del corte exacto no cuenta", revs[2:], true, [2]int{}},
+		{"solo anteriores", revs[:1], false, [2]int{1, 0}},
+		{"sin revisiones", nil, true, [2]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := lockedRevision(tt.revs, cutoff)
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("lockedRevision() = %d-%d, want nil", got.HomeGoals, got.AwayGoals)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("lockedRevision() = nil")
+			}
+			if score := [2]int{got.HomeGoals, got.AwayGoals}; score != tt.wantScore {
+				t.Errorf("lockedRevision() = %v, want %v", score, tt.wantScore)
+			}
+		})
+	}
+}
@@ -14,6 +14,9 @@ const (
 	RewardStatusPendingInventory = "PENDING_INVENTORY"
 	RewardStatusFailed           = "FAILED"
 	RewardStatusSkipped          = "SKIPPED"
+	// RewardStatusRevoked es un premio anulado porque se corrigió el
+	// resultado del partido y la predicción dejó de ser exacta.
+	RewardStatusRevoked = "REVOKED"
 )
 
 type ProdeReward struct {
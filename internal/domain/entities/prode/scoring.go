@@ -0,0 +1,42 @@
+package prode
+
+// Scoring define cuántos puntos vale cada tipo de acierto. El resultado
+// exacto no acumula con los demás; el bono por diferencia de gol solo suma
+// cuando además se acertó el ganador (o el empate).
+type Scoring struct {
+	Exact    int
+	Outcome  int
+	GoalDiff int
+}
+
+// DefaultScoring es el esquema clásico de prode: 3 por resultado exacto, 1
+// por acertar ganador o empate y 1 extra si coincide la diferencia de gol.
+func DefaultScoring() Scoring {
+	return Scoring{Exact: 3, Outcome: 1, GoalDiff: 1}
+}
+
+// Points calcula los puntos de una predicción contra el resultado final.
+func (s Scoring) Points(predHome, predAway, resHome, resAway int) int {
+	if predHome == resHome && predAway == resAway {
+		return s.Exact
+	}
+	if sign(predHome-predAway) != sign(resHome-resAway) {
+		return 0
+	}
+
+	points := s.Outcome
+	if predHome-predAway == resHome-resAway {
+		points += s.GoalDiff
+	}
+	return points
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,32 @@
+package prode
+
+import "testing"
+
+func TestScoringPoints(t *testing.T) {
+	t.Parallel()
+
+	s := DefaultScoring()
+
+	tests := []struct {
+		name                                 string
+		predHome, predAway, resHome, resAway int
+		want                                 int
+	}{
+		{"resultado exacto", 2, 1, 2, 1, 3},
+		{"ganador y diferencia", 3, 2, 2, 1, 2},
+		{"solo ganador", 3, 0, 2, 1, 1},
+		{"empate con otro marcador", 0, 0, 1, 1, 2},
+		{"ganador equivocado", 0, 1, 2, 1, 0},
+		{"empate predicho sin empate", 1, 1, 2, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.Points(tt.predHome, tt.predAway, tt.resHome, tt.resAway)
+			if got != tt.want {
+				t.Fatalf("Points(%d-%d vs %d-%d) = %d, want %d",
+					tt.predHome, tt.predAway, tt.resHome, tt.resAway, got, tt.want)
+			}
+		})
+	}
+}
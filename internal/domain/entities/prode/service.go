@@ -12,6 +12,7 @@ import (
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/user"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/voucher"
 	"github.com/sebaactis/powermix-back-mobile/internal/middlewares"
+	"github.com/sebaactis/powermix-back-mobile/internal/platform/logger"
 )
 
 // Clock permite inyectar la hora actual para tests (sin tests por ahora, pero
@@ -30,17 +31,21 @@ type Service struct {
 	userRepo    *user.Repository
 	mailer      mailer.Mailer
 	adminEmails []string
+	scoring     Scoring
 	clock       Clock
+	chunkSize   int
 }
 
-func NewService(repo *Repository, voucherRepo *voucher.Repository, userRepo *user.Repository, mailer mailer.Mailer, adminEmails []string) *Service {
+func NewService(repo *Repository, voucherRepo *voucher.Repository, userRepo *user.Repository, mailer mailer.Mailer, adminEmails []string, scoring Scoring) *Service {
 	return &Service{
 		repo:        repo,
 		voucherRepo: voucherRepo,
 		userRepo:    userRepo,
 		mailer:      mailer,
 		adminEmails: adminEmails,
+		scoring:     scoring,
 		clock:       realClock{},
+		chunkSize:   settlementChunkSize,
 	}
 }
 
@@ -52,15 +57,23 @@ func (s *Service) WithTx(txRepo *Repository) *Service {
 		userRepo:    s.userRepo,
 		mailer:      s.mailer,
 		adminEmails: s.adminEmails,
+		scoring:     s.scoring,
 		clock:       s.clock,
+		chunkSize:   s.chunkSize,
 	}
 }
 
-// ListMatches devuelve los partidos visibles con la predicción del usuario si existe.
-func (s *Service) ListMatches(ctx context.Context) ([]MatchResponse, error) {
+// ListMatches devuelve los partidos visibles de un torneo con la predicción
+// del usuario si existe.
+func (s *Service) ListMatches(ctx context.Context, tournamentSlug string) ([]MatchResponse, error) {
 	userID, _ := middlewares.UserIDFromContext(ctx)
 
-	matches, err := s.repo.GetVisibleMatches(ctx)
+	tournament, err := s.repo.GetTournamentBySlug(ctx, tournamentSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.repo.GetVisibleMatches(ctx, tournament.ID)
 	if err != nil {
 		slog.ErrorContext(ctx, "error al obtener partidos visibles", "error", err)
 		return nil, err
@@ -68,7 +81,10 @@ func (s *Service) ListMatches(ctx context.Context) ([]MatchResponse, error) {
 
 	responses := make([]MatchResponse, 0, len(matches))
 	for _, m := range matches {
-		resp := matchToResponse(m)
+		resp, err := matchToResponse(m)
+		if err != nil {
+			return nil, err
+		}
 
 		if userID != uuid.Nil {
 			pred, err := s.repo.GetUserPrediction(ctx, userID, m.ID)
@@ -94,7 +110,10 @@ func (s *Service) GetMatch(ctx context.Context, matchID uuid.UUID) (*MatchRespon
 		return nil, err
 	}
 
-	resp := matchToResponse(*match)
+	resp, err := matchToResponse(*match)
+	if err != nil {
+		return nil, err
+	}
 
 	if userID != uuid.Nil {
 		pred, err := s.repo.GetUserPrediction(ctx, userID, matchID)
@@ -116,27 +135,34 @@ func (s *Service) CreateOrUpdatePrediction(ctx context.Context, userID uuid.UUID
 		return nil, err
 	}
 
-	if !match.IsOpenForPrediction(s.clock.Now()) {
+	// La misma hora decide si está abierto y queda en la revisión, así
+	// ninguna revisión aceptada puede quedar después del corte.
+	now := s.clock.Now()
+	open, err := match.IsOpenForPrediction(now)
+	if err != nil {
+		return nil, err
+	}
+	if !open {
 		slog.WarnContext(ctx, "intento de predicción fuera de plazo", "user_id", userID,
 			"match_id", matchID,
 			"kickoff", match.KickoffAt,
-			"now", s.clock.Now(),)
+			"now", now,)
 		return nil, ErrCutoffPassed
 	}
 
 	pred := ProdePrediction{
-		UserID:         userID,
-		MatchID:        matchID,
-		ArgentinaGoals: req.ArgentinaGoals,
-		OpponentGoals:  req.OpponentGoals,
-		Status:         PredStatusPending,
+		UserID:    userID,
+		MatchID:   matchID,
+		HomeGoals: req.HomeGoals,
+		AwayGoals: req.AwayGoals,
+		Status:    PredStatusPending,
 	}
 
 	if err := pred.ValidateScore(); err != nil {
 		return nil, err
 	}
 
-	created, err := s.repo.UpsertPrediction(ctx, &pred)
+	created, err := s.repo.UpsertPrediction(ctx, &pred, now, logger.RequestIDFromContext(ctx))
 	if err != nil {
 		slog.ErrorContext(ctx, "error al guardar predicción", "user_id", userID,
 			"match_id", matchID,
@@ -146,17 +172,23 @@ func (s *Service) CreateOrUpdatePrediction(ctx context.Context, userID uuid.UUID
 
 	slog.InfoContext(ctx, "predicción guardada", "user_id", userID,
 		"match_id", matchID,
-		"argentina_goals", req.ArgentinaGoals,
-		"opponent_goals", req.OpponentGoals,)
+		"home_goals", req.HomeGoals,
+		"away_goals", req.AwayGoals,)
 
 	return predictionToResponse(created), nil
 }
 
-// GetMyPredictions devuelve todas las predicciones del usuario autenticado.
-func (s *Service) GetMyPredictions(ctx context.Context) ([]PredictionResponse, error) {
+// GetMyPredictions devuelve las predicciones del usuario autenticado en un
+// torneo.
+func (s *Service) GetMyPredictions(ctx context.Context, tournamentSlug string) ([]PredictionResponse, error) {
 	userID, _ := middlewares.UserIDFromContext(ctx)
 
-	predictions, err := s.repo.GetPredictionsByUserID(ctx, userID)
+	tournament, err := s.repo.GetTournamentBySlug(ctx, tournamentSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	predictions, err := s.repo.GetPredictionsByUserID(ctx, userID, tournament.ID)
 	if err != nil {
 		slog.ErrorContext(ctx, "error al obtener predicciones del usuario", "user_id", userID, "error", err)
 		return nil, err
@@ -181,26 +213,58 @@ func (s *Service) CreateMatch(ctx context.Context, req CreateMatchRequest) (*Adm
 		status = MatchStatusScheduled
 	}
 
+	tournament, err := s.repo.GetTournamentByID(ctx, req.TournamentID)
+	if err != nil {
+		return nil, err
+	}
+
 	match := ProdeMatch{
-		Stage:     req.Stage,
-		Opponent:  req.Opponent,
-		KickoffAt: req.KickoffAt,
-		IsVisible: req.IsVisible,
-		Status:    status,
+		TournamentID: tournament.ID,
+		Tournament:   tournament,
+		Stage:        req.Stage,
+		KickoffAt:    req.KickoffAt,
+		IsVisible:    req.IsVisible,
+		Status:       status,
+	}
+	if err := s.setTeams(ctx, &match, &req.HomeTeamID, &req.AwayTeamID); err != nil {
+		return nil, err
 	}
 
 	if err := s.repo.CreateMatch(ctx, &match); err != nil {
 		slog.ErrorContext(ctx, "error al crear partido", "stage", req.Stage,
-			"opponent", req.Opponent,
+			"tournament_id", req.TournamentID,
 			"error", err,)
 		return nil, err
 	}
 
 	slog.InfoContext(ctx, "partido creado", "match_id", match.ID,
 		"stage", match.Stage,
-		"opponent", match.Opponent,)
+		"match", match.Label(),)
 
-	return adminMatchToResponse(&match), nil
+	return adminMatchToResponse(&match)
+}
+
+// setTeams cambia los equipos indicados (nil deja el actual) y verifica que
+// existan y no sean el mismo.
+func (s *Service) setTeams(ctx context.Context, match *ProdeMatch, homeID, awayID *uuid.UUID) error {
+	if homeID != nil {
+		home, err := s.repo.GetTeamByID(ctx, *homeID)
+		if err != nil {
+			return err
+		}
+		match.HomeTeamID, match.HomeTeam = home.ID, home
+	}
+	if awayID != nil {
+		away, err := s.repo.GetTeamByID(ctx, *awayID)
+		if err != nil {
+			return err
+		}
+		match.AwayTeamID, match.AwayTeam = away.ID, away
+	}
+	if match.HomeTeamID == match.AwayTeamID {
+		return ErrSameTeams
+	}
+	return nil
 }
 
 // UpdateMatch actualiza los campos de un partido existente.
@@ -213,8 +277,8 @@ func (s *Service) UpdateMatch(ctx context.Context, matchID uuid.UUID, req Update
 	if req.Stage != nil {
 		match.Stage = *req.Stage
 	}
-	if req.Opponent != nil {
-		match.Opponent = *req.Opponent
+	if err := s.setTeams(ctx, match, req.HomeTeamID, req.AwayTeamID); err != nil {
+		return nil, err
 	}
 	if req.KickoffAt != nil {
 		match.KickoffAt = *req.KickoffAt
@@ -222,11 +286,15 @@ func (s *Service) UpdateMatch(ctx context.Context, matchID uuid.UUID, req Update
 	if req.IsVisible != nil {
 		match.IsVisible = *req.IsVisible
 	}
+	from := match.Status
 	if req.Status != nil {
+		if !CanTransition(match.Status, *req.Status) {
+			return nil, &TransitionError{MatchID: matchID, From: match.Status, To: *req.Status}
+		}
 		match.Status = *req.Status
 	}
 
-	if err := s.repo.UpdateMatch(ctx, match); err != nil {
+	if err := s.repo.SaveMatchTransition(ctx, match, from, ActorAdmin, ""); err != nil {
 		slog.ErrorContext(ctx, "error al actualizar partido", "match_id", matchID,
 			"error", err,)
 		return nil, err
@@ -236,7 +304,7 @@ func (s *Service) UpdateMatch(ctx context.Context, matchID uuid.UUID, req Update
 		"status", match.Status,
 		"is_visible", match.IsVisible,)
 
-	return adminMatchToResponse(match), nil
+	return adminMatchToResponse(match)
 }
 
 // RecordResult registra el resultado de 90 minutos de un partido.
@@ -246,138 +314,92 @@ func (s *Service) RecordResult(ctx context.Context, matchID uuid.UUID, req Recor
 		return nil, err
 	}
 
-	if req.ArgentinaGoals < 0 || req.OpponentGoals < 0 {
+	if req.HomeGoals < 0 || req.AwayGoals < 0 {
 		return nil, ErrInvalidScore
 	}
 
-	argentinaGoals := req.ArgentinaGoals
-	opponentGoals := req.OpponentGoals
-	match.ArgentinaGoals = &argentinaGoals
-	match.OpponentGoals = &opponentGoals
-	match.Status = MatchStatusResultRecorded
-
-	if err := s.repo.UpdateMatch(ctx, match); err != nil {
-		slog.ErrorContext(ctx, "error al guardar resultado", "match_id", matchID,
-			"argentina_goals", req.ArgentinaGoals,
-			"opponent_goals", req.OpponentGoals,
-			"error", err,)
+	if err := s.recordResult(ctx, match, req.HomeGoals, req.AwayGoals, ActorAdmin, ResultSourceManual); err != nil {
 		return nil, err
 	}
 
-	slog.InfoContext(ctx, "resultado registrado", "match_id", matchID,
-		"argentina_goals", req.ArgentinaGoals,
-		"opponent_goals", req.OpponentGoals,)
-
-	return adminMatchToResponse(match), nil
+	return adminMatchToResponse(match)
 }
 
-// SettleMatch evalúa todas las predicciones de un partido y asigna premios.
-func (s *Service) SettleMatch(ctx context.Context, matchID uuid.UUID) (*SettlementResponse, error) {
-	match, err := s.repo.GetMatchByID(ctx, matchID)
+// recordResult guarda el resultado de 90 minutos y pasa el partido a
+// RESULT_RECORDED. Los resultados manuales quedan confirmados en el acto.
+func (s *Service) recordResult(ctx context.Context, match *ProdeMatch, homeGoals, awayGoals int, actor, source string) error {
+	// Si el scheduler todavía no lo cerró pero el corte ya pasó, el partido
+	// se considera cerrado.
+	from := match.Status
+	open, err := match.IsOpenForPrediction(s.clock.Now())
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	if match.Status != MatchStatusResultRecorded {
-		return nil, ErrResultMissing
+	if (from == MatchStatusScheduled || from == MatchStatusOpen) && !open {
+		from = MatchStatusClosed
 	}
-
-	if match.ArgentinaGoals == nil || match.OpponentGoals == nil {
-		return nil, ErrResultMissing
+	if !CanTransition(from, MatchStatusResultRecorded) {
+		return &TransitionError{MatchID: match.ID, From: match.Status, To: MatchStatusResultRecorded}
 	}
+	from = match.Status
 
-	predictions, err := s.repo.GetPredictionsByMatchID(ctx, matchID)
-	if err != nil {
-		slog.ErrorContext(ctx, "error al obtener predicciones para settlement", "match_id", matchID, "error", err)
-		return nil, err
+	match.HomeGoals = &homeGoals
+	match.AwayGoals = &awayGoals
+	match.Status = MatchStatusResultRecorded
+	match.ResultSource = source
+	match.ResultConfirmedAt = nil
+	if source == ResultSourceManual {
+		now := s.clock.Now()
+		match.ResultConfirmedAt = &now
+	}
+
+	reason := fmt.Sprintf("resultado %d-%d", homeGoals, awayGoals)
+	if err := s.repo.SaveMatchResult(ctx, match, from, actor, reason); err != nil {
+		slog.ErrorContext(ctx, "error al guardar resultado", "match_id", match.ID,
+			"home_goals", homeGoals,
+			"away_goals", awayGoals,
+			"error", err,)
+		return err
 	}
 
-	correctCount := 0
-	incorrectCount := 0
-	pendingInventory := 0
-	totalPreds := len(predictions)
-	needsAdminNotify := false
-
-	for _, pred := range predictions {
-		existingReward, err := s.repo.GetRewardByPredictionID(ctx, pred.ID)
-		if err != nil {
-			slog.ErrorContext(ctx, "error al verificar premio existente", "prediction_id", pred.ID, "error", err)
-			continue
-		}
+	slog.InfoContext(ctx, "resultado registrado", "match_id", match.ID,
+		"home_goals", homeGoals,
+		"away_goals", awayGoals,
+		"source", source,)
 
-		if existingReward != nil {
-			if existingReward.Status == RewardStatusFulfilled {
-				correctCount++
-				continue
-			}
-			if existingReward.Status == RewardStatusPendingInventory {
-				if s.tryAssignVoucher(ctx, existingReward) {
-					correctCount++
-				} else {
-					pendingInventory++
-					needsAdminNotify = true
-				}
-				continue
-			}
-			continue
-		}
-
-		exactMatch := pred.ArgentinaGoals == *match.ArgentinaGoals &&
-			pred.OpponentGoals == *match.OpponentGoals
-
-		if exactMatch {
-			reward := &ProdeReward{
-				PredictionID: pred.ID,
-				UserID:       pred.UserID,
-				Status:       RewardStatusPending,
-			}
-
-			if err := s.repo.CreateReward(ctx, reward); err != nil {
-				slog.ErrorContext(ctx, "error al crear premio", "prediction_id", pred.ID, "error", err)
-				continue
-			}
-
-			if s.tryAssignVoucher(ctx, reward) {
-				correctCount++
-			} else {
-				pendingInventory++
-				needsAdminNotify = true
-			}
+	return nil
+}
 
-			pred.Status = PredStatusCorrect
-			if err := s.repo.UpdatePrediction(ctx, &pred); err != nil {
-				slog.ErrorContext(ctx, "error al actualizar predicción", "prediction_id", pred.ID, "error", err)
-			}
-		} else {
-			incorrectCount++
-			pred.Status = PredStatusIncorrect
-			if err := s.repo.UpdatePrediction(ctx, &pred); err != nil {
-				slog.ErrorContext(ctx, "error al actualizar predicción", "prediction_id", pred.ID, "error", err)
-			}
-		}
+// ConfirmResult confirma un resultado que llegó del feed para que pueda
+// evaluarse. Confirmar dos veces no hace nada.
+func (s *Service) ConfirmResult(ctx context.Context, matchID uuid.UUID) (*AdminMatchResponse, error) {
+	match, err := s.repo.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return nil, err
 	}
 
-	match.Status = MatchStatusEvaluated
-	if err := s.repo.UpdateMatch(ctx, match); err != nil {
-		slog.ErrorContext(ctx, "error al actualizar estado del partido", "match_id", matchID, "error", err)
+	if match.Status != MatchStatusResultRecorded {
+		return nil, ErrResultMissing
+	}
+	if !match.NeedsConfirmation() {
+		return adminMatchToResponse(match)
 	}
 
-	if needsAdminNotify {
-		s.notifyAdmins(ctx, match, pendingInventory)
+	now := s.clock.Now()
+	match.ResultConfirmedAt = &now
+	if err := s.repo.SaveMatchTransition(ctx, match, match.Status, ActorAdmin, "resultado del feed confirmado"); err != nil {
+		return nil, err
 	}
 
-	slog.InfoContext(ctx, "settlement completado", "match_id", matchID,
-		"total", totalPreds,
-		"correct", correctCount,
-		"incorrect", incorrectCount,
-		"pending_inventory", pendingInventory,)
+	slog.InfoContext(ctx, "resultado del feed confirmado", "match_id", matchID, "extra_time", match.ExtraTime)
+	return adminMatchToResponse(match)
+}
 
-	return &SettlementResponse{
-		MatchID:    matchID.String(),
-		Status:     MatchStatusEvaluated,
-		TotalPreds: totalPreds,
-		Correct:    correctCount,
-	}, nil
+// SettleMatch evalúa todas las predicciones de un partido: guarda los puntos
+// de cada una según el esquema de puntaje y asigna premios a los resultados
+// exactos. Se puede volver a correr: solo toma las predicciones sin evaluar.
+func (s *Service) SettleMatch(ctx context.Context, matchID uuid.UUID) (*SettlementResponse, error) {
+	return s.settleMatch(ctx, matchID, ActorAdmin)
 }
 
 // RetryPendingRewards reintenta asignar vouchers a premios pendientes por inventario.
@@ -492,7 +514,7 @@ func (s *Service) sendVoucherEmail(ctx context.Context, userID uuid.UUID, vouche
 // notifyAdmins envía notificación a los administradores sobre premios pendientes.
 func (s *Service) notifyAdmins(ctx context.Context, match *ProdeMatch, pendingCount int) {
 	for _, email := range s.adminEmails {
-		if err := s.mailer.SendProdeAdminNotification(ctx, email, match.Opponent, match.Stage, pendingCount); err != nil {
+		if err := s.mailer.SendProdeAdminNotification(ctx, email, match.Label(), match.Stage, pendingCount); err != nil {
 			slog.ErrorContext(ctx, "error al notificar a admin", "email", email, "error", err)
 		}
 	}
@@ -513,47 +535,74 @@ func (s *Service) notifyAdmins(ctx context.Context, match *ProdeMatch, pendingCo
 
 // helpers de conversión
 
-func matchToResponse(m ProdeMatch) MatchResponse {
+func matchToResponse(m ProdeMatch) (MatchResponse, error) {
 	now := time.Now()
+	cutoff, err := m.CutoffAt()
+	if err != nil {
+		return MatchResponse{}, err
+	}
+	isOpen, err := m.IsOpenForPrediction(now)
+	if err != nil {
+		return MatchResponse{}, err
+	}
 	resp := MatchResponse{
-		ID:            m.ID.String(),
-		Stage:         m.Stage,
-		Opponent:      m.Opponent,
-		KickoffAt:     m.KickoffAt,
-		CutoffAt:      m.CutoffAt(),
-		Status:        m.Status,
-		IsOpen:        m.IsOpenForPrediction(now),
-		ArgentinaGoals: m.ArgentinaGoals,
-		OpponentGoals:  m.OpponentGoals,
-	}
-	return resp
+		ID:           m.ID.String(),
+		TournamentID: m.TournamentID.String(),
+		Stage:        m.Stage,
+		HomeTeam:     teamToResponse(m.HomeTeam),
+		AwayTeam:     teamToResponse(m.AwayTeam),
+		KickoffAt:    m.KickoffAt,
+		CutoffAt:     cutoff,
+		Status:       m.Status,
+		IsOpen:       isOpen,
+		HomeGoals:    m.HomeGoals,
+		AwayGoals:    m.AwayGoals,
+	}
+	return resp, nil
 }
 
-func adminMatchToResponse(m *ProdeMatch) *AdminMatchResponse {
+func adminMatchToResponse(m *ProdeMatch) (*AdminMatchResponse, error) {
+	cutoff, err := m.CutoffAt()
+	if err != nil {
+		return nil, err
+	}
 	return &AdminMatchResponse{
-		ID:             m.ID.String(),
-		Stage:          m.Stage,
-		Opponent:       m.Opponent,
-		KickoffAt:      m.KickoffAt,
-		CutoffAt:       m.CutoffAt(),
-		Status:         m.Status,
-		IsVisible:      m.IsVisible,
-		ArgentinaGoals: m.ArgentinaGoals,
-		OpponentGoals:  m.OpponentGoals,
-		ExternalID:     m.ExternalID,
-		CreatedAt:      m.CreatedAt,
-		UpdatedAt:      m.UpdatedAt,
+		ID:                m.ID.String(),
+		TournamentID:      m.TournamentID.String(),
+		Stage:             m.Stage,
+		HomeTeam:          teamToResponse(m.HomeTeam),
+		AwayTeam:          teamToResponse(m.AwayTeam),
+		KickoffAt:         m.KickoffAt,
+		CutoffAt:          cutoff,
+		Status:            m.Status,
+		IsVisible:         m.IsVisible,
+		HomeGoals:         m.HomeGoals,
+		AwayGoals:         m.AwayGoals,
+		ExternalID:        m.ExternalID,
+		ResultSource:      m.ResultSource,
+		ResultConfirmedAt: m.ResultConfirmedAt,
+		ExtraTime:         m.ExtraTime,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}, nil
+}
+
+func teamToResponse(t *ProdeTeam) TeamResponse {
+	if t == nil {
+		return TeamResponse{}
 	}
+	return TeamResponse{ID: t.ID.String(), Name: t.Name, ShortName: t.ShortName}
 }
 
 func predictionToResponse(p *ProdePrediction) *PredictionResponse {
 	return &PredictionResponse{
-		ID:             p.ID.String(),
-		MatchID:        p.MatchID.String(),
-		ArgentinaGoals: p.ArgentinaGoals,
-		OpponentGoals:  p.OpponentGoals,
-		Status:         p.Status,
-		CreatedAt:      p.CreatedAt,
-		UpdatedAt:      p.UpdatedAt,
+		ID:        p.ID.String(),
+		MatchID:   p.MatchID.String(),
+		HomeGoals: p.HomeGoals,
+		AwayGoals: p.AwayGoals,
+		Status:    p.Status,
+		Points:    p.Points,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
 	}
 }
@@ -0,0 +1,380 @@
+package prode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/voucher"
+	"gorm.io/gorm"
+)
+
+// Estados de una corrida de settlement.
+const (
+	SettlementRunning   = "RUNNING"
+	SettlementCompleted = "COMPLETED"
+	SettlementFailed    = "FAILED"
+)
+
+const (
+	// settlementChunkSize es cuántas predicciones se evalúan por transacción.
+	settlementChunkSize = 200
+	// settlementStaleAfter es cuánto puede estar una corrida en RUNNING antes
+	// de darla por abandonada (por ejemplo, si se reinició el proceso).
+	settlementStaleAfter = 15 * time.Minute
+)
+
+// ProdeSettlementRun registra una corrida de settlement de un partido. Cada
+// tanda de predicciones se guarda en su propia transacción y LastPredictionID
+// marca hasta dónde llegó; una corrida que falla deja el partido en
+// RESULT_RECORDED y la siguiente retoma con las predicciones sin evaluar.
+// Reverted cuenta las predicciones que cambiaron después del corte: se evalúan
+// con la última revisión previa o, si no hay ninguna, quedan sin puntos.
+type ProdeSettlementRun struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	MatchID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"match_id"`
+	Actor            string     `gorm:"type:varchar(20);not null" json:"actor"`
+	Status           string     `gorm:"type:varchar(20);not null" json:"status"`
+	Total            int        `gorm:"not null;default:0" json:"total"`
+	Processed        int        `gorm:"not null;default:0" json:"processed"`
+	Correct          int        `gorm:"not null;default:0" json:"correct"`
+	Incorrect        int        `gorm:"not null;default:0" json:"incorrect"`
+	PendingInventory int        `gorm:"not null;default:0" json:"pending_inventory"`
+	Points           int        `gorm:"not null;default:0" json:"points"`
+	Errors           int        `gorm:"not null;default:0" json:"errors"`
+	Reverted         int        `gorm:"not null;default:0" json:"reverted"`
+	LastError        string     `gorm:"type:text" json:"last_error,omitempty"`
+	LastPredictionID *uuid.UUID `gorm:"type:uuid" json:"last_prediction_id,omitempty"`
+	StartedAt        time.Time  `gorm:"type:timestamptz;not null" json:"started_at"`
+	FinishedAt       *time.Time `gorm:"type:timestamptz" json:"finished_at,omitempty"`
+	DurationMs       int64      `gorm:"not null;default:0" json:"duration_ms"`
+}
+
+func (ProdeSettlementRun) TableName() string { return "prode_settlement_runs" }
+
+func (r *ProdeSettlementRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *ProdeSettlementRun) finish(status string, now time.Time) {
+	r.Status = status
+	r.FinishedAt = &now
+	r.DurationMs = now.Sub(r.StartedAt).Milliseconds()
+}
+
+// checkResultChange decide si se puede pisar el resultado guardado en
+// current con homeGoals a awayGoals. Cargar el mismo marcador o cambiarlo
+// antes de que el settlement puntúe está permitido; con predicciones ya
+// puntuadas el cambio va por CorrectResult una vez evaluado el partido.
+func checkResultChange(current *ProdeMatch, homeGoals, awayGoals int, settled int64) error {
+	if settled == 0 || current.HomeGoals == nil || current.AwayGoals == nil {
+		return nil
+	}
+	if *current.HomeGoals == homeGoals && *current.AwayGoals == awayGoals {
+		return nil
+	}
+	return ErrResultSettled
+}
+
+// chunkResult son los conteos de una tanda ya confirmada.
+type chunkResult struct {
+	correct, incorrect, pendingInventory, points, reverted int
+	vouchers                                               []awardedVoucher
+}
+
+// awardedVoucher es un voucher asignado dentro de una tanda. El email se
+// manda recién cuando la transacción confirmó.
+type awardedVoucher struct {
+	userID  uuid.UUID
+	voucher *voucher.Voucher
+}
+
+func (s *Service) settleMatch(ctx context.Context, matchID uuid.UUID, actor string) (*SettlementResponse, error) {
+	match, err := s.repo.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if match.Status != MatchStatusResultRecorded {
+		return nil, ErrResultMissing
+	}
+
+	if match.HomeGoals == nil || match.AwayGoals == nil {
+		return nil, ErrResultMissing
+	}
+
+	if match.NeedsConfirmation() {
+		return nil, ErrResultUnconfirmed
+	}
+
+	run := &ProdeSettlementRun{
+		MatchID:   matchID,
+		Actor:     actor,
+		Status:    SettlementRunning,
+		StartedAt: s.clock.Now(),
+	}
+	if err := s.repo.StartSettlementRun(ctx, run, settlementStaleAfter); err != nil {
+		return nil, err
+	}
+
+	cursor := uuid.Nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, s.failSettlement(ctx, run, err)
+		}
+
+		preds, err := s.repo.GetUnsettledPredictions(ctx, matchID, cursor, s.chunkSize)
+		if err != nil {
+			return nil, s.failSettlement(ctx, run, err)
+		}
+		if len(preds) == 0 {
+			break
+		}
+
+		res, err := s.settleChunk(ctx, match, preds)
+		if err != nil {
+			return nil, s.failSettlement(ctx, run, err)
+		}
+
+		cursor = preds[len(preds)-1].ID
+		run.Processed += len(preds)
+		run.Correct += res.correct
+		run.Incorrect += res.incorrect
+		run.PendingInventory += res.pendingInventory
+		run.Points += res.points
+		run.Reverted += res.reverted
+		run.LastPredictionID = &cursor
+		if err := s.repo.UpdateSettlementRun(ctx, run); err != nil {
+			slog.ErrorContext(ctx, "error al guardar avance del settlement", "run_id", run.ID, "error", err)
+		}
+
+		for _, v := range res.vouchers {
+			if err := s.sendVoucherEmail(ctx, v.userID, v.voucher); err != nil {
+				slog.ErrorContext(ctx, "error al enviar email del voucher", "match_id", matchID,
+					"user_id", v.userID,
+					"error", err)
+			}
+		}
+	}
+
+	// Si entró una predicción mientras corría (no debería, el partido ya
+	// cerró) el partido no se evalúa y la próxima corrida la toma.
+	remaining, err := s.repo.CountUnsettledPredictions(ctx, matchID)
+	if err != nil {
+		return nil, s.failSettlement(ctx, run, err)
+	}
+	if remaining > 0 {
+		return nil, s.failSettlement(ctx, run, fmt.Errorf("prode: quedaron %d predicciones sin evaluar", remaining))
+	}
+
+	// Solo se evalúa si el partido sigue con el resultado con el que se
+	// puntuó; si alguien lo cambió mientras corría la corrida falla y el
+	// partido queda en RESULT_RECORDED para revisarlo.
+	if err := s.repo.MarkMatchEvaluated(ctx, matchID, *match.HomeGoals, *match.AwayGoals, actor); err != nil {
+		return nil, s.failSettlement(ctx, run, err)
+	}
+
+	run.finish(SettlementCompleted, s.clock.Now())
+	if err := s.repo.UpdateSettlementRun(ctx, run); err != nil {
+		slog.ErrorContext(ctx, "error al cerrar corrida de settlement", "run_id", run.ID, "error", err)
+	}
+
+	if run.PendingInventory > 0 {
+		s.notifyAdmins(ctx, match, run.PendingInventory)
+	}
+
+	slog.InfoContext(ctx, "settlement completado", "match_id", matchID,
+		"run_id", run.ID,
+		"total", run.Total,
+		"correct", run.Correct,
+		"incorrect", run.Incorrect,
+		"points", run.Points,
+		"pending_inventory", run.PendingInventory,
+		"reverted", run.Reverted,
+		"duration_ms", run.DurationMs)
+
+	return &SettlementResponse{
+		MatchID:          matchID.String(),
+		RunID:            run.ID.String(),
+		Status:           MatchStatusEvaluated,
+		TotalPreds:       run.Processed,
+		Correct:          run.Correct,
+		Points:           run.Points,
+		PendingInventory: run.PendingInventory,
+	}, nil
+}
+
+// settleChunk evalúa una tanda de predicciones en una sola transacción: o se
+// guardan todas (puntos, estado, premios y vouchers) o ninguna. Cada
+// predicción se evalúa con su última revisión antes del corte.
+func (s *Service) settleChunk(ctx context.Context, match *ProdeMatch, preds []ProdePrediction) (chunkResult, error) {
+	var res chunkResult
+	homeGoals, awayGoals := *match.HomeGoals, *match.AwayGoals
+
+	ids := make([]uuid.UUID, len(preds))
+	for i := range preds {
+		ids[i] = preds[i].ID
+	}
+	cutoff, err := match.CutoffAt()
+	if err != nil {
+		return chunkResult{}, err
+	}
+	locked, err := s.repo.GetLockedRevisions(ctx, ids, cutoff)
+	if err != nil {
+		return chunkResult{}, err
+	}
+
+	err = s.repo.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		txVoucherRepo := s.voucherRepo.WithTx(tx)
+
+		if err := txRepo.LockRecordedResult(ctx, match.ID, homeGoals, awayGoals); err != nil {
+			return err
+		}
+
+		for i := range preds {
+			pred := &preds[i]
+
+			rev, ok := locked[pred.ID]
+			if !ok {
+				// Sin nada guardado antes del corte la predicción no cuenta.
+				slog.WarnContext(ctx, "predicción sin revisión antes del corte", "match_id", match.ID,
+					"prediction_id", pred.ID,
+					"user_id", pred.UserID)
+				zero := 0
+				pred.Points = &zero
+				pred.Status = PredStatusIncorrect
+				if err := txRepo.UpdatePrediction(ctx, pred); err != nil {
+					return err
+				}
+				res.incorrect++
+				res.reverted++
+				continue
+			}
+			if pred.HomeGoals != rev.HomeGoals || pred.AwayGoals != rev.AwayGoals {
+				slog.WarnContext(ctx, "predicción distinta a la revisión del corte", "match_id", match.ID,
+					"prediction_id", pred.ID,
+					"user_id", pred.UserID,
+					"stored", fmt.Sprintf("%d-%d", pred.HomeGoals, pred.AwayGoals),
+					"locked", fmt.Sprintf("%d-%d", rev.HomeGoals, rev.AwayGoals))
+				pred.HomeGoals, pred.AwayGoals = rev.HomeGoals, rev.AwayGoals
+				res.reverted++
+			}
+
+			points := s.scoring.Points(pred.HomeGoals, pred.AwayGoals, homeGoals, awayGoals)
+			exact := pred.HomeGoals == homeGoals && pred.AwayGoals == awayGoals
+
+			pred.Points = &points
+			pred.Status = PredStatusIncorrect
+			if exact {
+				pred.Status = PredStatusCorrect
+			}
+			if err := txRepo.UpdatePrediction(ctx, pred); err != nil {
+				return err
+			}
+			res.points += points
+
+			if !exact {
+				res.incorrect++
+				continue
+			}
+			res.correct++
+
+			if !match.Tournament.GivesVouchers() {
+				continue
+			}
+
+			// Predicciones premiadas antes de que existieran los puntos: el
+			// premio ya existe y solo faltaba guardar los puntos.
+			existing, err := txRepo.GetRewardByPredictionID(ctx, pred.ID)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				if existing.Status == RewardStatusPendingInventory {
+					res.pendingInventory++
+				}
+				continue
+			}
+
+			reward := &ProdeReward{
+				PredictionID: pred.ID,
+				UserID:       pred.UserID,
+				Status:       RewardStatusPendingInventory,
+			}
+
+			v, err := txVoucherRepo.AssignNextVoucher(ctx, &voucher.VoucherRequest{UserID: pred.UserID})
+			switch {
+			case errors.Is(err, voucher.ErrNoAvailableVouchers):
+				res.pendingInventory++
+			case err != nil:
+				return err
+			default:
+				reward.Status = RewardStatusFulfilled
+				reward.VoucherID = &v.ID
+				res.vouchers = append(res.vouchers, awardedVoucher{userID: pred.UserID, voucher: v})
+			}
+
+			if err := txRepo.CreateReward(ctx, reward); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return chunkResult{}, err
+	}
+	return res, nil
+}
+
+// failSettlement cierra la corrida como fallida y devuelve err. Lo ya
+// guardado en tandas anteriores queda firme.
+func (s *Service) failSettlement(ctx context.Context, run *ProdeSettlementRun, err error) error {
+	run.Errors++
+	run.LastError = err.Error()
+	run.finish(SettlementFailed, s.clock.Now())
+
+	// La corrida se cierra aunque el contexto del request se haya cancelado.
+	if uerr := s.repo.UpdateSettlementRun(context.WithoutCancel(ctx), run); uerr != nil {
+		slog.ErrorContext(ctx, "error al cerrar corrida de settlement", "run_id", run.ID, "error", uerr)
+	}
+
+	slog.ErrorContext(ctx, "settlement interrumpido", "match_id", run.MatchID,
+		"run_id", run.ID,
+		"processed", run.Processed,
+		"total", run.Total,
+		"error", err)
+	return fmt.Errorf("prode: settlement %s: %w", run.ID, err)
+}
+
+// GetSettlementStatus devuelve el estado del settlement de un partido: cuántas
+// predicciones faltan evaluar y las últimas corridas.
+func (s *Service) GetSettlementStatus(ctx context.Context, matchID uuid.UUID) (*SettlementStatusResponse, error) {
+	match, err := s.repo.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	unsettled, err := s.repo.CountUnsettledPredictions(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := s.repo.GetSettlementRuns(ctx, matchID, 10)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SettlementStatusResponse{
+		MatchID:     matchID.String(),
+		MatchStatus: match.Status,
+		Unsettled:   unsettled,
+		Runs:        runs,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package prode
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckResultChange(t *testing.T) {
+	t.Parallel()
+
+	two, one := 2, 1
+	recorded := &ProdeMatch{Status: MatchStatusResultRecorded, HomeGoals: &two, AwayGoals: &one}
+
+	tests := []struct {
+		name      string
+		current   *ProdeMatch
+		home      int
+		away      int
+		settled   int64
+		wantError bool
+	}{
+		{name: "first result", current: &ProdeMatch{Status: MatchStatusClosed}, home: 2, away: 1},
+		{name: "same score after scoring", current: recorded, home: 2, away: 1, settled: 40},
+		{name: "new score before scoring", current: recorded, home: 1, away: 1},
+		{name: "new score after scoring", current: recorded, home: 1, away: 1, settled: 40, wantError: true},
+		{name: "new away goals after scoring", current: recorded, home: 2, away: 2, settled: 1, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkResultChange(tt.current, tt.home, tt.away, tt.settled)
+			if tt.wantError && !errors.Is(err, ErrResultSettled) {
+				t.Fatalf("checkResultChange() error = %v, want %v", err, ErrResultSettled)
+			}
+			if !tt.wantError && err != nil {
+				t.Fatalf("checkResultChange() error = %v, want nil", err)
+			}
+		})
+	}
+}
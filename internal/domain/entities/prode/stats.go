@@ -0,0 +1,141 @@
+package prode
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetMatchStats arma el reporte de un partido: resultados predichos, cómo
+// se repartieron entre local, empate y visitante, y cuándo predijo la gente.
+func (s *Service) GetMatchStats(ctx context.Context, matchID uuid.UUID) (*MatchStatsResponse, error) {
+	match, err := s.repo.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := s.repo.GetScoreDistribution(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	hours, err := s.repo.GetPredictionsPerHour(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &MatchStatsResponse{
+		MatchID:       matchID.String(),
+		Match:         match.Label(),
+		Status:        match.Status,
+		HomeGoals:     match.HomeGoals,
+		AwayGoals:     match.AwayGoals,
+		Scores:        make([]ScoreCount, len(scores)),
+		Participation: make([]ParticipationBucket, len(hours)),
+	}
+
+	for i, sc := range scores {
+		resp.Total += sc.Count
+		switch sign(sc.HomeGoals - sc.AwayGoals) {
+		case 1:
+			resp.Outcomes.HomeWin += sc.Count
+		case 0:
+			resp.Outcomes.Draw += sc.Count
+		default:
+			resp.Outcomes.AwayWin += sc.Count
+		}
+
+		isResult := match.HomeGoals != nil && match.AwayGoals != nil &&
+			sc.HomeGoals == *match.HomeGoals && sc.AwayGoals == *match.AwayGoals
+		if isResult {
+			resp.Correct = sc.Count
+		}
+		resp.Scores[i] = ScoreCount{
+			HomeGoals: sc.HomeGoals,
+			AwayGoals: sc.AwayGoals,
+			Count:     sc.Count,
+			IsResult:  isResult,
+		}
+	}
+
+	var cumulative int64
+	for i, h := range hours {
+		cumulative += h.Count
+		resp.Participation[i] = ParticipationBucket{
+			Hour:       h.Hour,
+			Count:      h.Count,
+			Cumulative: cumulative,
+		}
+	}
+
+	return resp, nil
+}
+
+// winnersCSVHeader son las columnas del export de ganadores.
+var winnersCSVHeader = []string{"nombre", "email", "prediccion", "puntos", "estado_premio", "voucher_id", "fecha_prediccion"}
+
+// ExportWinners escribe en w el CSV con los aciertos exactos del partido para
+// que el local entregue los premios en persona.
+func (s *Service) ExportWinners(ctx context.Context, matchID uuid.UUID, w io.Writer) error {
+	match, err := s.repo.GetMatchByID(ctx, matchID)
+	if err != nil {
+		return err
+	}
+
+	winners, err := s.repo.GetMatchWinners(ctx, matchID)
+	if err != nil {
+		return err
+	}
+
+	loc := match.Tournament.Location()
+	out := csv.NewWriter(w)
+	if err := out.Write(winnersCSVHeader); err != nil {
+		return err
+	}
+	for _, wr := range winners {
+		if err := out.Write(winnerRecord(wr, loc)); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// csvSafe evita que una planilla interprete como fórmula un dato cargado por
+// el usuario: las celdas que empiezan con =, +, -, @, tab o CR van con un
+// apóstrofo adelante.
+func csvSafe(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func winnerRecord(wr WinnerRow, loc *time.Location) []string {
+	points := ""
+	if wr.Points != nil {
+		points = strconv.Itoa(*wr.Points)
+	}
+	rewardStatus := wr.RewardStatus
+	if rewardStatus == "" {
+		rewardStatus = "SIN_PREMIO"
+	}
+	voucherID := ""
+	if wr.VoucherID != nil {
+		voucherID = wr.VoucherID.String()
+	}
+
+	return []string{
+		csvSafe(wr.Name),
+		csvSafe(wr.Email),
+		strconv.Itoa(wr.HomeGoals) + "-" + strconv.Itoa(wr.AwayGoals),
+		points,
+		rewardStatus,
+		voucherID,
+		wr.PredictedAt.In(loc).Format("2006-01-02 15:04"),
+	}
+}
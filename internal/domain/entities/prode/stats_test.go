@@ -0,0 +1,82 @@
+package prode
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestWinnerRecord(t *testing.T) {
+	t.Parallel()
+
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := 3
+	voucherID := uuid.MustParse("0f8fad5b-d9cb-469f-a165-70867728950e")
+	predictedAt := time.Date(2026, 6, 14, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		row  WinnerRow
+		want []string
+	}{
+		{
+			name: "con voucher",
+			row: WinnerRow{
+				Name: "Ana", Email: "ana@example.com", HomeGoals: 2, AwayGoals: 1,
+				Points: &points, RewardStatus: RewardStatusFulfilled, VoucherID: &voucherID, PredictedAt: predictedAt,
+			},
+			want: []string{"Ana", "ana@example.com", "2-1", "3", "FULFILLED", voucherID.String(), "2026-06-14 12:30"},
+		},
+		{
+			name: "torneo sin premios",
+			row: WinnerRow{
+				Name: "Beto", Email: "beto@example.com", HomeGoals: 0, AwayGoals: 0, PredictedAt: predictedAt,
+			},
+			want: []string{"Beto", "beto@example.com", "0-0", "", "SIN_PREMIO", "", "2026-06-14 12:30"},
+		},
+		{
+			name: "nombre con fórmula",
+			row: WinnerRow{
+				Name: "=HYPERLINK(\"http://x\")", Email: "+cmd@example.com", HomeGoals: 1, AwayGoals: 0, PredictedAt: predictedAt,
+			},
+			want: []string{"'=HYPERLINK(\"http://x\")", "'+cmd@example.com", "1-0", "", "SIN_PREMIO", "", "2026-06-14 12:30"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := winnerRecord(tt.row, loc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("winnerRecord() = %v, want %v", got, tt.want)
+			}
+			if len(tt.want) != len(winnersCSVHeader) {
+				t.Errorf("el registro tiene %d columnas y el header %d", len(tt.want), len(winnersCSVHeader))
+			}
+		})
+	}
+}
+
+func TestCSVSafe(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{
+		"Ana":       "Ana",
+		"":          "",
+		"=1+1":      "'=1+1",
+		"-2":        "'-2",
+		"@SUM(A1)":  "'@SUM(A1)",
+		"\tcmd":     "'\tcmd",
+		"\rcmd":     "'\rcmd",
+		"a=b@c.com": "a=b@c.com",
+	} {
+		if got := csvSafe(in); got != want {
+			t.Errorf("csvSafe(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package prode
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Reglas de premio de un torneo.
+const (
+	// RewardRuleExact da un voucher por cada resultado exacto.
+	RewardRuleExact = "EXACT"
+	// RewardRuleNone solo suma puntos para la tabla.
+	RewardRuleNone = "NONE"
+)
+
+// Valores por defecto de un torneo, los mismos con los que arrancó el prode
+// del Mundial.
+const (
+	DefaultTimezone      = "America/Argentina/Buenos_Aires"
+	DefaultCutoffMinutes = 60
+)
+
+// ProdeTournament agrupa partidos bajo sus propias reglas: zona horaria para
+// mostrar el corte, minutos de corte antes del inicio y regla de premios.
+type ProdeTournament struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Slug          string    `gorm:"type:varchar(50);not null;uniqueIndex" json:"slug"`
+	Name          string    `gorm:"type:varchar(100);not null" json:"name"`
+	Timezone      string    `gorm:"type:varchar(50);not null;default:America/Argentina/Buenos_Aires" json:"timezone"`
+	CutoffMinutes int       `gorm:"not null;default:60" json:"cutoff_minutes"`
+	RewardRule    string    `gorm:"type:varchar(20);not null;default:EXACT" json:"reward_rule"`
+	IsActive      bool      `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (ProdeTournament) TableName() string { return "prode_tournaments" }
+
+func (t *ProdeTournament) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// Cutoff devuelve cuánto antes del inicio se cierran las predicciones. Cero
+// cierra justo al inicio.
+func (t *ProdeTournament) Cutoff() time.Duration {
+	return time.Duration(t.CutoffMinutes) * time.Minute
+}
+
+// Location devuelve la zona horaria del torneo. Si la configurada no existe
+// usa la de Buenos Aires.
+func (t *ProdeTournament) Location() *time.Location {
+	name := DefaultTimezone
+	if t != nil && t.Timezone != "" {
+		name = t.Timezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc, _ = time.LoadLocation(DefaultTimezone)
+	}
+	return loc
+}
+
+// GivesVouchers indica si los aciertos exactos del torneo reciben voucher.
+func (t *ProdeTournament) GivesVouchers() bool {
+	return t == nil || t.RewardRule != RewardRuleNone
+}
+
+// ProdeTeam es un equipo que puede jugar partidos de cualquier torneo.
+// ExternalID es su id en el feed deportivo.
+type ProdeTeam struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Name       string    `gorm:"type:varchar(100);not null;uniqueIndex" json:"name"`
+	ShortName  string    `gorm:"type:varchar(10)" json:"short_name,omitempty"`
+	ExternalID string    `gorm:"type:varchar(100);index" json:"external_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (ProdeTeam) TableName() string { return "prode_teams" }
+
+func (t *ProdeTeam) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// ListTournaments devuelve los torneos; activeOnly deja solo los activos.
+func (s *Service) ListTournaments(ctx context.Context, activeOnly bool) ([]ProdeTournament, error) {
+	return s.repo.ListTournaments(ctx, activeOnly)
+}
+
+// ActiveTournament devuelve el torneo activo más reciente.
+func (s *Service) ActiveTournament(ctx context.Context) (*ProdeTournament, error) {
+	tournaments, err := s.repo.ListTournaments(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(tournaments) == 0 {
+		return nil, ErrTournamentNotFound
+	}
+	return &tournaments[0], nil
+}
+
+// CreateTournament crea un torneo, completando con los valores por defecto lo
+// que no venga en el request.
+func (s *Service) CreateTournament(ctx context.Context, req CreateTournamentRequest) (*ProdeTournament, error) {
+	t := ProdeTournament{
+		Slug:          strings.ToLower(strings.TrimSpace(req.Slug)),
+		Name:          strings.TrimSpace(req.Name),
+		Timezone:      req.Timezone,
+		CutoffMinutes: DefaultCutoffMinutes,
+		RewardRule:    req.RewardRule,
+		IsActive:      true,
+	}
+	if t.Timezone == "" {
+		t.Timezone = DefaultTimezone
+	}
+	if req.CutoffMinutes != nil {
+		t.CutoffMinutes = *req.CutoffMinutes
+	}
+	if t.RewardRule == "" {
+		t.RewardRule = RewardRuleExact
+	}
+
+	if err := validateTournament(&t); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateTournament(ctx, &t); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "torneo creado", "tournament_id", t.ID, "slug", t.Slug)
+	return &t, nil
+}
+
+// UpdateTournament actualiza las reglas de un torneo. El slug no cambia porque
+// es parte de las URLs públicas.
+func (s *Service) UpdateTournament(ctx context.Context, id uuid.UUID, req UpdateTournamentRequest) (*ProdeTournament, error) {
+	t, err := s.repo.GetTournamentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		t.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Timezone != nil {
+		t.Timezone = *req.Timezone
+	}
+	if req.CutoffMinutes != nil {
+		t.CutoffMinutes = *req.CutoffMinutes
+	}
+	if req.RewardRule != nil {
+		t.RewardRule = *req.RewardRule
+	}
+	if req.IsActive != nil {
+		t.IsActive = *req.IsActive
+	}
+
+	if err := validateTournament(t); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateTournament(ctx, t); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "torneo actualizado", "tournament_id", t.ID,
+		"reward_rule", t.RewardRule,
+		"is_active", t.IsActive)
+	return t, nil
+}
+
+// ListTeams devuelve todos los equipos.
+func (s *Service) ListTeams(ctx context.Context) ([]ProdeTeam, error) {
+	return s.repo.ListTeams(ctx)
+}
+
+// CreateTeam crea un equipo.
+func (s *Service) CreateTeam(ctx context.Context, req CreateTeamRequest) (*ProdeTeam, error) {
+	team := ProdeTeam{
+		Name:       strings.TrimSpace(req.Name),
+		ShortName:  strings.TrimSpace(req.ShortName),
+		ExternalID: strings.TrimSpace(req.ExternalID),
+	}
+	if err := s.repo.CreateTeam(ctx, &team); err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "equipo creado", "team_id", team.ID, "name", team.Name)
+	return &team, nil
+}
+
+func validateTournament(t *ProdeTournament) error {
+	if !validSlug(t.Slug) {
+		return fmt.Errorf("%w: el slug solo admite minúsculas, números y guiones", ErrInvalidTournament)
+	}
+	if t.Name == "" {
+		return fmt.Errorf("%w: falta el nombre", ErrInvalidTournament)
+	}
+	if _, err := time.LoadLocation(t.Timezone); err != nil {
+		return fmt.Errorf("%w: zona horaria %q desconocida", ErrInvalidTournament, t.Timezone)
+	}
+	if t.CutoffMinutes < 0 {
+		return fmt.Errorf("%w: cutoff_minutes no puede ser negativo", ErrInvalidTournament)
+	}
+	if t.RewardRule != RewardRuleExact && t.RewardRule != RewardRuleNone {
+		return fmt.Errorf("%w: reward_rule debe ser %s o %s", ErrInvalidTournament, RewardRuleExact, RewardRuleNone)
+	}
+	return nil
+}
+
+func validSlug(slug string) bool {
+	if slug == "" || len(slug) > 50 || slug[0] == '-' || slug[len(slug)-1] == '-' {
+		return false
+	}
+	for _, c := range slug {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
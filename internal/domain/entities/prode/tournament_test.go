@@ -0,0 +1,84 @@
+package prode
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMatchCutoffUsesTournament(t *testing.T) {
+	t.Parallel()
+
+	kickoff := time.Date(2026, 6, 14, 19, 0, 0, 0, time.UTC)
+
+	unloaded := ProdeMatch{KickoffAt: kickoff}
+	if _, err := unloaded.CutoffAt(); !errors.Is(err, ErrTournamentNotLoaded) {
+		t.Errorf("sin torneo: CutoffAt() error = %v, want ErrTournamentNotLoaded", err)
+	}
+	unloaded.Status = MatchStatusOpen
+	if _, err := unloaded.IsOpenForPrediction(kickoff); !errors.Is(err, ErrTournamentNotLoaded) {
+		t.Errorf("sin torneo: IsOpenForPrediction() error = %v, want ErrTournamentNotLoaded", err)
+	}
+
+	atKickoff := ProdeMatch{KickoffAt: kickoff, Tournament: &ProdeTournament{Timezone: DefaultTimezone}}
+	if got, err := atKickoff.CutoffAt(); err != nil || !got.Equal(kickoff) {
+		t.Errorf("corte de 0 minutos: CutoffAt() = %v, %v, want %v", got, err, kickoff)
+	}
+
+	m := ProdeMatch{
+		KickoffAt:  kickoff,
+		Tournament: &ProdeTournament{Timezone: "Europe/Madrid", CutoffMinutes: 15},
+	}
+	got, err := m.CutoffAt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := kickoff.Add(-15 * time.Minute); !got.Equal(want) {
+		t.Errorf("CutoffAt() = %v, want %v", got, want)
+	}
+	if loc := got.Location().String(); loc != "Europe/Madrid" {
+		t.Errorf("CutoffAt() location = %s, want Europe/Madrid", loc)
+	}
+}
+
+func TestValidateTournament(t *testing.T) {
+	t.Parallel()
+
+	valid := ProdeTournament{
+		Slug:          "copa-america-2028",
+		Name:          "Copa América 2028",
+		Timezone:      DefaultTimezone,
+		CutoffMinutes: DefaultCutoffMinutes,
+		RewardRule:    RewardRuleNone,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*ProdeTournament)
+		ok     bool
+	}{
+		{"válido", func(*ProdeTournament) {}, true},
+		{"slug con mayúsculas", func(tr *ProdeTournament) { tr.Slug = "Copa" }, false},
+		{"slug con guion al final", func(tr *ProdeTournament) { tr.Slug = "copa-" }, false},
+		{"sin nombre", func(tr *ProdeTournament) { tr.Name = "" }, false},
+		{"zona horaria desconocida", func(tr *ProdeTournament) { tr.Timezone = "Marte/Olympus" }, false},
+		{"cutoff negativo", func(tr *ProdeTournament) { tr.CutoffMinutes = -5 }, false},
+		{"regla desconocida", func(tr *ProdeTournament) { tr.RewardRule = "TOP3" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tour := valid
+			tt.modify(&tour)
+			err := validateTournament(&tour)
+			if tt.ok && err != nil {
+				t.Fatalf("validateTournament() = %v, want nil", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidTournament) {
+				t.Fatalf("validateTournament() = %v, want ErrInvalidTournament", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package prode
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Quién originó un cambio de estado.
+const (
+	ActorAdmin     = "ADMIN"
+	ActorScheduler = "SCHEDULER"
+	ActorFeed      = "FEED"
+)
+
+// ProdeMatchTransition registra cada cambio de estado de un partido. Las
+// correcciones de resultado quedan como EVALUATED -> EVALUATED con el
+// marcador anterior en Reason.
+type ProdeMatchTransition struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	MatchID    uuid.UUID `gorm:"type:uuid;not null;index" json:"match_id"`
+	FromStatus string    `gorm:"type:varchar(30);not null" json:"from_status"`
+	ToStatus   string    `gorm:"type:varchar(30);not null" json:"to_status"`
+	Actor      string    `gorm:"type:varchar(20);not null" json:"actor"`
+	Reason     string    `gorm:"type:varchar(255)" json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (ProdeMatchTransition) TableName() string { return "prode_match_transitions" }
+
+func (t *ProdeMatchTransition) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// TransitionError es un cambio de estado rechazado por la tabla de
+// transiciones. errors.Is(err, ErrInvalidTransition) lo reconoce.
+type TransitionError struct {
+	MatchID uuid.UUID
+	From    string
+	To      string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("prode: partido %s: %s -> %s: %v", e.MatchID, e.From, e.To, ErrInvalidTransition)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
@@ -0,0 +1,243 @@
+package proof
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/mercadopago"
+	"github.com/sebaactis/powermix-back-mobile/internal/validations"
+)
+
+// LinkPaymentIdentity vincula el DNI o la tarjeta de un comprobante del
+// usuario. Solo sirven comprobantes aprobados y no anulados: así se prueba
+// que el dato es suyo.
+func (s *Service) LinkPaymentIdentity(ctx context.Context, userID uuid.UUID, req *LinkRequest) (*PaymentLinkResponse, error) {
+	if fields, ok := s.validator.ValidateStruct(req); !ok {
+		return nil, &validations.ValidationError{Fields: fields}
+	}
+
+	p, err := s.repo.GetByID(ctx, req.ProofMPID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || p.UserID != userID || p.ReviewStatus != ReviewApproved || p.ReversedAt != nil {
+		return nil, fmt.Errorf("proof: link: %w (id_mp=%s)", ErrLinkUnverified, req.ProofMPID)
+	}
+
+	link := &PaymentLink{UserID: userID, Kind: req.Kind, VerifiedByIDMP: p.IDMP}
+	switch req.Kind {
+	case LinkKindDNI:
+		if deref(p.Dni) == "" {
+			return nil, fmt.Errorf("proof: link: %w (id_mp=%s sin dni)", ErrLinkUnverified, p.IDMP)
+		}
+		link.Value = *p.Dni
+	case LinkKindCard:
+		if deref(p.Last4Card) == "" || deref(p.CardID) == "" {
+			return nil, fmt.Errorf("proof: link: %w (id_mp=%s sin tarjeta)", ErrLinkUnverified, p.IDMP)
+		}
+		link.Value = cardLinkValue(*p.CardID, *p.Last4Card)
+	}
+
+	if err := s.repo.CreateLink(ctx, link); err != nil {
+		return nil, err
+	}
+
+	return toLinkResponse(link), nil
+}
+
+func (s *Service) ListPaymentLinks(ctx context.Context, userID uuid.UUID) ([]*PaymentLinkResponse, error) {
+	links, err := s.repo.ListLinks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*PaymentLinkResponse, len(links))
+	for i := range links {
+		out[i] = toLinkResponse(&links[i])
+	}
+	return out, nil
+}
+
+func (s *Service) DeletePaymentLink(ctx context.Context, userID, linkID uuid.UUID) error {
+	return s.repo.DeleteLink(ctx, userID, linkID)
+}
+
+func toLinkResponse(l *PaymentLink) *PaymentLinkResponse {
+	resp := &PaymentLinkResponse{ID: l.ID, Kind: l.Kind, CreatedAt: l.CreatedAt}
+	if l.Kind == LinkKindDNI {
+		resp.DNI = l.Value
+	} else {
+		resp.CardBrand, resp.Last4, _ = strings.Cut(l.Value, ":")
+	}
+	return resp
+}
+
+// AutoAttribute atribuye pagos aprobados a los usuarios que vincularon su DNI
+// o tarjeta. Si el DNI del pagador coincide con un solo usuario se crea el
+// comprobante como si lo hubiera cargado. Marca y últimos 4 dígitos los
+// comparten muchas tarjetas, así que esas coincidencias (o varias por DNI)
+// quedan como sugerencia para cada usuario. Los pagos que ya tienen
+// comprobante se ignoran.
+func (s *Service) AutoAttribute(ctx context.Context, payments []*mercadopago.PaymentDTO) (AutoAttributeResult, error) {
+	var res AutoAttributeResult
+
+	for _, pay := range payments {
+		if pay == nil || pay.Status != "approved" {
+			continue
+		}
+		idMP := fmt.Sprintf("%d", pay.PaymentID)
+
+		existing, err := s.repo.GetByID(ctx, idMP)
+		if err != nil {
+			return res, err
+		}
+		if existing != nil {
+			continue
+		}
+
+		matches, err := s.linkedUsers(ctx, pay)
+		if err != nil {
+			return res, err
+		}
+
+		if len(matches) == 0 {
+			continue
+		}
+
+		if userID, ok := attributionTarget(matches); ok {
+			_, err := s.Create(ctx, &ProofRequest{UserID: userID, IDMP: idMP})
+			if err != nil {
+				// El pago puede no ser elegible o Coffeeji puede fallar:
+				// no frena al resto del lote.
+				slog.WarnContext(ctx, "no se pudo atribuir el pago",
+					"id_mp", idMP, "user_id", userID, "error", err)
+				continue
+			}
+			res.Linked++
+			continue
+		}
+
+		for userID, matchedBy := range matches {
+			err := s.repo.SaveSuggestion(ctx, &Suggestion{
+				UserID:     userID,
+				IDMP:       idMP,
+				Amount:     pay.TotalPaidAmount,
+				ApprovedAt: pay.DateApproved,
+				MatchedBy:  matchedBy,
+				Status:     SuggestionPending,
+			})
+			if err != nil {
+				return res, err
+			}
+		}
+		res.Suggested++
+	}
+
+	return res, nil
+}
+
+// attributionTarget devuelve el usuario al que se le puede crear el
+// comprobante sin preguntar: el único que vinculó el DNI del pagador. Una
+// coincidencia solo por tarjeta nunca alcanza.
+func attributionTarget(matches map[uuid.UUID]string) (uuid.UUID, bool) {
+	var target uuid.UUID
+	n := 0
+	for userID, matchedBy := range matches {
+		if matchedBy == LinkKindDNI {
+			target = userID
+			n++
+		}
+	}
+	return target, n == 1
+}
+
+// linkedUsers devuelve los usuarios cuyo vínculo coincide con el pago y por
+// qué dato coincidió. El DNI tiene prioridad sobre la tarjeta.
+func (s *Service) linkedUsers(ctx context.Context, pay *mercadopago.PaymentDTO) (map[uuid.UUID]string, error) {
+	matches := map[uuid.UUID]string{}
+
+	if last4, brand := deref(pay.CardLast4), deref(pay.CardId); last4 != "" && brand != "" {
+		ids, err := s.repo.FindLinkedUsers(ctx, LinkKindCard, cardLinkValue(brand, last4))
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			matches[id] = LinkKindCard
+		}
+	}
+
+	if dni := deref(pay.PayerDNI); dni != "" {
+		ids, err := s.repo.FindLinkedUsers(ctx, LinkKindDNI, dni)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			matches[id] = LinkKindDNI
+		}
+	}
+
+	return matches, nil
+}
+
+func (s *Service) ListSuggested(ctx context.Context, userID uuid.UUID) ([]*SuggestionResponse, error) {
+	suggestions, err := s.repo.ListPendingSuggestions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*SuggestionResponse, len(suggestions))
+	for i, sg := range suggestions {
+		out[i] = &SuggestionResponse{
+			IDMP:       sg.IDMP,
+			Amount:     sg.Amount,
+			ApprovedAt: sg.ApprovedAt,
+			MatchedBy:  sg.MatchedBy,
+		}
+	}
+	return out, nil
+}
+
+// ClaimSuggestion carga el pago sugerido como comprobante del usuario. Pasa
+// por las mismas reglas y controles que una carga manual.
+func (s *Service) ClaimSuggestion(ctx context.Context, userID uuid.UUID, idMP string) (*ProofResponse, error) {
+	sg, err := s.pendingSuggestion(ctx, userID, idMP)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.Create(ctx, &ProofRequest{UserID: userID, IDMP: idMP})
+	if err != nil {
+		if errors.Is(err, ErrProofDuplicateID) {
+			_ = s.repo.SetSuggestionStatus(ctx, sg.ID, SuggestionDismissed)
+		}
+		return nil, err
+	}
+
+	if err := s.repo.SetSuggestionStatus(ctx, sg.ID, SuggestionClaimed); err != nil {
+		slog.WarnContext(ctx, "no se pudo cerrar la sugerencia", "id_mp", idMP, "error", err)
+	}
+	return resp, nil
+}
+
+func (s *Service) DismissSuggestion(ctx context.Context, userID uuid.UUID, idMP string) error {
+	sg, err := s.pendingSuggestion(ctx, userID, idMP)
+	if err != nil {
+		return err
+	}
+	return s.repo.SetSuggestionStatus(ctx, sg.ID, SuggestionDismissed)
+}
+
+func (s *Service) pendingSuggestion(ctx context.Context, userID uuid.UUID, idMP string) (*Suggestion, error) {
+	sg, err := s.repo.GetSuggestion(ctx, userID, idMP)
+	if err != nil {
+		return nil, err
+	}
+	if sg == nil || sg.Status != SuggestionPending {
+		return nil, fmt.Errorf("proof: suggestion: %w (id_mp=%s)", ErrSuggestionNotFound, idMP)
+	}
+	return sg, nil
+}
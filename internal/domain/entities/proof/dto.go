@@ -22,6 +22,29 @@ type ProofOthersRequest struct {
 	DNI   *string `json:"dni,omitempty"`
 }
 
+// SelectPaymentRequest elige uno de los candidatos devueltos por
+// AmbiguousPaymentError.
+type SelectPaymentRequest struct {
+	UserID         uuid.UUID `json:"user_id"`
+	SelectionToken string    `json:"selection_token" validate:"required"`
+	Ref            string    `json:"ref" validate:"required,max=16"`
+}
+
+const (
+	ReceiptStatusCreated           = "created"
+	ReceiptStatusNeedsConfirmation = "needs_confirmation"
+)
+
+// ReceiptResponse es el resultado de cargar un comprobante por foto. Si algún
+// dato no se leyó con confianza suficiente no se crea el comprobante: la app
+// muestra Extracted para que el usuario confirme y envíe /proof o /proof/others.
+type ReceiptResponse struct {
+	Status        string         `json:"status"`
+	Proof         *ProofResponse `json:"proof,omitempty"`
+	Extracted     ReceiptData    `json:"extracted"`
+	LowConfidence []string       `json:"low_confidence,omitempty"`
+}
+
 type ProofResponse struct {
 	UserID          uuid.UUID           `json:"user_id"`
 	IDMP            string              `json:"proof_mp_id"`
@@ -35,6 +58,9 @@ type ProofResponse struct {
 	Last4Card       *string             `json:"last4_card,omitempty"`
 	ExternalID      *string             `json:"external_id,omitempty"`
 	ProductName     *string             `json:"product_name,omitempty"`
+	Stamps          int                 `json:"stamps"`
+	ReviewStatus    string              `json:"review_status"`
+	ReversedAt      *time.Time          `json:"reversed_at,omitempty"`
 }
 
 type PaginatedProofResponse struct {
@@ -52,3 +78,60 @@ type ProofFilters struct {
 	MinAmount     *float64
 	MaxAmount     *float64
 }
+
+// ReviewItem es un comprobante de la cola de revisión antifraude.
+type ReviewItem struct {
+	ID          uuid.UUID           `json:"id"`
+	UserID      uuid.UUID           `json:"user_id"`
+	IDMP        string              `json:"proof_mp_id"`
+	AmountMP    float64             `json:"amount_mp"`
+	ProofDate   utils.FormattedTime `json:"proof_date"`
+	Dni         *string             `json:"dni,omitempty"`
+	CardType    *string             `json:"card_type,omitempty"`
+	Last4Card   *string             `json:"last4_card,omitempty"`
+	Stamps      int                 `json:"stamps"`
+	RiskScore   float64             `json:"risk_score"`
+	RiskSignals []string            `json:"risk_signals"`
+}
+
+type PaginatedReviewResponse struct {
+	Items    []*ReviewItem `json:"items"`
+	Page     int           `json:"page"`
+	PageSize int           `json:"pageSize"`
+	Total    int64         `json:"total"`
+	HasMore  bool          `json:"hasMore"`
+}
+
+// ReviewRequest es la decisión del admin sobre un comprobante en revisión.
+type ReviewRequest struct {
+	Decision string `json:"decision" validate:"required,oneof=approve reject"`
+}
+
+// LinkRequest vincula el DNI o la tarjeta de un comprobante propio ya
+// aprobado.
+type LinkRequest struct {
+	Kind      string `json:"kind" validate:"required,oneof=dni card"`
+	ProofMPID string `json:"proof_mp_id" validate:"required,max=255"`
+}
+
+type PaymentLinkResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Kind      string    `json:"kind"`
+	DNI       string    `json:"dni,omitempty"`
+	CardBrand string    `json:"card_brand,omitempty"`
+	Last4     string    `json:"last4,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SuggestionResponse struct {
+	IDMP       string    `json:"proof_mp_id"`
+	Amount     float64   `json:"amount"`
+	ApprovedAt time.Time `json:"approved_at"`
+	MatchedBy  string    `json:"matched_by"`
+}
+
+// AutoAttributeResult resume una pasada de atribución automática.
+type AutoAttributeResult struct {
+	Linked    int
+	Suggested int
+}
@@ -0,0 +1,138 @@
+package proof
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sebaactis/powermix-back-mobile/internal/utils"
+)
+
+// PaymentFacts son los datos del pago que evalúan las reglas de elegibilidad,
+// sin importar si vino por ID o por conciliación.
+type PaymentFacts struct {
+	PaymentID   string
+	Status      string
+	Amount      float64
+	ApprovedAt  time.Time
+	CollectorID int64
+	ProductName string
+}
+
+// Rule decide si un pago puede cargarse como comprobante. Devuelve nil si
+// pasa o un error que envuelve alguno de los sentinels de elegibilidad.
+type Rule interface {
+	Check(f PaymentFacts, now time.Time) error
+}
+
+// RuleFunc adapta una función a Rule.
+type RuleFunc func(f PaymentFacts, now time.Time) error
+
+func (fn RuleFunc) Check(f PaymentFacts, now time.Time) error {
+	return fn(f, now)
+}
+
+// Rules se evalúan en orden; gana el primer rechazo.
+type Rules []Rule
+
+func (rs Rules) Check(f PaymentFacts, now time.Time) error {
+	for _, r := range rs {
+		if err := r.Check(f, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EligibilityConfig arma las reglas por defecto. Los valores cero desactivan
+// la regla correspondiente, salvo el estado, que siempre debe ser approved.
+type EligibilityConfig struct {
+	MaxAge          time.Duration
+	MinAmount       float64
+	CollectorID     int64
+	AllowedProducts []string
+}
+
+func NewRules(cfg EligibilityConfig) Rules {
+	rules := Rules{RequireApproved()}
+	if cfg.MaxAge > 0 {
+		rules = append(rules, MaxAge(cfg.MaxAge))
+	}
+	if cfg.MinAmount > 0 {
+		rules = append(rules, MinAmount(cfg.MinAmount))
+	}
+	if cfg.CollectorID != 0 {
+		rules = append(rules, Collector(cfg.CollectorID))
+	}
+	if len(cfg.AllowedProducts) > 0 {
+		rules = append(rules, ProductAllowlist(cfg.AllowedProducts))
+	}
+	return rules
+}
+
+func RequireApproved() Rule {
+	return RuleFunc(func(f PaymentFacts, _ time.Time) error {
+		if f.Status != "approved" {
+			return fmt.Errorf("proof: %w (id_mp=%s status=%s)", ErrPaymentNotApproved, f.PaymentID, f.Status)
+		}
+		return nil
+	})
+}
+
+func MaxAge(max time.Duration) Rule {
+	return RuleFunc(func(f PaymentFacts, now time.Time) error {
+		if now.Sub(f.ApprovedAt) > max {
+			return fmt.Errorf("proof: %w (id_mp=%s approved=%s)", ErrPaymentTooOld, f.PaymentID, f.ApprovedAt.Format(time.RFC3339))
+		}
+		return nil
+	})
+}
+
+func MinAmount(min float64) Rule {
+	return RuleFunc(func(f PaymentFacts, _ time.Time) error {
+		if f.Amount < min {
+			return fmt.Errorf("proof: %w (id_mp=%s amount=%.2f)", ErrAmountTooLow, f.PaymentID, f.Amount)
+		}
+		return nil
+	})
+}
+
+func Collector(id int64) Rule {
+	return RuleFunc(func(f PaymentFacts, _ time.Time) error {
+		if f.CollectorID != id {
+			return fmt.Errorf("proof: %w (id_mp=%s collector=%d)", ErrForeignCollector, f.PaymentID, f.CollectorID)
+		}
+		return nil
+	})
+}
+
+// ProductAllowlist compara sin distinguir mayúsculas ni espacios de más.
+func ProductAllowlist(products []string) Rule {
+	allowed := make(map[string]struct{}, len(products))
+	for _, p := range products {
+		allowed[normalizeProduct(p)] = struct{}{}
+	}
+
+	return RuleFunc(func(f PaymentFacts, _ time.Time) error {
+		if _, ok := allowed[normalizeProduct(f.ProductName)]; !ok {
+			return fmt.Errorf("proof: %w (id_mp=%s product=%q)", ErrProductNotEligible, f.PaymentID, f.ProductName)
+		}
+		return nil
+	})
+}
+
+func normalizeProduct(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// eligibilityErrCodes asigna a cada rechazo su código en la respuesta.
+var eligibilityErrCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrPaymentNotApproved, utils.ErrCodePaymentNotApproved},
+	{ErrPaymentTooOld, utils.ErrCodePaymentTooOld},
+	{ErrAmountTooLow, utils.ErrCodeAmountTooLow},
+	{ErrForeignCollector, utils.ErrCodeForeignCollector},
+	{ErrProductNotEligible, utils.ErrCodeProductNotEligible},
+}
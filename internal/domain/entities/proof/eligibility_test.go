@@ -0,0 +1,65 @@
+package proof
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRules_Check(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+	rules := NewRules(EligibilityConfig{
+		MaxAge:          30 * 24 * time.Hour,
+		MinAmount:       1000,
+		CollectorID:     42,
+		AllowedProducts: []string{"Café con leche", "Latte"},
+	})
+
+	valid := PaymentFacts{
+		PaymentID:   "123",
+		Status:      "approved",
+		Amount:      1500,
+		ApprovedAt:  now.Add(-2 * time.Hour),
+		CollectorID: 42,
+		ProductName: "  café CON leche ",
+	}
+
+	if err := rules.Check(valid, now); err != nil {
+		t.Fatalf("pago válido rechazado: %v", err)
+	}
+
+	cases := map[string]struct {
+		mutate func(*PaymentFacts)
+		want   error
+	}{
+		"pendiente":     {func(f *PaymentFacts) { f.Status = "pending" }, ErrPaymentNotApproved},
+		"devuelto":      {func(f *PaymentFacts) { f.Status = "refunded" }, ErrPaymentNotApproved},
+		"viejo":         {func(f *PaymentFacts) { f.ApprovedAt = now.Add(-31 * 24 * time.Hour) }, ErrPaymentTooOld},
+		"monto bajo":    {func(f *PaymentFacts) { f.Amount = 999.99 }, ErrAmountTooLow},
+		"otro cobrador": {func(f *PaymentFacts) { f.CollectorID = 7 }, ErrForeignCollector},
+		"otro producto": {func(f *PaymentFacts) { f.ProductName = "Agua" }, ErrProductNotEligible},
+	}
+	for name, tc := range cases {
+		f := valid
+		tc.mutate(&f)
+		if err := rules.Check(f, now); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, quería %v", name, err, tc.want)
+		}
+	}
+}
+
+func TestNewRules_ZeroValuesOnlyRequireApproved(t *testing.T) {
+	t.Parallel()
+
+	rules := NewRules(EligibilityConfig{})
+	if len(rules) != 1 {
+		t.Fatalf("len(rules) = %d, quería 1", len(rules))
+	}
+
+	old := PaymentFacts{Status: "approved", Amount: 1, ApprovedAt: time.Unix(0, 0)}
+	if err := rules.Check(old, time.Now()); err != nil {
+		t.Fatalf("sin configuración no debería rechazar: %v", err)
+	}
+}
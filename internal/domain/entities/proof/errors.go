@@ -9,4 +9,19 @@ var (
 	ErrPaymentNotFound       = errors.New("proof: no se encontró un pago que coincida")
 	ErrProofDuplicateMP      = errors.New("proof: ya guardaste un comprobante con este pago de Mercado Pago")
 	ErrProofIDRequired       = errors.New("proof: id es requerido")
+	ErrReceiptUnsupported    = errors.New("proof: formato de imagen no soportado, usá JPG o PNG")
+	ErrReceiptUnreadable     = errors.New("proof: no pudimos leer el comprobante, probá con una foto más nítida")
+	ErrOCRUnavailable        = errors.New("proof: lectura de comprobantes no disponible")
+	ErrSelectionInvalid      = errors.New("proof: la selección de pago no es válida")
+	ErrSelectionExpired      = errors.New("proof: la selección de pago venció, volvé a cargar el comprobante")
+	ErrPaymentNotApproved    = errors.New("proof: el pago no está aprobado")
+	ErrPaymentTooOld         = errors.New("proof: el pago es demasiado antiguo para sumar sellos")
+	ErrAmountTooLow          = errors.New("proof: el monto del pago no alcanza el mínimo para sumar sellos")
+	ErrForeignCollector      = errors.New("proof: el pago no corresponde a Powermix")
+	ErrProductNotEligible    = errors.New("proof: el producto no participa de la promoción")
+	ErrReviewNotPending      = errors.New("proof: el comprobante no está pendiente de revisión")
+	ErrLinkUnverified        = errors.New("proof: necesitás un comprobante aprobado propio con ese dato para vincularlo")
+	ErrLinkExists            = errors.New("proof: ese dato ya está vinculado a tu cuenta")
+	ErrLinkNotFound          = errors.New("proof: vínculo no encontrado")
+	ErrSuggestionNotFound    = errors.New("proof: sugerencia no encontrada")
 )
@@ -0,0 +1,171 @@
+package proof
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Señales de riesgo que puede levantar un comprobante.
+const (
+	SignalSharedDNI          = "shared_dni"
+	SignalSharedCard         = "shared_card"
+	SignalPayerEmailMismatch = "payer_email_mismatch"
+	SignalBurst              = "burst"
+	SignalPredatesAccount    = "predates_account"
+)
+
+// FraudConfig ajusta el puntaje de riesgo. Un comprobante con puntaje igual o
+// mayor a Threshold queda en PENDING_REVIEW.
+type FraudConfig struct {
+	Threshold float64
+	// SharedAccounts es cuántas otras cuentas pueden haber cargado pagos con
+	// el mismo DNI o tarjeta antes de levantar la señal.
+	SharedAccounts int
+	BurstWindow    time.Duration
+	BurstLimit     int
+	// PredatesAccount es cuánto antes del alta de la cuenta puede ser el pago.
+	PredatesAccount time.Duration
+	Weights         map[string]float64
+}
+
+func DefaultFraudConfig() FraudConfig {
+	return FraudConfig{
+		Threshold:       0.5,
+		SharedAccounts:  2,
+		BurstWindow:     time.Hour,
+		BurstLimit:      5,
+		PredatesAccount: 24 * time.Hour,
+		Weights: map[string]float64{
+			SignalSharedDNI:          0.5,
+			SignalSharedCard:         0.4,
+			SignalPayerEmailMismatch: 0.2,
+			SignalBurst:              0.3,
+			SignalPredatesAccount:    0.4,
+		},
+	}
+}
+
+// FraudFacts son los datos del comprobante y de la cuenta que lo carga.
+type FraudFacts struct {
+	UserID        uuid.UUID
+	UserEmail     string
+	UserCreatedAt time.Time
+	PayerEmail    *string
+	DNI           *string
+	CardID        *string
+	Last4         *string
+	ApprovedAt    time.Time
+}
+
+// fraudHistory consulta los comprobantes ya guardados. Lo implementa Repository.
+type fraudHistory interface {
+	CountOtherUsersByDNI(ctx context.Context, dni string, userID uuid.UUID) (int64, error)
+	CountOtherUsersByCard(ctx context.Context, cardID, last4 string, userID uuid.UUID) (int64, error)
+	CountByUserSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
+}
+
+// Risk es el resultado del análisis.
+type Risk struct {
+	Score   float64
+	Signals []string
+}
+
+func (r Risk) NeedsReview(cfg FraudConfig) bool {
+	return cfg.Threshold > 0 && r.Score >= cfg.Threshold
+}
+
+func scoreFraud(ctx context.Context, h fraudHistory, cfg FraudConfig, f FraudFacts, now time.Time) (Risk, error) {
+	var risk Risk
+	add := func(signal string) {
+		risk.Signals = append(risk.Signals, signal)
+		risk.Score += cfg.Weights[signal]
+	}
+
+	if dni := deref(f.DNI); dni != "" {
+		n, err := h.CountOtherUsersByDNI(ctx, dni, f.UserID)
+		if err != nil {
+			return Risk{}, err
+		}
+		if n >= int64(cfg.SharedAccounts) {
+			add(SignalSharedDNI)
+		}
+	}
+
+	if last4 := deref(f.Last4); last4 != "" {
+		n, err := h.CountOtherUsersByCard(ctx, deref(f.CardID), last4, f.UserID)
+		if err != nil {
+			return Risk{}, err
+		}
+		if n >= int64(cfg.SharedAccounts) {
+			add(SignalSharedCard)
+		}
+	}
+
+	if payer := deref(f.PayerEmail); payer != "" && !strings.EqualFold(strings.TrimSpace(payer), strings.TrimSpace(f.UserEmail)) {
+		add(SignalPayerEmailMismatch)
+	}
+
+	if cfg.BurstLimit > 0 {
+		n, err := h.CountByUserSince(ctx, f.UserID, now.Add(-cfg.BurstWindow))
+		if err != nil {
+			return Risk{}, err
+		}
+		// Contamos el comprobante que se está cargando.
+		if n+1 > int64(cfg.BurstLimit) {
+			add(SignalBurst)
+		}
+	}
+
+	if !f.UserCreatedAt.IsZero() && f.ApprovedAt.Before(f.UserCreatedAt.Add(-cfg.PredatesAccount)) {
+		add(SignalPredatesAccount)
+	}
+
+	return risk, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// applyRisk puntúa el comprobante antes de guardarlo y lo deja en revisión si
+// supera el umbral.
+func (s *Service) applyRisk(ctx context.Context, p *Proof, payerEmail *string) error {
+	u, err := s.userService.GetByID(ctx, p.UserID)
+	if err != nil {
+		return err
+	}
+
+	risk, err := scoreFraud(ctx, s.repo, s.fraud, FraudFacts{
+		UserID:        p.UserID,
+		UserEmail:     u.Email,
+		UserCreatedAt: u.CreatedAt,
+		PayerEmail:    payerEmail,
+		DNI:           p.Dni,
+		CardID:        p.CardID,
+		Last4:         p.Last4Card,
+		ApprovedAt:    p.DateApprovedMP.Time,
+	}, time.Now())
+	if err != nil {
+		return err
+	}
+
+	p.RiskScore = risk.Score
+	p.ReviewStatus = ReviewApproved
+	if len(risk.Signals) > 0 {
+		signals := strings.Join(risk.Signals, ",")
+		p.RiskSignals = &signals
+	}
+	if risk.NeedsReview(s.fraud) {
+		p.ReviewStatus = ReviewPending
+		slog.WarnContext(ctx, "comprobante enviado a revisión",
+			"id_mp", p.IDMP, "user_id", p.UserID, "score", risk.Score, "signals", risk.Signals)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package proof
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fakeFraudHistory struct {
+	byDNI, byCard, recent int64
+}
+
+func (f fakeFraudHistory) CountOtherUsersByDNI(context.Context, string, uuid.UUID) (int64, error) {
+	return f.byDNI, nil
+}
+
+func (f fakeFraudHistory) CountOtherUsersByCard(context.Context, string, string, uuid.UUID) (int64, error) {
+	return f.byCard, nil
+}
+
+func (f fakeFraudHistory) CountByUserSince(context.Context, uuid.UUID, time.Time) (int64, error) {
+	return f.recent, nil
+}
+
+func TestScoreFraud(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultFraudConfig()
+	now := time.Date(2025, 11, 20, 12, 0, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+
+	clean := FraudFacts{
+		UserID:        uuid.New(),
+		UserEmail:     "Ana@Example.com",
+		UserCreatedAt: now.Add(-30 * 24 * time.Hour),
+		PayerEmail:    str("ana@example.com"),
+		DNI:           str("30111222"),
+		CardID:        str("visa"),
+		Last4:         str("1234"),
+		ApprovedAt:    now.Add(-time.Hour),
+	}
+
+	risk, err := scoreFraud(context.Background(), fakeFraudHistory{byDNI: 1, byCard: 1, recent: 2}, cfg, clean, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(risk.Signals) != 0 || risk.NeedsReview(cfg) {
+		t.Fatalf("comprobante limpio marcado: %+v", risk)
+	}
+
+	suspicious := clean
+	suspicious.PayerEmail = str("otro@example.com")
+	suspicious.UserCreatedAt = now
+	suspicious.ApprovedAt = now.Add(-10 * 24 * time.Hour)
+
+	risk, err = scoreFraud(context.Background(), fakeFraudHistory{byDNI: 3, byCard: 2, recent: 5}, cfg, suspicious, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{SignalSharedDNI, SignalSharedCard, SignalPayerEmailMismatch, SignalBurst, SignalPredatesAccount} {
+		if !slices.Contains(risk.Signals, want) {
+			t.Errorf("falta la señal %s en %v", want, risk.Signals)
+		}
+	}
+	if !risk.NeedsReview(cfg) {
+		t.Errorf("score %.2f debería ir a revisión", risk.Score)
+	}
+
+	// Una sola señal leve no alcanza el umbral.
+	mismatch := clean
+	mismatch.PayerEmail = str("otro@example.com")
+	risk, _ = scoreFraud(context.Background(), fakeFraudHistory{}, cfg, mismatch, now)
+	if risk.NeedsReview(cfg) {
+		t.Errorf("solo email distinto no debería ir a revisión (score %.2f)", risk.Score)
+	}
+
+	cfg.Threshold = 0
+	risk, _ = scoreFraud(context.Background(), fakeFraudHistory{byDNI: 10}, cfg, clean, now)
+	if risk.NeedsReview(cfg) {
+		t.Error("umbral 0 desactiva la revisión")
+	}
+}
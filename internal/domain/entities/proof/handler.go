@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sebaactis/powermix-back-mobile/internal/middlewares"
 	"github.com/sebaactis/powermix-back-mobile/internal/utils"
 	"github.com/sebaactis/powermix-back-mobile/internal/validations"
@@ -84,6 +86,83 @@ func (h *HTTPHandler) CreateFromOthers(w http.ResponseWriter, r *http.Request) {
 	utils.WriteSuccess(w, http.StatusCreated, proof)
 }
 
+func (h *HTTPHandler) SelectPayment(w http.ResponseWriter, r *http.Request) {
+	var req SelectPaymentRequest
+
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProofUnauthorized(w)
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProofValidation(w, "Error al intentar parsear el request, por favor validar el mismo", nil)
+		return
+	}
+
+	req.UserID = userID
+
+	proof, err := h.service.SelectPayment(r.Context(), &req)
+	if err != nil {
+		if fields, ok := validations.AsValidationError(err); ok {
+			writeProofValidation(w, "Error de validación", fields)
+			return
+		}
+		writeProofServiceError(r.Context(), w, err, "No se pudo crear el comprobante", userID)
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusCreated, proof)
+}
+
+// MaxReceiptSize limita la foto del comprobante. Exportada para que el router
+// aplique el mismo límite antes de leer el body.
+const MaxReceiptSize = 5 << 20
+
+func (h *HTTPHandler) CreateFromReceipt(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProofUnauthorized(w)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, MaxReceiptSize)
+	if err := r.ParseMultipartForm(MaxReceiptSize); err != nil {
+		writeProofValidation(w, "La imagen es inválida o supera los 5MB", nil)
+		return
+	}
+
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		writeProofValidation(w, "Falta la imagen del comprobante (campo image)", nil)
+		return
+	}
+	defer file.Close()
+
+	image, err := io.ReadAll(file)
+	if err != nil {
+		writeProofValidation(w, "No se pudo leer la imagen", nil)
+		return
+	}
+
+	// El tipo se detecta del contenido: el Content-Type que manda el cliente no es confiable.
+	result, err := h.service.CreateFromReceipt(r.Context(), userID, image, http.DetectContentType(image))
+	if err != nil {
+		if fields, ok := validations.AsValidationError(err); ok {
+			writeProofValidation(w, "Error de validación", fields)
+			return
+		}
+		writeProofServiceError(r.Context(), w, err, "No se pudo procesar el comprobante", userID)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Status == ReceiptStatusCreated {
+		status = http.StatusCreated
+	}
+	utils.WriteSuccess(w, status, result)
+}
+
 func (h *HTTPHandler) GetAllByUserID(w http.ResponseWriter, r *http.Request) {
 	userID, ok := middlewares.UserIDFromContext(r.Context())
 	if !ok {
@@ -211,6 +290,205 @@ func (h *HTTPHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	utils.WriteSuccess(w, http.StatusOK, proof)
 }
 
+func (h *HTTPHandler) LinkPaymentIdentity(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProofUnauthorized(w)
+		return
+	}
+
+	var req LinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProofValidation(w, "Error al intentar parsear el request, por favor validar el mismo", nil)
+		return
+	}
+
+	link, err := h.service.LinkPaymentIdentity(r.Context(), userID, &req)
+	if err != nil {
+		if fields, ok := validations.AsValidationError(err); ok {
+			writeProofValidation(w, "Error de validación", fields)
+			return
+		}
+		if errors.Is(err, ErrLinkExists) {
+			utils.WriteError(w, http.StatusConflict, utils.WriteErrorOpts{
+				Code:    utils.ErrCodeDuplicateEntry,
+				Message: ErrLinkExists.Error(),
+			})
+			return
+		}
+		writeProofServiceError(r.Context(), w, err, "No se pudo vincular el dato de pago", userID)
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusCreated, link)
+}
+
+func (h *HTTPHandler) ListPaymentLinks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProofUnauthorized(w)
+		return
+	}
+
+	links, err := h.service.ListPaymentLinks(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al listar vínculos de pago", "user_id", userID, "error", err)
+		writeProofInternal(w, "No se pudieron recuperar los datos de pago vinculados")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, links)
+}
+
+func (h *HTTPHandler) DeletePaymentLink(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProofUnauthorized(w)
+		return
+	}
+
+	linkID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeProofValidation(w, "El id del vínculo es inválido", nil)
+		return
+	}
+
+	if err := h.service.DeletePaymentLink(r.Context(), userID, linkID); err != nil {
+		if errors.Is(err, ErrLinkNotFound) {
+			writeProofNotFound(w, "Vínculo no encontrado")
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al borrar vínculo de pago", "user_id", userID, "error", err)
+		writeProofInternal(w, "No se pudo borrar el vínculo")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler) ListSuggested(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProofUnauthorized(w)
+		return
+	}
+
+	suggestions, err := h.service.ListSuggested(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al listar pagos sugeridos", "user_id", userID, "error", err)
+		writeProofInternal(w, "No se pudieron recuperar los pagos sugeridos")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, suggestions)
+}
+
+func (h *HTTPHandler) ClaimSuggestion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProofUnauthorized(w)
+		return
+	}
+
+	proof, err := h.service.ClaimSuggestion(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, ErrSuggestionNotFound) {
+			writeProofNotFound(w, "Sugerencia no encontrada")
+			return
+		}
+		writeProofServiceError(r.Context(), w, err, "No se pudo crear el comprobante", userID)
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusCreated, proof)
+}
+
+func (h *HTTPHandler) DismissSuggestion(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProofUnauthorized(w)
+		return
+	}
+
+	if err := h.service.DismissSuggestion(r.Context(), userID, r.PathValue("id")); err != nil {
+		if errors.Is(err, ErrSuggestionNotFound) {
+			writeProofNotFound(w, "Sugerencia no encontrada")
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al descartar sugerencia", "user_id", userID, "error", err)
+		writeProofInternal(w, "No se pudo descartar la sugerencia")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler) ListPendingReview(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	page := 1
+	pageSize := 20
+
+	if v, err := strconv.Atoi(q.Get("page")); err == nil && v > 0 {
+		page = v
+	}
+	if v, err := strconv.Atoi(q.Get("pageSize")); err == nil && v > 0 && v <= 100 {
+		pageSize = v
+	}
+
+	resp, err := h.service.ListPendingReview(r.Context(), page, pageSize)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al listar comprobantes en revisión", "error", err)
+		writeProofInternal(w, "No se pudo recuperar la cola de revisión")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, resp)
+}
+
+func (h *HTTPHandler) Review(w http.ResponseWriter, r *http.Request) {
+	reviewerID, ok := middlewares.UserIDFromContext(r.Context())
+	if !ok {
+		writeProofUnauthorized(w)
+		return
+	}
+
+	proofID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeProofValidation(w, "El id del comprobante es inválido", nil)
+		return
+	}
+
+	var req ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProofValidation(w, "Error al intentar parsear el request, por favor validar el mismo", nil)
+		return
+	}
+
+	proof, err := h.service.Review(r.Context(), proofID, reviewerID, &req)
+	if err != nil {
+		if fields, ok := validations.AsValidationError(err); ok {
+			writeProofValidation(w, "Error de validación", fields)
+			return
+		}
+		if errors.Is(err, ErrProofNotFoundID) {
+			writeProofNotFound(w, "Comprobante no encontrado")
+			return
+		}
+		if errors.Is(err, ErrReviewNotPending) {
+			utils.WriteError(w, http.StatusConflict, utils.WriteErrorOpts{
+				Code:    utils.ErrCodeConflict,
+				Message: ErrReviewNotPending.Error(),
+			})
+			return
+		}
+		writeProofServiceError(r.Context(), w, err, "No se pudo revisar el comprobante", reviewerID)
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, proof)
+}
+
 func writeProofUnauthorized(w http.ResponseWriter) {
 	utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
 		Code:    utils.ErrCodeUnauthorized,
@@ -242,11 +520,34 @@ func writeProofInternal(w http.ResponseWriter, message string) {
 
 // writeProofServiceError mapea errores del service a respuestas de la API. Devuelve true si lo manejó.
 func writeProofServiceError(ctx context.Context, w http.ResponseWriter, err error, internalMessage string, userID interface{}) bool {
+	var ambiguous *AmbiguousPaymentError
+	if errors.As(err, &ambiguous) {
+		utils.WriteError(w, http.StatusConflict, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeConflict,
+			Message: "Encontramos varios pagos posibles, elegí el tuyo",
+			Fields:  ambiguous,
+		})
+		return true
+	}
+	for _, e := range eligibilityErrCodes {
+		if errors.Is(err, e.err) {
+			utils.WriteError(w, http.StatusUnprocessableEntity, utils.WriteErrorOpts{
+				Code:    e.code,
+				Message: e.err.Error(),
+			})
+			return true
+		}
+	}
 	if errors.Is(err, ErrProofDuplicateID) ||
 		errors.Is(err, ErrProofNotFoundID) ||
 		errors.Is(err, ErrPaymentNotFound) ||
 		errors.Is(err, ErrProofDuplicateMP) ||
-		errors.Is(err, ErrProofIDRequired) {
+		errors.Is(err, ErrProofIDRequired) ||
+		errors.Is(err, ErrReceiptUnsupported) ||
+		errors.Is(err, ErrReceiptUnreadable) ||
+		errors.Is(err, ErrSelectionInvalid) ||
+		errors.Is(err, ErrSelectionExpired) ||
+		errors.Is(err, ErrLinkUnverified) {
 		writeProofValidation(w, err.Error(), nil)
 		return true
 	}
@@ -0,0 +1,58 @@
+package proof
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	LinkKindDNI  = "dni"
+	LinkKindCard = "card"
+)
+
+// PaymentLink asocia un DNI o una tarjeta (marca + últimos 4) a un usuario
+// para atribuirle automáticamente los pagos que coincidan. Se verifica con un
+// comprobante aprobado del propio usuario que tenga ese dato. Value es el DNI
+// o "marca:last4" según Kind.
+type PaymentLink struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID         uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_payment_links_user_value"`
+	Kind           string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_payment_links_user_value;index:idx_payment_links_value"`
+	Value          string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_payment_links_user_value;index:idx_payment_links_value"`
+	VerifiedByIDMP string    `gorm:"column:verified_by_id_mp;type:varchar(50);not null"`
+	CreatedAt      time.Time
+}
+
+func (PaymentLink) TableName() string {
+	return "proof_payment_links"
+}
+
+func cardLinkValue(brand, last4 string) string {
+	return brand + ":" + last4
+}
+
+const (
+	SuggestionPending   = "PENDING"
+	SuggestionClaimed   = "CLAIMED"
+	SuggestionDismissed = "DISMISSED"
+)
+
+// Suggestion es un pago que coincide con los vínculos de más de un usuario.
+// No se atribuye solo: cada candidato lo ve en /proofs/me/suggested y puede
+// reclamarlo.
+type Suggestion struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_proof_suggestions_user_payment"`
+	IDMP       string    `gorm:"column:id_mp;type:varchar(50);not null;index;uniqueIndex:idx_proof_suggestions_user_payment"`
+	Amount     float64   `gorm:"not null"`
+	ApprovedAt time.Time `gorm:"not null"`
+	MatchedBy  string    `gorm:"type:varchar(10);not null"`
+	Status     string    `gorm:"type:varchar(20);not null;default:PENDING;index"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (Suggestion) TableName() string {
+	return "proof_suggestions"
+}
@@ -0,0 +1,55 @@
+package proof
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestToLinkResponse(t *testing.T) {
+	t.Parallel()
+
+	card := toLinkResponse(&PaymentLink{Kind: LinkKindCard, Value: cardLinkValue("visa", "1234")})
+	if card.CardBrand != "visa" || card.Last4 != "1234" || card.DNI != "" {
+		t.Fatalf("tarjeta mal mapeada: %+v", card)
+	}
+
+	dni := toLinkResponse(&PaymentLink{Kind: LinkKindDNI, Value: "30111222"})
+	if dni.DNI != "30111222" || dni.CardBrand != "" {
+		t.Fatalf("dni mal mapeado: %+v", dni)
+	}
+}
+
+func TestAttributionTarget(t *testing.T) {
+	t.Parallel()
+
+	ana, beto := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		matches map[uuid.UUID]string
+		want    uuid.UUID
+		wantOK  bool
+	}{
+		{"solo tarjeta, un usuario", map[uuid.UUID]string{ana: LinkKindCard}, uuid.Nil, false},
+		{"solo tarjeta, varios usuarios", map[uuid.UUID]string{ana: LinkKindCard, beto: LinkKindCard}, uuid.Nil, false},
+		{"dni de un usuario", map[uuid.UUID]string{ana: LinkKindDNI}, ana, true},
+		{"dni gana a la tarjeta de otro", map[uuid.UUID]string{ana: LinkKindDNI, beto: LinkKindCard}, ana, true},
+		{"dni de varios usuarios", map[uuid.UUID]string{ana: LinkKindDNI, beto: LinkKindDNI}, uuid.Nil, false},
+		{"sin coincidencias", map[uuid.UUID]string{}, uuid.Nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := attributionTarget(tt.matches)
+			if ok != tt.wantOK {
+				t.Fatalf("attributionTarget() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got != tt.want {
+				t.Errorf("attributionTarget() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
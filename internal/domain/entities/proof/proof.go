@@ -1,6 +1,8 @@
 package proof
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/sebaactis/powermix-back-mobile/internal/utils"
 )
@@ -20,4 +22,37 @@ type Proof struct {
 	Last4Card       *string
 	ExternalID      *string
 	ProductName     *string
+	// Stamps es la cantidad de sellos que sumó este comprobante.
+	Stamps int `gorm:"not null;default:1"`
+
+	// Revisión antifraude: los comprobantes PENDING_REVIEW no suman sellos
+	// hasta que un admin los aprueba.
+	ReviewStatus string  `gorm:"type:varchar(20);not null;default:APPROVED;index"`
+	RiskScore    float64 `gorm:"not null;default:0"`
+	RiskSignals  *string `gorm:"type:varchar(255)"`
+	ReviewedAt   *time.Time
+	ReviewedBy   *uuid.UUID `gorm:"type:uuid"`
+
+	// Datos de la anulación cuando Mercado Pago informa devolución o
+	// contracargo del pago.
+	ReversedAt      *time.Time
+	ReversalOutcome *string `gorm:"type:varchar(30)"`
 }
+
+// stampsPerVoucher es la cantidad de sellos que canjean un voucher.
+const stampsPerVoucher = 5
+
+const (
+	ReviewApproved = "APPROVED"
+	ReviewPending  = "PENDING_REVIEW"
+	ReviewRejected = "REJECTED"
+)
+
+// Qué se le descontó al usuario al anular el comprobante.
+const (
+	ReversalStampDebited   = "STAMP_DEBITED"
+	ReversalVoucherRevoked = "VOUCHER_REVOKED"
+	ReversalVoucherFlagged = "VOUCHER_FLAGGED"
+	// ReversalNoStamps: el comprobante no había sumado sellos (estaba en revisión).
+	ReversalNoStamps = "NO_STAMPS"
+)
@@ -0,0 +1,176 @@
+package proof
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/ocr"
+)
+
+// receiptMinConfidence es la confianza mínima para usar un campo sin pedir
+// confirmación al usuario.
+const receiptMinConfidence = 0.75
+
+// Un valor encontrado junto a su etiqueta ("Total", "DNI", ...) es más
+// confiable que uno deducido solo por su forma.
+const (
+	labeledWeight   = 1.0
+	unlabeledWeight = 0.6
+)
+
+const (
+	ReceiptFieldOperationID = "operation_id"
+	ReceiptFieldAmount      = "amount"
+	ReceiptFieldDate        = "date"
+	ReceiptFieldTime        = "time"
+	ReceiptFieldLast4       = "last4"
+	ReceiptFieldDNI         = "dni"
+)
+
+// ReceiptField es un dato extraído del comprobante.
+type ReceiptField struct {
+	Value      string  `json:"value"`
+	Confidence float64 `json:"confidence"`
+}
+
+func (f *ReceiptField) confident() bool {
+	return f != nil && f.Confidence >= receiptMinConfidence
+}
+
+// ReceiptData son los datos que se pudieron leer del comprobante.
+type ReceiptData struct {
+	OperationID *ReceiptField `json:"operation_id,omitempty"`
+	Amount      *ReceiptField `json:"amount,omitempty"`
+	Date        *ReceiptField `json:"date,omitempty"`
+	Time        *ReceiptField `json:"time,omitempty"`
+	Last4       *ReceiptField `json:"last4,omitempty"`
+	DNI         *ReceiptField `json:"dni,omitempty"`
+}
+
+var (
+	reOperationLabeled = regexp.MustCompile(`(?i)operaci[oó]n\D{0,30}(\d{9,14})\b`)
+	reOperationBare    = regexp.MustCompile(`\b(\d{10,14})\b`)
+	reAmountLabeled    = regexp.MustCompile(`(?i)(?:total|monto|importe|pagaste|pago de)\D{0,6}\$?\s*(\d{1,3}(?:\.\d{3})+(?:,\d{1,2})?|\d+(?:,\d{1,2})?)\b`)
+	reAmountBare       = regexp.MustCompile(`\$\s*(\d{1,3}(?:\.\d{3})+(?:,\d{1,2})?|\d+(?:,\d{1,2})?)\b`)
+	reDateNumeric      = regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})/(\d{4})\b`)
+	reDateText         = regexp.MustCompile(`(?i)\b(\d{1,2})\s+(?:de\s+)?([a-zé]{3,10})\.?\s+(?:de\s+)?(\d{4})\b`)
+	reTime             = regexp.MustCompile(`(?i)\b([01]?\d|2[0-3]):([0-5]\d)\b|\b([01]?\d|2[0-3])\.([0-5]\d)\s*hs\b`)
+	reLast4            = regexp.MustCompile(`(?i)(?:\*{2,}|•{2,}|terminada en|termina en|final)\s*(\d{4})\b`)
+	reDNI              = regexp.MustCompile(`(?i)\b(?:dni|documento)\D{0,5}(\d{1,2}\.?\d{3}\.?\d{3})\b`)
+)
+
+var spanishMonths = map[string]int{
+	"ene": 1, "feb": 2, "mar": 3, "abr": 4, "may": 5, "jun": 6,
+	"jul": 7, "ago": 8, "sep": 9, "set": 9, "oct": 10, "nov": 11, "dic": 12,
+}
+
+// ParseReceipt busca en el texto reconocido los datos que necesitan Create y
+// CreateFromOthers. Cada campo lleva la confianza de su línea ponderada por
+// cómo se encontró.
+func ParseReceipt(res *ocr.Result) ReceiptData {
+	var d ReceiptData
+
+	for _, line := range res.Lines {
+		text := line.Text
+
+		if m := reOperationLabeled.FindStringSubmatch(text); m != nil {
+			d.OperationID = better(d.OperationID, m[1], line.Confidence*labeledWeight)
+		} else if m := reOperationBare.FindStringSubmatch(text); m != nil {
+			d.OperationID = better(d.OperationID, m[1], line.Confidence*unlabeledWeight)
+		}
+
+		if m := reAmountLabeled.FindStringSubmatch(text); m != nil {
+			d.Amount = better(d.Amount, normalizeAmount(m[1]), line.Confidence*labeledWeight)
+		} else if m := reAmountBare.FindStringSubmatch(text); m != nil {
+			d.Amount = better(d.Amount, normalizeAmount(m[1]), line.Confidence*unlabeledWeight)
+		}
+
+		if m := reDateNumeric.FindStringSubmatch(text); m != nil {
+			if v, ok := formatDate(m[1], atoiOrZero(m[2]), m[3]); ok {
+				d.Date = better(d.Date, v, line.Confidence*labeledWeight)
+			}
+		} else if m := reDateText.FindStringSubmatch(text); m != nil {
+			if month, ok := spanishMonths[monthPrefix(m[2])]; ok {
+				if v, ok := formatDate(m[1], month, m[3]); ok {
+					d.Date = better(d.Date, v, line.Confidence*labeledWeight)
+				}
+			}
+		}
+
+		if m := reTime.FindStringSubmatch(text); m != nil {
+			h, min := m[1], m[2]
+			if h == "" {
+				h, min = m[3], m[4]
+			}
+			d.Time = better(d.Time, fmt.Sprintf("%02d:%s", atoiOrZero(h), min), line.Confidence*labeledWeight)
+		}
+
+		if m := reLast4.FindStringSubmatch(text); m != nil {
+			d.Last4 = better(d.Last4, m[1], line.Confidence*labeledWeight)
+		}
+
+		if m := reDNI.FindStringSubmatch(text); m != nil {
+			d.DNI = better(d.DNI, strings.ReplaceAll(m[1], ".", ""), line.Confidence*labeledWeight)
+		}
+	}
+
+	return d
+}
+
+// better se queda con el candidato de mayor confianza.
+func better(cur *ReceiptField, value string, confidence float64) *ReceiptField {
+	if cur != nil && cur.Confidence >= confidence {
+		return cur
+	}
+	return &ReceiptField{Value: value, Confidence: confidence}
+}
+
+// normalizeAmount pasa "1.500,50" (formato argentino) a "1500.50".
+func normalizeAmount(s string) string {
+	s = strings.ReplaceAll(s, ".", "")
+	return strings.ReplaceAll(s, ",", ".")
+}
+
+func monthPrefix(s string) string {
+	s = strings.ToLower(s)
+	if len(s) > 3 {
+		s = s[:3]
+	}
+	return s
+}
+
+// formatDate devuelve la fecha como la espera ProofOthersRequest ("18/11/2025").
+func formatDate(day string, month int, year string) (string, bool) {
+	dd := atoiOrZero(day)
+	if dd < 1 || dd > 31 || month < 1 || month > 12 {
+		return "", false
+	}
+	return fmt.Sprintf("%02d/%02d/%s", dd, month, year), true
+}
+
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// lowConfidence lista los campos pedidos que faltan o no alcanzan la confianza mínima.
+func (d ReceiptData) lowConfidence(names ...string) []string {
+	fields := map[string]*ReceiptField{
+		ReceiptFieldOperationID: d.OperationID,
+		ReceiptFieldAmount:      d.Amount,
+		ReceiptFieldDate:        d.Date,
+		ReceiptFieldTime:        d.Time,
+		ReceiptFieldLast4:       d.Last4,
+		ReceiptFieldDNI:         d.DNI,
+	}
+
+	var out []string
+	for _, name := range names {
+		if !fields[name].confident() {
+			out = append(out, name)
+		}
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package proof
+
+import (
+	"testing"
+
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/ocr"
+)
+
+func lines(conf float64, texts ...string) *ocr.Result {
+	res := &ocr.Result{}
+	for _, t := range texts {
+		res.Lines = append(res.Lines, ocr.Line{Text: t, Confidence: conf})
+	}
+	return res
+}
+
+func TestParseReceipt_MercadoPagoReceipt(t *testing.T) {
+	t.Parallel()
+
+	d := ParseReceipt(lines(0.92,
+		"Comprobante de pago",
+		"Martes, 18 de noviembre de 2025, 12:11 hs",
+		"Pagaste $ 1.500,50",
+		"Visa Débito **** 4321",
+		"DNI 30.123.456",
+		"Número de operación de Mercado Pago 132456789012",
+	))
+
+	checks := []struct {
+		name  string
+		field *ReceiptField
+		want  string
+	}{
+		{"operation", d.OperationID, "132456789012"},
+		{"amount", d.Amount, "1500.50"},
+		{"date", d.Date, "18/11/2025"},
+		{"time", d.Time, "12:11"},
+		{"last4", d.Last4, "4321"},
+		{"dni", d.DNI, "30123456"},
+	}
+	for _, c := range checks {
+		if c.field == nil || c.field.Value != c.want {
+			t.Errorf("%s = %+v, want %q", c.name, c.field, c.want)
+		}
+	}
+
+	if low := d.lowConfidence(ReceiptFieldOperationID, ReceiptFieldAmount); len(low) != 0 {
+		t.Errorf("lowConfidence = %v, want none", low)
+	}
+}
+
+func TestParseReceipt_LowConfidence(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		res  *ocr.Result
+		want []string
+	}{
+		{
+			name: "amount without label",
+			res:  lines(0.95, "18/11/2025 09:05", "$ 2.300"),
+			want: []string{ReceiptFieldAmount},
+		},
+		{
+			name: "blurry line",
+			res:  lines(0.5, "Total $ 2.300", "18/11/2025 09:05"),
+			want: []string{ReceiptFieldDate, ReceiptFieldTime, ReceiptFieldAmount},
+		},
+		{
+			name: "missing time",
+			res:  lines(0.95, "Total $ 2.300", "18/11/2025"),
+			want: []string{ReceiptFieldTime},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := ParseReceipt(tt.res).lowConfidence(ReceiptFieldDate, ReceiptFieldTime, ReceiptFieldAmount)
+			if len(got) != len(tt.want) {
+				t.Fatalf("lowConfidence = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("lowConfidence = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
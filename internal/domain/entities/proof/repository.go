@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository struct {
@@ -130,6 +132,242 @@ func (r *Repository) GetByID(ctx context.Context, id string) (*Proof, error) {
 	return &proof, nil
 }
 
+// GetByIDForUpdate busca el comprobante por id de Mercado Pago y bloquea la
+// fila hasta el fin de la transacción. Devuelve nil si no existe.
+func (r *Repository) GetByIDForUpdate(ctx context.Context, id string) (*Proof, error) {
+	var proof Proof
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&proof, "id_mp = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapProofRepoErr(ctx, "get by id for update", err)
+	}
+
+	return &proof, nil
+}
+
+func (r *Repository) MarkReversed(ctx context.Context, id uuid.UUID, status, outcome string, at time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&Proof{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"status_mp":        status,
+			"reversed_at":      &at,
+			"reversal_outcome": outcome,
+		}).Error
+	if err != nil {
+		return mapProofRepoErr(ctx, "mark reversed", err)
+	}
+	return nil
+}
+
+// CountOtherUsersByDNI cuenta las otras cuentas que cargaron pagos con ese DNI.
+func (r *Repository) CountOtherUsersByDNI(ctx context.Context, dni string, userID uuid.UUID) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).
+		Model(&Proof{}).
+		Where("dni = ? AND user_id <> ?", dni, userID).
+		Distinct("user_id").
+		Count(&n).Error
+	if err != nil {
+		return 0, mapProofRepoErr(ctx, "count other users by dni", err)
+	}
+	return n, nil
+}
+
+// CountOtherUsersByCard cuenta las otras cuentas que cargaron pagos con la
+// misma tarjeta (medio de pago y últimos 4 dígitos).
+func (r *Repository) CountOtherUsersByCard(ctx context.Context, cardID, last4 string, userID uuid.UUID) (int64, error) {
+	var n int64
+	q := r.db.WithContext(ctx).
+		Model(&Proof{}).
+		Where("last4_card = ? AND user_id <> ?", last4, userID)
+	if cardID != "" {
+		q = q.Where("card_id = ?", cardID)
+	}
+	if err := q.Distinct("user_id").Count(&n).Error; err != nil {
+		return 0, mapProofRepoErr(ctx, "count other users by card", err)
+	}
+	return n, nil
+}
+
+func (r *Repository) CountByUserSince(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).
+		Model(&Proof{}).
+		Where("user_id = ? AND proof_date >= ?", userID, since).
+		Count(&n).Error
+	if err != nil {
+		return 0, mapProofRepoErr(ctx, "count by user since", err)
+	}
+	return n, nil
+}
+
+// ListPendingReview devuelve la cola de revisión, los más viejos primero.
+func (r *Repository) ListPendingReview(ctx context.Context, page, pageSize int) ([]*Proof, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+
+	base := r.db.WithContext(ctx).
+		Model(&Proof{}).
+		Where("review_status = ? AND reversed_at IS NULL", ReviewPending)
+
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, mapProofRepoErr(ctx, "count pending review", err)
+	}
+
+	var proofs []*Proof
+	if err := base.
+		Order("proof_date ASC").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Find(&proofs).Error; err != nil {
+		return nil, 0, mapProofRepoErr(ctx, "list pending review", err)
+	}
+
+	return proofs, total, nil
+}
+
+// GetByUUIDForUpdate busca por id interno y bloquea la fila. Devuelve nil si
+// no existe.
+func (r *Repository) GetByUUIDForUpdate(ctx context.Context, id uuid.UUID) (*Proof, error) {
+	var proof Proof
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&proof, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapProofRepoErr(ctx, "get by uuid for update", err)
+	}
+
+	return &proof, nil
+}
+
+func (r *Repository) SetReview(ctx context.Context, id uuid.UUID, status string, reviewer uuid.UUID, at time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&Proof{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"review_status": status,
+			"reviewed_at":   &at,
+			"reviewed_by":   reviewer,
+		}).Error
+	if err != nil {
+		return mapProofRepoErr(ctx, "set review", err)
+	}
+	return nil
+}
+
+func (r *Repository) CreateLink(ctx context.Context, link *PaymentLink) error {
+	err := r.db.WithContext(ctx).Create(link).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return fmt.Errorf("proof: create link: %w", ErrLinkExists)
+	}
+	if err != nil {
+		return mapProofRepoErr(ctx, "create link", err)
+	}
+	return nil
+}
+
+func (r *Repository) ListLinks(ctx context.Context, userID uuid.UUID) ([]PaymentLink, error) {
+	var links []PaymentLink
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&links).Error
+	if err != nil {
+		return nil, mapProofRepoErr(ctx, "list links", err)
+	}
+	return links, nil
+}
+
+func (r *Repository) DeleteLink(ctx context.Context, userID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&PaymentLink{})
+	if result.Error != nil {
+		return mapProofRepoErr(ctx, "delete link", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("proof: delete link: %w", ErrLinkNotFound)
+	}
+	return nil
+}
+
+// FindLinkedUsers devuelve los usuarios que vincularon ese DNI o tarjeta.
+func (r *Repository) FindLinkedUsers(ctx context.Context, kind, value string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&PaymentLink{}).
+		Where("kind = ? AND value = ?", kind, value).
+		Distinct().
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		return nil, mapProofRepoErr(ctx, "find linked users", err)
+	}
+	return ids, nil
+}
+
+// SaveSuggestion guarda la sugerencia si el usuario todavía no la tenía.
+func (r *Repository) SaveSuggestion(ctx context.Context, sg *Suggestion) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(sg).Error
+	if err != nil {
+		return mapProofRepoErr(ctx, "save suggestion", err)
+	}
+	return nil
+}
+
+// ListPendingSuggestions omite los pagos que ya tienen comprobante.
+func (r *Repository) ListPendingSuggestions(ctx context.Context, userID uuid.UUID) ([]Suggestion, error) {
+	var out []Suggestion
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, SuggestionPending).
+		Where("NOT EXISTS (SELECT 1 FROM proofs p WHERE p.id_mp = proof_suggestions.id_mp)").
+		Order("approved_at DESC").
+		Find(&out).Error
+	if err != nil {
+		return nil, mapProofRepoErr(ctx, "list pending suggestions", err)
+	}
+	return out, nil
+}
+
+func (r *Repository) GetSuggestion(ctx context.Context, userID uuid.UUID, idMP string) (*Suggestion, error) {
+	var sg Suggestion
+	err := r.db.WithContext(ctx).First(&sg, "user_id = ? AND id_mp = ?", userID, idMP).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapProofRepoErr(ctx, "get suggestion", err)
+	}
+	return &sg, nil
+}
+
+func (r *Repository) SetSuggestionStatus(ctx context.Context, id uuid.UUID, status string) error {
+	err := r.db.WithContext(ctx).
+		Model(&Suggestion{}).
+		Where("id = ?", id).
+		Update("status", status).Error
+	if err != nil {
+		return mapProofRepoErr(ctx, "set suggestion status", err)
+	}
+	return nil
+}
+
 func mapProofRepoErr(ctx context.Context, action string, err error) error {
 	if err == nil {
 		return nil
@@ -0,0 +1,133 @@
+package proof
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/mailer"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/user"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/voucher"
+	"gorm.io/gorm"
+)
+
+// ReversePayment anula el comprobante del pago idMP cuando Mercado Pago lo
+// informa devuelto, contracargado o cancelado. Descuenta los sellos que sumó;
+// si ya se canjearon por un voucher sin usar lo anula, y si el voucher ya se
+// usó lo marca para revisión. Es idempotente: devuelve false si no hay
+// comprobante para ese pago o si ya estaba anulado.
+func (s *Service) ReversePayment(ctx context.Context, idMP, status string) (bool, error) {
+	var reversed *Proof
+	var outcome string
+	now := time.Now()
+
+	err := s.repo.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txProofRepo := s.repo.WithTx(tx)
+		txUserService := s.userService.WithTx(tx)
+		txVoucherService := s.voucherService.WithTx(tx)
+
+		p, err := txProofRepo.GetByIDForUpdate(ctx, idMP)
+		if err != nil {
+			return err
+		}
+		if p == nil || p.ReversedAt != nil {
+			return nil
+		}
+
+		// Un comprobante en revisión o rechazado nunca sumó sellos.
+		outcome = ReversalNoStamps
+		if p.ReviewStatus == ReviewApproved {
+			outcome, err = debitProofStamps(ctx, txUserService, txVoucherService, p, "pago "+idMP+" "+status)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := txProofRepo.MarkReversed(ctx, p.ID, status, outcome, now); err != nil {
+			return err
+		}
+		reversed = p
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if reversed == nil {
+		return false, nil
+	}
+
+	slog.InfoContext(ctx, "comprobante anulado",
+		"id_mp", idMP, "user_id", reversed.UserID, "status", status, "outcome", outcome)
+
+	s.notifyReversal(ctx, reversed, status, outcome, now)
+	return true, nil
+}
+
+// notifyReversal avisa al usuario por mail. Un fallo no deshace la anulación.
+func (s *Service) notifyReversal(ctx context.Context, p *Proof, status, outcome string, at time.Time) {
+	if s.mailer == nil {
+		return
+	}
+
+	u, err := s.userService.GetByID(ctx, p.UserID)
+	if err != nil {
+		slog.WarnContext(ctx, "no se pudo avisar la anulación", "id_mp", p.IDMP, "error", err)
+		return
+	}
+
+	err = s.mailer.SendProofReversedEmail(ctx, u.Email, mailer.ProofReversal{
+		PaymentID: p.IDMP,
+		Reason:    status,
+		Outcome:   outcome,
+		At:        at,
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "no se pudo avisar la anulación", "id_mp", p.IDMP, "error", err)
+	}
+}
+
+// debitProofStamps descuenta los sellos del comprobante. Lo que falte se cubre
+// anulando vouchers activos (cada uno devuelve una tarjeta completa, cuyo
+// sobrante vuelve al contador); si no quedan, marca el último usado.
+func debitProofStamps(ctx context.Context, users *user.Service, vouchers *voucher.Service, p *Proof, reason string) (string, error) {
+	stamps := max(p.Stamps, 1)
+
+	debited, err := users.DebitStamps(ctx, p.UserID, stamps)
+	if err != nil {
+		return "", err
+	}
+
+	owed := stamps - debited
+	if owed == 0 {
+		return ReversalStampDebited, nil
+	}
+
+	// Los vouchers se asignan después del comprobante, así que buscamos desde
+	// su fecha de carga.
+	outcome := ReversalVoucherRevoked
+	refund := 0
+	for owed > 0 {
+		v, err := vouchers.RevokeLatestActive(ctx, p.UserID, p.ProofDate.Time)
+		if err != nil {
+			return "", err
+		}
+		if v == nil {
+			if _, err := vouchers.FlagLatestUsed(ctx, p.UserID, p.ProofDate.Time, reason); err != nil {
+				return "", err
+			}
+			outcome = ReversalVoucherFlagged
+			break
+		}
+
+		take := min(owed, stampsPerVoucher)
+		owed -= take
+		refund += stampsPerVoucher - take
+	}
+
+	if refund > 0 {
+		if _, err := users.AddStamps(ctx, p.UserID, refund); err != nil {
+			return "", err
+		}
+	}
+	return outcome, nil
+}
@@ -0,0 +1,115 @@
+package proof
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sebaactis/powermix-back-mobile/internal/validations"
+	"gorm.io/gorm"
+)
+
+func (s *Service) ListPendingReview(ctx context.Context, page, pageSize int) (*PaginatedReviewResponse, error) {
+	proofs, total, err := s.repo.ListPendingReview(ctx, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*ReviewItem, len(proofs))
+	for i, p := range proofs {
+		var signals []string
+		if p.RiskSignals != nil && *p.RiskSignals != "" {
+			signals = strings.Split(*p.RiskSignals, ",")
+		}
+		items[i] = &ReviewItem{
+			ID:          p.ID,
+			UserID:      p.UserID,
+			IDMP:        p.IDMP,
+			AmountMP:    p.AmountMP,
+			ProofDate:   p.ProofDate,
+			Dni:         p.Dni,
+			CardType:    p.CardType,
+			Last4Card:   p.Last4Card,
+			Stamps:      p.Stamps,
+			RiskScore:   p.RiskScore,
+			RiskSignals: signals,
+		}
+	}
+
+	return &PaginatedReviewResponse{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+		HasMore:  int64(page*pageSize) < total,
+	}, nil
+}
+
+// Review aprueba o rechaza un comprobante en revisión. Al aprobarlo se suman
+// los sellos que había retenido.
+func (s *Service) Review(ctx context.Context, proofID, reviewerID uuid.UUID, req *ReviewRequest) (*ProofResponse, error) {
+	if fields, ok := s.validator.ValidateStruct(req); !ok {
+		return nil, &validations.ValidationError{Fields: fields}
+	}
+
+	status := ReviewRejected
+	if req.Decision == "approve" {
+		status = ReviewApproved
+	}
+
+	var reviewed *Proof
+	err := s.repo.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txProofRepo := s.repo.WithTx(tx)
+
+		p, err := txProofRepo.GetByUUIDForUpdate(ctx, proofID)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return fmt.Errorf("proof: review: %w (id=%s)", ErrProofNotFoundID, proofID)
+		}
+		if p.ReviewStatus != ReviewPending || p.ReversedAt != nil {
+			return fmt.Errorf("proof: review: %w (id=%s status=%s)", ErrReviewNotPending, proofID, p.ReviewStatus)
+		}
+
+		if status == ReviewApproved {
+			_, err := creditStamps(ctx, s.userService.WithTx(tx), s.voucherService.WithTx(tx), p.UserID, p.Stamps)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := txProofRepo.SetReview(ctx, p.ID, status, reviewerID, time.Now()); err != nil {
+			return err
+		}
+		p.ReviewStatus = status
+		reviewed = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "comprobante revisado",
+		"id_mp", reviewed.IDMP, "user_id", reviewed.UserID, "reviewer_id", reviewerID, "status", status)
+
+	return &ProofResponse{
+		UserID:          reviewed.UserID,
+		IDMP:            reviewed.IDMP,
+		ProofDate:       reviewed.ProofDate,
+		StatusMP:        reviewed.StatusMP,
+		DateApprovedMP:  reviewed.DateApprovedMP,
+		OperationTypeMP: reviewed.OperationTypeMP,
+		AmountMP:        reviewed.AmountMP,
+		Dni:             reviewed.Dni,
+		CardType:        reviewed.CardType,
+		Last4Card:       reviewed.Last4Card,
+		ExternalID:      reviewed.ExternalID,
+		ProductName:     reviewed.ProductName,
+		Stamps:          reviewed.Stamps,
+		ReviewStatus:    reviewed.ReviewStatus,
+	}, nil
+}
@@ -0,0 +1,171 @@
+package proof
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/mercadopago"
+)
+
+// selectionTTL es cuánto tiempo tiene el usuario para elegir un candidato.
+const selectionTTL = 10 * time.Minute
+
+var argentinaTZ = func() *time.Location {
+	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	if err != nil {
+		return time.FixedZone("ART", -3*60*60)
+	}
+	return loc
+}()
+
+// PaymentCandidate es un pago posible mostrado al usuario. No expone datos
+// del pagador ni el id del pago: Ref solo tiene sentido junto con el
+// SelectionToken que lo acompaña.
+type PaymentCandidate struct {
+	Ref       string  `json:"ref"`
+	Amount    string  `json:"amount"`
+	Time      string  `json:"time"`
+	CardBrand string  `json:"card_brand,omitempty"`
+	Last4     string  `json:"last4,omitempty"`
+	Score     float64 `json:"score"`
+}
+
+// AmbiguousPaymentError indica que varios pagos coinciden con el comprobante.
+// El usuario elige uno enviando SelectionToken y el ref del candidato a
+// /proof/others/select.
+type AmbiguousPaymentError struct {
+	Candidates     []PaymentCandidate `json:"candidates"`
+	SelectionToken string             `json:"selection_token"`
+	ExpiresAt      time.Time          `json:"expires_at"`
+}
+
+func (e *AmbiguousPaymentError) Error() string {
+	return fmt.Sprintf("proof: %d pagos coinciden con el comprobante", len(e.Candidates))
+}
+
+func (s *Service) newAmbiguousPaymentError(userID uuid.UUID, candidates []mercadopago.Candidate, now time.Time) (*AmbiguousPaymentError, error) {
+	out := &AmbiguousPaymentError{ExpiresAt: now.Add(selectionTTL)}
+	ids := make([]string, 0, len(candidates))
+
+	for i, c := range candidates {
+		p := c.Payment
+		ids = append(ids, strconv.FormatInt(p.PaymentID, 10))
+
+		pc := PaymentCandidate{
+			Ref:    candidateRef(i),
+			Amount: maskAmount(p.TotalPaidAmount),
+			Time:   p.DateApproved.In(argentinaTZ).Format("15:04"),
+			Score:  c.Score,
+		}
+		if p.CardId != nil {
+			pc.CardBrand = *p.CardId
+		}
+		if p.CardLast4 != nil {
+			pc.Last4 = *p.CardLast4
+		}
+		out.Candidates = append(out.Candidates, pc)
+	}
+
+	token, err := sealSelection(s.selectionKey, userID, ids, out.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("proof: selection token: %w", err)
+	}
+	out.SelectionToken = token
+	return out, nil
+}
+
+// candidateRef es la referencia del i-ésimo candidato: "c1", "c2", ...
+func candidateRef(i int) string {
+	return "c" + strconv.Itoa(i+1)
+}
+
+// maskAmount oculta los últimos dígitos del monto: "$15**".
+func maskAmount(v float64) string {
+	digits := strconv.FormatFloat(math.Trunc(v), 'f', 0, 64)
+	keep := (len(digits) + 1) / 2
+	return "$" + digits[:keep] + strings.Repeat("*", len(digits)-keep)
+}
+
+// sealSelection cifra los pagos ofrecidos a un usuario, así la elección no
+// depende de guardar estado entre los dos pedidos y los ids de pago (que
+// pueden ser de otros clientes) nunca llegan a la app. El usuario va como
+// dato asociado: el token no sirve para otra cuenta.
+func sealSelection(key []byte, userID uuid.UUID, paymentIDs []string, exp time.Time) (string, error) {
+	gcm, err := selectionCipher(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	payload := strconv.FormatInt(exp.Unix(), 10) + "|" + strings.Join(paymentIDs, ",")
+	sealed := gcm.Seal(nonce, nonce, []byte(payload), userID[:])
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// openSelection comprueba que el token sea de este usuario y siga vigente, y
+// devuelve el id del pago que corresponde a ref.
+func openSelection(key []byte, token string, userID uuid.UUID, ref string, now time.Time) (string, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", ErrSelectionInvalid
+	}
+
+	gcm, err := selectionCipher(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", ErrSelectionInvalid
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	payload, err := gcm.Open(nil, nonce, ciphertext, userID[:])
+	if err != nil {
+		return "", ErrSelectionInvalid
+	}
+
+	expRaw, idsRaw, ok := strings.Cut(string(payload), "|")
+	if !ok {
+		return "", ErrSelectionInvalid
+	}
+	exp, err := strconv.ParseInt(expRaw, 10, 64)
+	if err != nil || !now.Before(time.Unix(exp, 0)) {
+		return "", ErrSelectionExpired
+	}
+
+	ids := strings.Split(idsRaw, ",")
+	for i, id := range ids {
+		if candidateRef(i) == ref {
+			return id, nil
+		}
+	}
+	return "", ErrSelectionInvalid
+}
+
+func selectionCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(selectionMAC(key, "proof-selection-seal"))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func selectionMAC(key []byte, payload string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(payload))
+	return m.Sum(nil)
+}
@@ -0,0 +1,75 @@
+package proof
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestSelectionToken(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("test-key")
+	userID := uuid.New()
+	now := time.Unix(1700000000, 0)
+	token, err := sealSelection(key, userID, []string{"111", "222"}, now.Add(selectionTTL))
+	if err != nil {
+		t.Fatalf("sealSelection() = %v", err)
+	}
+
+	tampered := []byte(token)
+	tampered[len(tampered)/2] ^= 1
+
+	raw, _ := base64.RawURLEncoding.DecodeString(token)
+	if strings.Contains(token, "111") || strings.Contains(string(raw), "222") {
+		t.Fatal("el token expone los ids de pago")
+	}
+
+	tests := []struct {
+		name          string
+		token         string
+		userID        uuid.UUID
+		ref           string
+		now           time.Time
+		wantPaymentID string
+		wantErr       error
+	}{
+		{"valid", token, userID, "c2", now, "222", nil},
+		{"other user", token, uuid.New(), "c2", now, "", ErrSelectionInvalid},
+		{"not offered", token, userID, "c3", now, "", ErrSelectionInvalid},
+		{"expired", token, userID, "c1", now.Add(selectionTTL), "", ErrSelectionExpired},
+		{"tampered", string(tampered), userID, "c1", now, "", ErrSelectionInvalid},
+		{"garbage", "nope", userID, "c1", now, "", ErrSelectionInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := openSelection(key, tt.token, tt.userID, tt.ref, tt.now)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("openSelection() = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("errors.Is(%v, %v) = false", err, tt.wantErr)
+			}
+			if got != tt.wantPaymentID {
+				t.Errorf("openSelection() = %q, want %q", got, tt.wantPaymentID)
+			}
+		})
+	}
+}
+
+func TestMaskAmount(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[float64]string{1500.5: "$15**", 250: "$25*", 9: "$9"} {
+		if got := maskAmount(in); got != want {
+			t.Errorf("maskAmount(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
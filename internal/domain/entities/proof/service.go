@@ -2,13 +2,17 @@ package proof
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sebaactis/powermix-back-mobile/internal/clients/coffeeji"
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/mailer"
 	"github.com/sebaactis/powermix-back-mobile/internal/clients/mercadopago"
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/ocr"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/user"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/voucher"
 	"github.com/sebaactis/powermix-back-mobile/internal/utils"
@@ -16,17 +20,32 @@ import (
 	"gorm.io/gorm"
 )
 
+// PaymentGateway consulta pagos de Mercado Pago. Lo implementan el cliente de
+// la API y el espejo local de pagos.
+type PaymentGateway interface {
+	ValidatePaymentExists(ctx context.Context, paymentID string) (*mercadopago.PaymentDTO, error)
+	ReconcileOthers(ctx context.Context, req mercadopago.ReconcileOthersRequest) (*mercadopago.ReconcileOthersResult, error)
+}
+
 type Service struct {
 	repo           *Repository
 	userService    *user.Service
 	voucherService *voucher.Service
 	validator      validations.StructValidator
-	mpClient       *mercadopago.Client
+	mpClient       PaymentGateway
 	coffejiClient  *coffeeji.Client
+	ocr            ocr.Engine
+	selectionKey   []byte
+	mailer         mailer.Mailer
+	rules          Rules
+	stamps         StampPolicy
+	fraud          FraudConfig
 }
 
-func NewService(repo *Repository, userService *user.Service, voucherService *voucher.Service, validator validations.StructValidator, mpClient *mercadopago.Client, coffejiClient *coffeeji.Client) *Service {
-	return &Service{repo: repo, userService: userService, voucherService: voucherService, validator: validator, mpClient: mpClient, coffejiClient: coffejiClient}
+func NewService(repo *Repository, userService *user.Service, voucherService *voucher.Service, validator validations.StructValidator, mpClient PaymentGateway, coffejiClient *coffeeji.Client, ocrEngine ocr.Engine, selectionSecret string, mailerClient mailer.Mailer, rules Rules, stamps StampPolicy, fraud FraudConfig) *Service {
+	// La clave de selección se deriva para no reutilizar el secreto tal cual.
+	selectionKey := selectionMAC([]byte(selectionSecret), "proof-selection")
+	return &Service{repo: repo, userService: userService, voucherService: voucherService, validator: validator, mpClient: mpClient, coffejiClient: coffejiClient, ocr: ocrEngine, selectionKey: selectionKey, mailer: mailerClient, rules: rules, stamps: stamps, fraud: fraud}
 }
 
 func (s *Service) Create(ctx context.Context, proof *ProofRequest) (*ProofResponse, error) {
@@ -53,8 +72,21 @@ func (s *Service) Create(ctx context.Context, proof *ProofRequest) (*ProofRespon
 		return nil, fmt.Errorf("proof: create: %w (id_mp=%s)", ErrProofNotFoundID, proof.IDMP)
 	}
 
-	goodsName, err := s.coffejiClient.GetGoodsNameByOrderNo(ctx, *payment.ExternalID)
+	items, err := s.coffejiClient.GetOrderItems(ctx, *payment.ExternalID)
 
+	if err != nil {
+		return nil, err
+	}
+	goodsName := items[0].Name
+
+	err = s.rules.Check(PaymentFacts{
+		PaymentID:   proof.IDMP,
+		Status:      payment.Status,
+		Amount:      payment.TotalPaidAmount,
+		ApprovedAt:  payment.DateApproved,
+		CollectorID: payment.CollectorID,
+		ProductName: goodsName,
+	}, time.Now())
 	if err != nil {
 		return nil, err
 	}
@@ -73,11 +105,15 @@ func (s *Service) Create(ctx context.Context, proof *ProofRequest) (*ProofRespon
 		Last4Card:       payment.CardLast4,
 		ExternalID:      payment.ExternalID,
 		ProductName:     &goodsName,
+		Stamps:          s.stamps.Stamps(items, payment.TotalPaidAmount),
+	}
+
+	if err := s.applyRisk(ctx, newProof, payment.PayerEmail); err != nil {
+		return nil, err
 	}
 
 	// Usamos una transacción para asegurar atomicidad
 	var proofResult *Proof
-	var quantityStamps int
 
 	err = s.repo.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Creamos repositories/services que usen esta transacción
@@ -92,27 +128,16 @@ func (s *Service) Create(ctx context.Context, proof *ProofRequest) (*ProofRespon
 			return createErr
 		}
 
-		// 2. Incrementamos el contador de stamps
-		quantityStamps, createErr = txUserService.IncrementStampsCounter(ctx, proofResult.UserID)
+		// 2. Sumamos los sellos; cada tarjeta completa se canjea por un voucher.
+		// Si quedó en revisión los sellos se suman recién al aprobarlo.
+		if proofResult.ReviewStatus != ReviewApproved {
+			return nil
+		}
+		_, createErr = creditStamps(ctx, txUserService, txVoucherService, proofResult.UserID, proofResult.Stamps)
 		if createErr != nil {
 			return createErr
 		}
 
-		// 3. Si stamps == 5, asignamos voucher y reseteamos el contador
-		if quantityStamps == 5 {
-			_, createErr = txVoucherService.AssignNextVoucher(ctx, &voucher.VoucherRequest{
-				UserID: proofResult.UserID,
-			})
-			if createErr != nil {
-				return createErr
-			}
-
-			_, createErr = txUserService.ResetStampsCounter(ctx, proofResult.UserID)
-			if createErr != nil {
-				return createErr
-			}
-		}
-
 		return nil
 	})
 
@@ -133,6 +158,8 @@ func (s *Service) Create(ctx context.Context, proof *ProofRequest) (*ProofRespon
 		Last4Card:       proofResult.Last4Card,
 		ExternalID:      proofResult.ExternalID,
 		ProductName:     proofResult.ProductName,
+		Stamps:          proofResult.Stamps,
+		ReviewStatus:    proofResult.ReviewStatus,
 	}, nil
 
 }
@@ -153,6 +180,14 @@ func (s *Service) CreateFromOthers(ctx context.Context, req *ProofOthersRequest)
 
 	result, err := s.mpClient.ReconcileOthers(ctx, mpReq)
 	if err != nil {
+		var ambiguous *mercadopago.AmbiguousMatchError
+		if errors.As(err, &ambiguous) {
+			ambiguousErr, err := s.newAmbiguousPaymentError(req.UserID, ambiguous.Candidates, time.Now())
+			if err != nil {
+				return nil, err
+			}
+			return nil, ambiguousErr
+		}
 		return nil, err
 	}
 	if result == nil {
@@ -169,8 +204,21 @@ func (s *Service) CreateFromOthers(ctx context.Context, req *ProofOthersRequest)
 		return nil, fmt.Errorf("proof: create from others: %w (id_mp=%s)", ErrProofDuplicateMP, idMP)
 	}
 
-	goodsName, err := s.coffejiClient.GetGoodsNameByOrderNo(ctx, *result.ExternalID)
+	items, err := s.coffejiClient.GetOrderItems(ctx, *result.ExternalID)
 
+	if err != nil {
+		return nil, err
+	}
+	goodsName := items[0].Name
+
+	err = s.rules.Check(PaymentFacts{
+		PaymentID:   idMP,
+		Status:      result.Status,
+		Amount:      result.TotalPaidAmount,
+		ApprovedAt:  result.DateApproved,
+		CollectorID: result.CollectorID,
+		ProductName: goodsName,
+	}, time.Now())
 	if err != nil {
 		return nil, err
 	}
@@ -189,6 +237,11 @@ func (s *Service) CreateFromOthers(ctx context.Context, req *ProofOthersRequest)
 		Last4Card:       result.CardLast4,
 		ExternalID:      result.ExternalID,
 		ProductName:     &goodsName,
+		Stamps:          s.stamps.Stamps(items, result.TotalPaidAmount),
+	}
+
+	if err := s.applyRisk(ctx, newProof, result.PayerEmail); err != nil {
+		return nil, err
 	}
 
 	// Usamos una transacción para asegurar atomicidad
@@ -208,30 +261,19 @@ func (s *Service) CreateFromOthers(ctx context.Context, req *ProofOthersRequest)
 			return createErr
 		}
 
-		// 2. Incrementamos el contador de stamps
-		quantityStamps, createErr = txUserService.IncrementStampsCounter(ctx, proofResult.UserID)
+		// 2. Sumamos los sellos; cada tarjeta completa se canjea por un voucher.
+		// Si quedó en revisión los sellos se suman recién al aprobarlo.
+		if proofResult.ReviewStatus != ReviewApproved {
+			return nil
+		}
+		quantityStamps, createErr = creditStamps(ctx, txUserService, txVoucherService, proofResult.UserID, proofResult.Stamps)
 		if createErr != nil {
 			return createErr
 		}
 
-		log.Printf("proofResult (others): userID=%s idMP=%s amount=%.2f", proofResult.UserID, proofResult.IDMP, proofResult.AmountMP)
+		log.Printf("proofResult (others): userID=%s idMP=%s amount=%.2f stamps=%d", proofResult.UserID, proofResult.IDMP, proofResult.AmountMP, proofResult.Stamps)
 		log.Printf("quantityStamps (others): %d", quantityStamps)
 
-		// 3. Si stamps == 5, asignamos voucher y reseteamos el contador
-		if quantityStamps == 5 {
-			_, createErr = txVoucherService.AssignNextVoucher(ctx, &voucher.VoucherRequest{
-				UserID: proofResult.UserID,
-			})
-			if createErr != nil {
-				return createErr
-			}
-
-			_, createErr = txUserService.ResetStampsCounter(ctx, proofResult.UserID)
-			if createErr != nil {
-				return createErr
-			}
-		}
-
 		return nil
 	})
 
@@ -252,9 +294,94 @@ func (s *Service) CreateFromOthers(ctx context.Context, req *ProofOthersRequest)
 		Last4Card:       proofResult.Last4Card,
 		ExternalID:      proofResult.ExternalID,
 		ProductName:     proofResult.ProductName,
+		Stamps:          proofResult.Stamps,
+		ReviewStatus:    proofResult.ReviewStatus,
 	}, nil
 }
 
+// SelectPayment carga el comprobante con el pago que el usuario eligió entre
+// los candidatos de una conciliación ambigua.
+func (s *Service) SelectPayment(ctx context.Context, req *SelectPaymentRequest) (*ProofResponse, error) {
+	if fields, ok := s.validator.ValidateStruct(req); !ok {
+		return nil, &validations.ValidationError{Fields: fields}
+	}
+
+	paymentID, err := openSelection(s.selectionKey, req.SelectionToken, req.UserID, req.Ref, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Create(ctx, &ProofRequest{UserID: req.UserID, IDMP: paymentID})
+}
+
+// CreateFromReceipt lee la foto de un comprobante y, si los datos son
+// confiables, lo carga por número de operación o por fecha/hora/monto.
+func (s *Service) CreateFromReceipt(ctx context.Context, userID uuid.UUID, image []byte, contentType string) (*ReceiptResponse, error) {
+	if s.ocr == nil {
+		return nil, ErrOCRUnavailable
+	}
+
+	res, err := s.ocr.Recognize(ctx, image, contentType)
+	if err != nil {
+		switch {
+		case errors.Is(err, ocr.ErrUnsupportedImage):
+			return nil, ErrReceiptUnsupported
+		case errors.Is(err, ocr.ErrNoText):
+			return nil, ErrReceiptUnreadable
+		}
+		return nil, fmt.Errorf("proof: create from receipt: %w", err)
+	}
+
+	data := ParseReceipt(res)
+	needsConfirmation := func(fields []string) *ReceiptResponse {
+		return &ReceiptResponse{Status: ReceiptStatusNeedsConfirmation, Extracted: data, LowConfidence: fields}
+	}
+
+	// El número de operación identifica el pago sin ambigüedad: si aparece
+	// en el comprobante es la vía preferida.
+	if data.OperationID != nil {
+		if low := data.lowConfidence(ReceiptFieldOperationID); len(low) > 0 {
+			return needsConfirmation(low), nil
+		}
+
+		proof, err := s.Create(ctx, &ProofRequest{UserID: userID, IDMP: data.OperationID.Value})
+		if errors.Is(err, ErrProofNotFoundID) {
+			// Probablemente un dígito mal leído: que el usuario lo revise.
+			return needsConfirmation([]string{ReceiptFieldOperationID}), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &ReceiptResponse{Status: ReceiptStatusCreated, Proof: proof, Extracted: data}, nil
+	}
+
+	if low := data.lowConfidence(ReceiptFieldDate, ReceiptFieldTime, ReceiptFieldAmount); len(low) > 0 {
+		return needsConfirmation(low), nil
+	}
+
+	amount, err := strconv.ParseFloat(data.Amount.Value, 64)
+	if err != nil {
+		return needsConfirmation([]string{ReceiptFieldAmount}), nil
+	}
+
+	req := &ProofOthersRequest{UserID: userID, Date: data.Date.Value, Time: data.Time.Value, Amount: amount}
+	if data.Last4.confident() {
+		req.Last4 = &data.Last4.Value
+	}
+	if data.DNI.confident() {
+		req.DNI = &data.DNI.Value
+	}
+
+	proof, err := s.CreateFromOthers(ctx, req)
+	if errors.Is(err, ErrPaymentNotFound) {
+		return needsConfirmation([]string{ReceiptFieldDate, ReceiptFieldTime, ReceiptFieldAmount}), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ReceiptResponse{Status: ReceiptStatusCreated, Proof: proof, Extracted: data}, nil
+}
+
 func (s *Service) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*ProofResponse, error) {
 
 	var proofsResponse []*ProofResponse
@@ -279,6 +406,9 @@ func (s *Service) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*Proo
 			Last4Card:       proofs[i].Last4Card,
 			ExternalID:      proofs[i].ExternalID,
 			ProductName:     proofs[i].ProductName,
+			Stamps:          proofs[i].Stamps,
+			ReviewStatus:    proofs[i].ReviewStatus,
+			ReversedAt:      proofs[i].ReversedAt,
 		})
 	}
 
@@ -309,6 +439,9 @@ func (s *Service) GetAllByUserIDPaginated(ctx context.Context, userID uuid.UUID,
 			Last4Card:       proofs[i].Last4Card,
 			ExternalID:      proofs[i].ExternalID,
 			ProductName:     proofs[i].ProductName,
+			Stamps:          proofs[i].Stamps,
+			ReviewStatus:    proofs[i].ReviewStatus,
+			ReversedAt:      proofs[i].ReversedAt,
 		}
 	}
 
@@ -347,6 +480,9 @@ func (s *Service) GetLastThreeByUserID(ctx context.Context, userID uuid.UUID) ([
 			Last4Card:       proofs[i].Last4Card,
 			ExternalID:      proofs[i].ExternalID,
 			ProductName:     proofs[i].ProductName,
+			Stamps:          proofs[i].Stamps,
+			ReviewStatus:    proofs[i].ReviewStatus,
+			ReversedAt:      proofs[i].ReversedAt,
 		})
 	}
 
@@ -381,5 +517,8 @@ func (s *Service) GetByID(ctx context.Context, id string) (*ProofResponse, error
 		Last4Card:       proof.Last4Card,
 		ExternalID:      proof.ExternalID,
 		ProductName:     proof.ProductName,
+		Stamps:          proof.Stamps,
+		ReviewStatus:    proof.ReviewStatus,
+		ReversedAt:      proof.ReversedAt,
 	}, nil
 }
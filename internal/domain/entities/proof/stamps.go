@@ -0,0 +1,108 @@
+package proof
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/coffeeji"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/user"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/voucher"
+)
+
+// StampPolicy decide cuántos sellos suma un comprobante. Siempre devuelve al
+// menos uno: un pago que pasó las reglas de elegibilidad suma.
+type StampPolicy interface {
+	Stamps(items []coffeeji.OrderItem, amount float64) int
+}
+
+// SingleStamp es el comportamiento histórico: un sello por comprobante.
+type SingleStamp struct{}
+
+func (SingleStamp) Stamps([]coffeeji.OrderItem, float64) int { return 1 }
+
+// PerItem suma un sello por unidad del pedido. Max > 0 limita el total.
+type PerItem struct {
+	Max int
+}
+
+func (p PerItem) Stamps(items []coffeeji.OrderItem, _ float64) int {
+	n := 0
+	for _, it := range items {
+		n += it.Quantity
+	}
+	if p.Max > 0 && n > p.Max {
+		n = p.Max
+	}
+	return max(n, 1)
+}
+
+// AmountTier otorga Stamps sellos a pagos de al menos Min.
+type AmountTier struct {
+	Min    float64
+	Stamps int
+}
+
+// AmountTiers aplica el tramo más alto alcanzado por el monto del pago.
+type AmountTiers []AmountTier
+
+func (t AmountTiers) Stamps(_ []coffeeji.OrderItem, amount float64) int {
+	n := 1
+	for _, tier := range t {
+		if amount >= tier.Min {
+			n = tier.Stamps
+		}
+	}
+	return max(n, 1)
+}
+
+// ParseAmountTiers lee tramos con formato "monto:sellos", por ejemplo
+// "3000:2,6000:3". Los devuelve ordenados por monto.
+func ParseAmountTiers(specs []string) (AmountTiers, error) {
+	tiers := make(AmountTiers, 0, len(specs))
+	for _, spec := range specs {
+		amount, stamps, ok := strings.Cut(spec, ":")
+		if !ok {
+			return nil, fmt.Errorf("tramo inválido %q: se espera monto:sellos", spec)
+		}
+		min, err := strconv.ParseFloat(strings.TrimSpace(amount), 64)
+		if err != nil || min < 0 {
+			return nil, fmt.Errorf("tramo inválido %q: monto", spec)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(stamps))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("tramo inválido %q: sellos", spec)
+		}
+		tiers = append(tiers, AmountTier{Min: min, Stamps: n})
+	}
+
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].Min < tiers[j].Min })
+	return tiers, nil
+}
+
+// creditStamps suma n sellos al usuario y canjea un voucher por cada tarjeta
+// completa. Devuelve el contador final.
+func creditStamps(ctx context.Context, users *user.Service, vouchers *voucher.Service, userID uuid.UUID, n int) (int, error) {
+	total, err := users.AddStamps(ctx, userID, n)
+	if err != nil {
+		return 0, err
+	}
+	if total < stampsPerVoucher {
+		return total, nil
+	}
+
+	for total >= stampsPerVoucher {
+		if _, err := vouchers.AssignNextVoucher(ctx, &voucher.VoucherRequest{UserID: userID}); err != nil {
+			return 0, err
+		}
+		total -= stampsPerVoucher
+	}
+
+	if err := users.SetStampsCounter(ctx, userID, total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
@@ -0,0 +1,49 @@
+package proof
+
+import (
+	"testing"
+
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/coffeeji"
+)
+
+func TestStampPolicies(t *testing.T) {
+	t.Parallel()
+
+	items := []coffeeji.OrderItem{
+		{Name: "Latte", Quantity: 2, Amount: 3000},
+		{Name: "Espresso", Quantity: 1, Amount: 1200},
+	}
+
+	if got := (SingleStamp{}).Stamps(items, 4200); got != 1 {
+		t.Errorf("SingleStamp = %d, quería 1", got)
+	}
+	if got := (PerItem{}).Stamps(items, 4200); got != 3 {
+		t.Errorf("PerItem = %d, quería 3", got)
+	}
+	if got := (PerItem{Max: 2}).Stamps(items, 4200); got != 2 {
+		t.Errorf("PerItem con tope = %d, quería 2", got)
+	}
+	if got := (PerItem{}).Stamps(nil, 4200); got != 1 {
+		t.Errorf("PerItem sin líneas = %d, quería 1", got)
+	}
+
+	tiers, err := ParseAmountTiers([]string{"6000:3", " 3000 : 2 "})
+	if err != nil {
+		t.Fatalf("ParseAmountTiers: %v", err)
+	}
+	for amount, want := range map[float64]int{1000: 1, 3000: 2, 5999.99: 2, 6000: 3, 10000: 3} {
+		if got := tiers.Stamps(items, amount); got != want {
+			t.Errorf("AmountTiers(%.2f) = %d, quería %d", amount, got, want)
+		}
+	}
+}
+
+func TestParseAmountTiers_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, spec := range []string{"3000", "abc:2", "3000:0", "-1:2", "3000:x"} {
+		if _, err := ParseAmountTiers([]string{spec}); err == nil {
+			t.Errorf("ParseAmountTiers(%q) debería fallar", spec)
+		}
+	}
+}
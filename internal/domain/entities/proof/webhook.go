@@ -0,0 +1,142 @@
+package proof
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/sebaactis/powermix-back-mobile/internal/clients/mercadopago"
+	"github.com/sebaactis/powermix-back-mobile/internal/utils"
+)
+
+// ReversalSource consulta en Mercado Pago el estado actual de un pago. El
+// webhook va directo a la API: el espejo local puede estar desactualizado.
+type ReversalSource interface {
+	ValidatePaymentExists(ctx context.Context, paymentID string) (*mercadopago.PaymentDTO, error)
+	GetChargebackPayments(ctx context.Context, chargebackID string) ([]int64, error)
+}
+
+// WebhookHandler recibe las notificaciones de Mercado Pago: anula los
+// comprobantes de pagos devueltos o contracargados y atribuye los pagos
+// aprobados a los usuarios con datos de pago vinculados.
+type WebhookHandler struct {
+	service *Service
+	source  ReversalSource
+	secret  string
+}
+
+func NewWebhookHandler(service *Service, source ReversalSource, secret string) *WebhookHandler {
+	return &WebhookHandler{service: service, source: source, secret: secret}
+}
+
+const maxWebhookBody = 64 << 10
+
+func (h *WebhookHandler) MercadoPago(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		writeProofValidation(w, "No se pudo leer la notificación", nil)
+		return
+	}
+
+	var n mercadopago.WebhookNotification
+	if err := json.Unmarshal(body, &n); err != nil {
+		writeProofValidation(w, "Notificación inválida", nil)
+		return
+	}
+
+	// Mercado Pago firma el data.id que viaja en la query string.
+	dataID := r.URL.Query().Get("data.id")
+	if dataID == "" {
+		dataID = n.Data.ID
+	}
+
+	err = mercadopago.VerifyWebhookSignature(h.secret, r.Header.Get("x-signature"), r.Header.Get("x-request-id"), dataID)
+	if err != nil {
+		slog.WarnContext(ctx, "webhook de mercado pago con firma inválida", "type", n.Type, "data_id", dataID)
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeUnauthorized,
+			Message: "Firma inválida",
+		})
+		return
+	}
+
+	var reversed int
+	switch n.Type {
+	case "payment":
+		reversed, err = h.handlePayment(ctx, dataID)
+	case "chargebacks":
+		reversed, err = h.handleChargeback(ctx, dataID)
+	}
+	if err != nil {
+		// Respondemos 5xx para que Mercado Pago reintente.
+		slog.ErrorContext(ctx, "webhook de mercado pago falló", "type", n.Type, "data_id", dataID, "error", err)
+		writeProofInternal(w, "No se pudo procesar la notificación")
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, map[string]int{"reversed": reversed})
+}
+
+func (h *WebhookHandler) handlePayment(ctx context.Context, paymentID string) (int, error) {
+	if paymentID == "" {
+		return 0, nil
+	}
+
+	payment, err := h.source.ValidatePaymentExists(ctx, paymentID)
+	if err != nil {
+		return 0, err
+	}
+	if payment == nil {
+		return 0, nil
+	}
+
+	// Un pago aprobado nuevo puede atribuirse a un usuario con DNI o tarjeta
+	// vinculados.
+	if payment.Status == "approved" {
+		if _, err := h.service.AutoAttribute(ctx, []*mercadopago.PaymentDTO{payment}); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	if !mercadopago.IsReversedStatus(payment.Status) {
+		return 0, nil
+	}
+
+	ok, err := h.service.ReversePayment(ctx, paymentID, payment.Status)
+	if err != nil || !ok {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func (h *WebhookHandler) handleChargeback(ctx context.Context, chargebackID string) (int, error) {
+	if chargebackID == "" {
+		return 0, nil
+	}
+
+	ids, err := h.source.GetChargebackPayments(ctx, chargebackID)
+	if err != nil {
+		return 0, err
+	}
+
+	var reversed int
+	var errs []error
+	for _, id := range ids {
+		ok, err := h.service.ReversePayment(ctx, strconv.FormatInt(id, 10), mercadopago.StatusChargedBack)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			reversed++
+		}
+	}
+	return reversed, errors.Join(errs...)
+}
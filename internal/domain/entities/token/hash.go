@@ -2,7 +2,9 @@ package token
 
 import (
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 )
 
@@ -11,3 +13,13 @@ func HashToken(pepper []byte, raw string) string {
 	mac.Write([]byte(raw))
 	return hex.EncodeToString(mac.Sum(nil))
 }
+
+// NewOpaqueToken genera un token aleatorio apto para URLs, para links que no
+// necesitan ser JWT.
+func NewOpaqueToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -97,6 +97,118 @@ func (r *Repository) RevokeToken(ctx context.Context, token string) error {
 }
 
 
+// GetActiveByHash busca un token vigente (no revocado) del tipo y usuario indicados.
+func (r *Repository) GetActiveByHash(ctx context.Context, userID uuid.UUID, tokenType string, tokenHash string) (*Token, error) {
+	var t Token
+
+	err := r.db.WithContext(ctx).
+		Where("token_hash = ?", tokenHash).
+		Where("token_type = ?", tokenType).
+		Where("user_id = ?", userID).
+		Where("is_revoked = ?", false).
+		First(&t).Error
+
+	if err != nil {
+		return nil, mapResetTokenRepoErr(ctx, "get active by hash", err)
+	}
+
+	return &t, nil
+}
+
+// MarkUsed consume un token de un solo uso. Devuelve ErrTokenInvalid si otro
+// request lo consumió antes.
+func (r *Repository) MarkUsed(ctx context.Context, tokenID uuid.UUID, now time.Time, reason string) error {
+	result := r.db.WithContext(ctx).
+		Model(&Token{}).
+		Where("id = ?", tokenID).
+		Where("is_revoked = ?", false).
+		Updates(map[string]any{
+			"is_revoked":     true,
+			"revoked_date":   now,
+			"used_at":        now,
+			"revoked_reason": reason,
+		})
+
+	if result.Error != nil {
+		return mapTokenRepoErr(ctx, "mark used", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("token: mark used: %w", ErrTokenInvalid)
+	}
+
+	return nil
+}
+
+// RevokeAllByUserAndType revoca todos los tokens vigentes de un tipo para el usuario.
+func (r *Repository) RevokeAllByUserAndType(ctx context.Context, userID uuid.UUID, tokenType string, now time.Time, reason string) error {
+	result := r.db.WithContext(ctx).
+		Model(&Token{}).
+		Where("user_id = ?", userID).
+		Where("token_type = ?", tokenType).
+		Where("is_revoked = ?", false).
+		Updates(map[string]any{
+			"revoked_date":   now,
+			"is_revoked":     true,
+			"revoked_reason": reason,
+		})
+
+	if result.Error != nil {
+		return mapTokenRepoErr(ctx, "revoke all by user and type", result.Error)
+	}
+	return nil
+}
+
+// GetLatestActiveForUpdate bloquea el último token vigente de un tipo para el
+// usuario, de modo que los intentos concurrentes se cuenten de a uno.
+func (r *Repository) GetLatestActiveForUpdate(ctx context.Context, userID uuid.UUID, tokenType string, now time.Time) (*Token, error) {
+	var t Token
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		Where("token_type = ?", tokenType).
+		Where("is_revoked = ?", false).
+		Where("expires_at > ?", now).
+		Order("created_at DESC").
+		First(&t).Error
+
+	if err != nil {
+		return nil, mapResetTokenRepoErr(ctx, "get latest active for update", err)
+	}
+
+	return &t, nil
+}
+
+func (r *Repository) IncrementAttempts(ctx context.Context, tokenID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&Token{}).
+		Where("id = ?", tokenID).
+		Update("attempts", gorm.Expr("attempts + ?", 1))
+
+	if result.Error != nil {
+		return mapTokenRepoErr(ctx, "increment attempts", result.Error)
+	}
+	return nil
+}
+
+// CountActiveByUserAndType cuenta los tokens vigentes de un tipo para el usuario.
+func (r *Repository) CountActiveByUserAndType(ctx context.Context, userID uuid.UUID, tokenType string) (int64, error) {
+	var count int64
+
+	err := r.db.WithContext(ctx).
+		Model(&Token{}).
+		Where("user_id = ?", userID).
+		Where("token_type = ?", tokenType).
+		Where("is_revoked = ?", false).
+		Count(&count).Error
+
+	if err != nil {
+		return 0, mapTokenRepoErr(ctx, "count active by user and type", err)
+	}
+	return count, nil
+}
+
 func (r *Repository) GetRefreshByHashForUpdate(ctx context.Context, tokenHash string) (*Token, error) {
 	var t Token
 
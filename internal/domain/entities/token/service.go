@@ -2,7 +2,9 @@ package token
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -160,6 +162,162 @@ func (s *Service) RotateRefresh(ctx context.Context, rawRefresh string, now time
 	return
 }
 
+// ReplaceRecoveryCodes invalida los códigos de recuperación anteriores del
+// usuario y persiste los nuevos. Solo se guarda el hash: los códigos en claro
+// se muestran una única vez.
+func (s *Service) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codes []string) error {
+	now := time.Now()
+	tokenType := string(jwtx.TokenTypeMFARecovery)
+
+	return s.Transaction(ctx, func(sTx *Service) error {
+		if err := sTx.repository.RevokeAllByUserAndType(ctx, userID, tokenType, now, "replaced"); err != nil {
+			return err
+		}
+
+		for _, code := range codes {
+			t := &Token{
+				UserID:    userID,
+				TokenType: tokenType,
+				TokenHash: sTx.userCodeHash(userID, code),
+			}
+			if _, err := sTx.repository.Create(ctx, t); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ConsumeRecoveryCode marca como usado un código de recuperación vigente.
+func (s *Service) ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, code string) error {
+	t, err := s.repository.GetActiveByHash(ctx, userID, string(jwtx.TokenTypeMFARecovery), s.userCodeHash(userID, code))
+	if err != nil {
+		return err
+	}
+
+	return s.repository.MarkUsed(ctx, t.ID, time.Now(), "used")
+}
+
+// RevokeRecoveryCodes invalida todos los códigos de recuperación del usuario.
+func (s *Service) RevokeRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	return s.repository.RevokeAllByUserAndType(ctx, userID, string(jwtx.TokenTypeMFARecovery), time.Now(), "mfa_disabled")
+}
+
+// CountRecoveryCodes devuelve cuántos códigos de recuperación le quedan al usuario.
+func (s *Service) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.repository.CountActiveByUserAndType(ctx, userID, string(jwtx.TokenTypeMFARecovery))
+}
+
+// CreateEmailLoginCode persiste un código de acceso por email. Los códigos
+// anteriores del usuario quedan revocados: solo vale el último enviado.
+func (s *Service) CreateEmailLoginCode(ctx context.Context, userID uuid.UUID, code string, expiresAt time.Time) error {
+	now := time.Now()
+	tokenType := string(jwtx.TokenTypeEmailLogin)
+
+	return s.Transaction(ctx, func(sTx *Service) error {
+		if err := sTx.repository.RevokeAllByUserAndType(ctx, userID, tokenType, now, "replaced"); err != nil {
+			return err
+		}
+
+		// Se hashea con el ID propio del token: un código de 6 dígitos puede
+		// repetirse para el mismo usuario y chocaría con el índice único.
+		id := uuid.New()
+		_, err := sTx.repository.Create(ctx, &Token{
+			ID:        id,
+			UserID:    userID,
+			TokenType: tokenType,
+			TokenHash: sTx.userCodeHash(id, code),
+			ExpiresAt: expiresAt,
+		})
+		return err
+	})
+}
+
+// ConsumeEmailLoginCode valida el código contra el último emitido. Cada fallo
+// suma un intento y al llegar a maxAttempts el código se revoca.
+func (s *Service) ConsumeEmailLoginCode(ctx context.Context, userID uuid.UUID, code string, maxAttempts int) error {
+	now := time.Now()
+	var invalid bool
+
+	err := s.Transaction(ctx, func(sTx *Service) error {
+		t, err := sTx.repository.GetLatestActiveForUpdate(ctx, userID, string(jwtx.TokenTypeEmailLogin), now)
+		if err != nil {
+			return err
+		}
+
+		hash := sTx.userCodeHash(t.ID, code)
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(t.TokenHash)) == 1 {
+			return sTx.repository.MarkUsed(ctx, t.ID, now, "used")
+		}
+
+		// El fallo se confirma en la transacción; el error se devuelve afuera
+		// para que el rollback no descarte el intento.
+		invalid = true
+		if t.Attempts+1 >= maxAttempts {
+			return sTx.repository.MarkUsed(ctx, t.ID, now, "max_attempts")
+		}
+		return sTx.repository.IncrementAttempts(ctx, t.ID)
+	})
+
+	if err != nil {
+		return err
+	}
+	if invalid {
+		return fmt.Errorf("token: consume email login code: %w", ErrTokenInvalid)
+	}
+	return nil
+}
+
+// CreateSessionRevokeToken guarda el link de revocación asociado a una familia
+// de refresh tokens.
+func (s *Service) CreateSessionRevokeToken(ctx context.Context, userID, familyID uuid.UUID, rawToken string, expiresAt time.Time) error {
+	_, err := s.repository.Create(ctx, &Token{
+		UserID:    userID,
+		TokenType: string(jwtx.TokenTypeSessionRevoke),
+		TokenHash: HashToken(s.pepper, rawToken),
+		FamilyID:  familyID,
+		ExpiresAt: expiresAt,
+	})
+	return err
+}
+
+// RevokeSession consume el link y revoca la familia de refresh tokens que
+// inició la sesión sospechosa. Devuelve el dueño de la sesión.
+func (s *Service) RevokeSession(ctx context.Context, rawToken string) (uuid.UUID, error) {
+	now := time.Now()
+	var userID uuid.UUID
+
+	err := s.Transaction(ctx, func(sTx *Service) error {
+		t, err := sTx.repository.GetByToken(ctx, HashToken(sTx.pepper, rawToken))
+		if err != nil {
+			if errors.Is(err, ErrTokenNotFound) {
+				return fmt.Errorf("token: revoke session: %w", ErrTokenInvalid)
+			}
+			return err
+		}
+
+		if t.TokenType != string(jwtx.TokenTypeSessionRevoke) || t.IsRevoked || t.ExpiresAt.Before(now) {
+			return fmt.Errorf("token: revoke session: %w", ErrTokenInvalid)
+		}
+
+		if err := sTx.repository.RevokeFamily(ctx, t.FamilyID, now, "user_reported"); err != nil {
+			return err
+		}
+
+		userID = t.UserID
+		return sTx.repository.MarkUsed(ctx, t.ID, now, "used")
+	})
+
+	return userID, err
+}
+
+// userCodeHash antepone un ID al código para que dos usuarios (o dos tokens)
+// con el mismo código no choquen contra el índice único de token_hash.
+func (s *Service) userCodeHash(scope uuid.UUID, code string) string {
+	return HashToken(s.pepper, scope.String()+":"+code)
+}
+
 func (s *Service) WithTx(tx *gorm.DB) *Service {
 	return &Service{
 		repository: s.repository.WithTx(tx),
@@ -18,6 +18,7 @@ type Token struct {
 	ReplacedByID  *uuid.UUID `json:"replaced_by_id" gorm:"type:uuid;default:null;index"`
 	UsedAt        *time.Time `json:"used_at" gorm:"default:null"`
 	RevokedReason *string    `json:"revoked_reason" gorm:"size:30;default:null"`
+	Attempts      int        `json:"attempts" gorm:"default:0"`
 
 	RevokedDate time.Time `json:"revoked_date" gorm:"default:null"`
 	IsRevoked   bool      `json:"is_revoked" gorm:"default:false;index"`
@@ -24,6 +24,8 @@ type UserResponse struct {
 	LoginAttempts int       `json:"login_attempt"`
 	LockedUntil   string    `json:"locked_until"`
 	StampsCounter int       `json:"stamps_counter"`
+	Role          string    `json:"role"`
+	MFAEnabled    bool      `json:"mfa_enabled"`
 }
 
 type UserRecoveryPassword struct {
@@ -39,6 +41,48 @@ type UserChangePassword struct {
 	ConfirmPassword string `json:"confirmPassword" validate:"required,min=8,max=30,eqfield=NewPassword"`
 }
 
+type MFACodeRequest struct {
+	Code         string `json:"code" validate:"omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recoveryCode" validate:"omitempty,max=20"`
+}
+
+type EmailCodeRequest struct {
+	Email string `json:"email" validate:"required,email,max=32"`
+}
+
+type EmailCodeVerifyRequest struct {
+	Email string `json:"email" validate:"required,email,max=32"`
+	Code  string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// ReauthRequest confirma la identidad antes de una operación sensible: con la
+// contraseña o, para cuentas sin contraseña, con un código enviado por email.
+type ReauthRequest struct {
+	Password  string `json:"password"`
+	EmailCode string `json:"emailCode"`
+}
+
+type LinkIdentityRequest struct {
+	AccessToken string `json:"accessToken"`
+	Password    string `json:"password"`
+	EmailCode   string `json:"emailCode"`
+}
+
+type IdentityResponse struct {
+	Provider string    `json:"provider"`
+	Email    string    `json:"email"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+type TOTPEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
 func ToResponse(u *User) *UserResponse {
 	return &UserResponse{
 		ID:            u.ID,
@@ -47,6 +91,8 @@ func ToResponse(u *User) *UserResponse {
 		LockedUntil:   u.LockedUntil.Truncate(time.Second).String(),
 		LoginAttempts: u.LoginAttempt,
 		StampsCounter: u.StampsCounter,
+		Role:          u.Role,
+		MFAEnabled:    u.TOTPEnabled,
 	}
 }
 
@@ -59,3 +105,17 @@ func ToResponseMany(users []*User) []*UserResponse {
 
 	return response
 }
+
+func ToIdentityResponseMany(identities []Identity) []*IdentityResponse {
+	response := make([]*IdentityResponse, len(identities))
+
+	for i, id := range identities {
+		response[i] = &IdentityResponse{
+			Provider: id.Provider,
+			Email:    id.Email,
+			LinkedAt: id.CreatedAt,
+		}
+	}
+
+	return response
+}
@@ -6,4 +6,19 @@ var (
 	ErrNotFound       = errors.New("user: usuario no encontrado")
 	ErrInternal       = errors.New("user: error interno de persistencia")
 	ErrDuplicateEmail = errors.New("el email ya esta en uso")
+
+	ErrMFAInvalidCode      = errors.New("user: código de segundo factor inválido")
+	ErrMFANotEnrolled      = errors.New("user: el segundo factor no está configurado")
+	ErrMFAAlreadyEnabled   = errors.New("user: el segundo factor ya está activo")
+	ErrMFARequiredForAdmin = errors.New("user: los administradores no pueden desactivar el segundo factor")
+
+	ErrEmailCodeInvalid = errors.New("user: código de acceso inválido o expirado")
+
+	ErrIdentityNotFound      = errors.New("user: identidad no vinculada")
+	ErrIdentityAlreadyLinked = errors.New("user: la identidad ya está vinculada")
+	ErrIdentityInUse         = errors.New("user: la identidad pertenece a otra cuenta")
+	ErrIdentityLinkRequired  = errors.New("user: el email ya tiene cuenta, hay que vincular la identidad explícitamente")
+	ErrLastLoginMethod       = errors.New("user: no se puede quitar el único método de acceso")
+	ErrReauthRequired        = errors.New("user: se requiere reautenticación")
+	ErrReauthFailed          = errors.New("user: reautenticación inválida")
 )
@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/sebaactis/powermix-back-mobile/internal/clients/mailer"
 	jwtx "github.com/sebaactis/powermix-back-mobile/internal/security/jwt"
+	"github.com/sebaactis/powermix-back-mobile/internal/security/oauth"
 	"github.com/sebaactis/powermix-back-mobile/internal/utils"
 	"github.com/sebaactis/powermix-back-mobile/internal/validations"
 )
@@ -274,6 +275,297 @@ func (h *HTTPHandler) SendEmailContact(w http.ResponseWriter, r *http.Request) {
 	utils.WriteSuccess(w, http.StatusOK, emailSend)
 }
 
+func (h *HTTPHandler) StartTOTPEnrollment(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.getUserIDFromRequest(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeUnauthorized,
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	enrollment, err := h.service.StartTOTPEnrollment(r.Context(), userID)
+	if err != nil {
+		h.writeMFAError(w, r, userID, err)
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, enrollment)
+}
+
+func (h *HTTPHandler) ConfirmTOTPEnrollment(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.getUserIDFromRequest(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeUnauthorized,
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeValidation,
+			Message: "No se pudo parsear la request, por favor revise los datos enviados",
+		})
+		return
+	}
+
+	codes, err := h.service.ConfirmTOTPEnrollment(r.Context(), userID, req.Code)
+	if err != nil {
+		h.writeMFAError(w, r, userID, err)
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *HTTPHandler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.getUserIDFromRequest(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeUnauthorized,
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeValidation,
+			Message: "No se pudo parsear la request, por favor revise los datos enviados",
+		})
+		return
+	}
+
+	if err := h.service.DisableTOTP(r.Context(), userID, req); err != nil {
+		h.writeMFAError(w, r, userID, err)
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, map[string]any{"message": "Segundo factor desactivado"})
+}
+
+func (h *HTTPHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.getUserIDFromRequest(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeUnauthorized,
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req MFACodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeValidation,
+			Message: "No se pudo parsear la request, por favor revise los datos enviados",
+		})
+		return
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(r.Context(), userID, req)
+	if err != nil {
+		h.writeMFAError(w, r, userID, err)
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *HTTPHandler) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.getUserIDFromRequest(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeUnauthorized,
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	identities, err := h.service.ListIdentities(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al listar identidades", "user_id", userID, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeInternal,
+			Message: "Error en el servidor",
+		})
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, ToIdentityResponseMany(identities))
+}
+
+func (h *HTTPHandler) LinkIdentity(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.getUserIDFromRequest(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeUnauthorized,
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req LinkIdentityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.AccessToken == "" {
+		utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeValidation,
+			Message: "El accessToken del proveedor es requerido",
+		})
+		return
+	}
+
+	info, err := oauth.GetUserInfo(r.Context(), chi.URLParam(r, "provider"), req.AccessToken)
+	if err != nil {
+		if errors.Is(err, oauth.ErrUnsupportedProvider) {
+			utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+				Code:    utils.ErrCodeValidation,
+				Message: "Proveedor no soportado",
+			})
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al validar token del proveedor", "user_id", userID, "error", err)
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeExternalService,
+			Message: "Token del proveedor inválido",
+		})
+		return
+	}
+
+	identity, err := h.service.LinkIdentity(r.Context(), userID, info, ReauthRequest{
+		Password:  req.Password,
+		EmailCode: req.EmailCode,
+	})
+	if err != nil {
+		h.writeIdentityError(w, r, userID, err)
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusCreated, ToIdentityResponseMany([]Identity{*identity})[0])
+}
+
+func (h *HTTPHandler) UnlinkIdentity(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.getUserIDFromRequest(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeUnauthorized,
+			Message: "Token inválido",
+		})
+		return
+	}
+
+	var req ReauthRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeValidation,
+			Message: "No se pudo parsear la request, por favor revise los datos enviados",
+		})
+		return
+	}
+
+	if err := h.service.UnlinkIdentity(r.Context(), userID, chi.URLParam(r, "provider"), req); err != nil {
+		h.writeIdentityError(w, r, userID, err)
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, map[string]any{"message": "Cuenta desvinculada"})
+}
+
+func (h *HTTPHandler) writeIdentityError(w http.ResponseWriter, r *http.Request, userID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, ErrReauthRequired):
+		utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeValidation,
+			Message: "Confirmá tu identidad con tu contraseña o un código enviado por email",
+		})
+	case errors.Is(err, ErrReauthFailed):
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeInvalidCreds,
+			Message: "No se pudo confirmar tu identidad",
+		})
+	case errors.Is(err, ErrIdentityAlreadyLinked):
+		utils.WriteError(w, http.StatusConflict, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeDuplicateEntry,
+			Message: "Ya tenés una cuenta de este proveedor vinculada",
+		})
+	case errors.Is(err, ErrIdentityInUse):
+		utils.WriteError(w, http.StatusConflict, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeConflict,
+			Message: "Esa cuenta ya está vinculada a otro usuario",
+		})
+	case errors.Is(err, ErrLastLoginMethod):
+		utils.WriteError(w, http.StatusConflict, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeConflict,
+			Message: "No podés desvincular tu único método de acceso. Definí una contraseña primero",
+		})
+	case errors.Is(err, ErrIdentityNotFound):
+		utils.WriteError(w, http.StatusNotFound, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeNotFound,
+			Message: "No hay una cuenta vinculada para ese proveedor",
+		})
+	case errors.Is(err, ErrNotFound):
+		utils.WriteError(w, http.StatusNotFound, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeNotFound,
+			Message: "Usuario no encontrado",
+		})
+	default:
+		slog.ErrorContext(r.Context(), "error al gestionar identidades", "user_id", userID, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeInternal,
+			Message: "Error en el servidor",
+		})
+	}
+}
+
+func (h *HTTPHandler) writeMFAError(w http.ResponseWriter, r *http.Request, userID uuid.UUID, err error) {
+	if fields, ok := validations.AsValidationError(err); ok {
+		utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeValidation,
+			Message: "Error de validación",
+			Fields:  fields,
+		})
+		return
+	}
+
+	switch {
+	case errors.Is(err, ErrMFAInvalidCode):
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeInvalidCreds,
+			Message: "Código inválido",
+		})
+	case errors.Is(err, ErrMFANotEnrolled):
+		utils.WriteError(w, http.StatusConflict, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeConflict,
+			Message: "El segundo factor no está configurado",
+		})
+	case errors.Is(err, ErrMFAAlreadyEnabled):
+		utils.WriteError(w, http.StatusConflict, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeConflict,
+			Message: "El segundo factor ya está activo",
+		})
+	case errors.Is(err, ErrMFARequiredForAdmin):
+		utils.WriteError(w, http.StatusForbidden, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeUnauthorized,
+			Message: "Los administradores deben mantener el segundo factor activo",
+		})
+	case errors.Is(err, ErrNotFound):
+		utils.WriteError(w, http.StatusNotFound, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeNotFound,
+			Message: "Usuario no encontrado",
+		})
+	default:
+		slog.ErrorContext(r.Context(), "error en segundo factor", "user_id", userID, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeInternal,
+			Message: "Error en el servidor",
+		})
+	}
+}
+
 // Helper privado
 func (h *HTTPHandler) getUserIDFromRequest(r *http.Request) (uuid.UUID, error) {
 	authHeader := r.Header.Get("Authorization")
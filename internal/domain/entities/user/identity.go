@@ -0,0 +1,23 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Identity vincula una cuenta con un proveedor externo de login. Un usuario
+// puede tener varias (una por proveedor) y cada identidad pertenece a un solo usuario.
+type Identity struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_user_identities_user_provider"`
+	Provider   string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_user_identities_provider_id;uniqueIndex:idx_user_identities_user_provider"`
+	ProviderID string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_user_identities_provider_id"`
+	Email      string    `gorm:"type:varchar(255)"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (Identity) TableName() string {
+	return "user_identities"
+}
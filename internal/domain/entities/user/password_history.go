@@ -0,0 +1,20 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordHistory guarda los hashes de contraseñas anteriores para impedir
+// que se reutilicen. Solo se conservan las últimas N (ver password.Policy).
+type PasswordHistory struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null;index:idx_password_history_user_created"`
+	PasswordHash string    `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"index:idx_password_history_user_created"`
+}
+
+func (PasswordHistory) TableName() string {
+	return "user_password_history"
+}
@@ -4,15 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/sebaactis/powermix-back-mobile/internal/security/oauth"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // isDuplicateKeyError verifica si el error es de clave duplicada (constraint violation)
@@ -67,55 +66,61 @@ func (r *Repository) Create(ctx context.Context, user *User) error {
 	return mapRepoErr(ctx, "create", insertErr)
 }
 
-func (r *Repository) CreateWithOAuth(ctx context.Context, info *oauth.OAuthUserInfo) (*User, error) {
-	// Primero buscar si el usuario ya existe por email
-	var existing User
-	err := r.db.WithContext(ctx).Where("email = ?", info.Email).First(&existing).Error
+// FindByIdentity devuelve el usuario dueño de la identidad externa.
+func (r *Repository) FindByIdentity(ctx context.Context, provider, providerID string) (*User, error) {
+	var u User
 
-	if err == nil {
-		// Usuario encontrado: actualizar OAuth si es primera vez que vincula
-		if existing.OAuthProvider == "" {
-			existing.OAuthProvider = info.Provider
-			existing.OAuthID = info.ProviderID
-			if saveErr := r.db.WithContext(ctx).Save(&existing).Error; saveErr != nil {
-				return nil, mapRepoErr(ctx, "create with oauth save", saveErr)
-			}
-			log.Printf("Usuario existente vinculado con OAuth: id=%s email=%s", existing.ID, existing.Email)
-		} else {
-			log.Printf("Usuario ya existe con OAuth: id=%s email=%s provider=%s", existing.ID, existing.Email, existing.OAuthProvider)
+	err := r.db.WithContext(ctx).
+		Joins("JOIN user_identities ui ON ui.user_id = users.id").
+		Where("ui.provider = ? AND ui.provider_id = ?", provider, providerID).
+		First(&u).Error
+
+	if err != nil {
+		return nil, mapRepoErr(ctx, "find by identity", err)
+	}
+
+	return &u, nil
+}
+
+func (r *Repository) CreateIdentity(ctx context.Context, identity *Identity) error {
+	if err := r.db.WithContext(ctx).Create(identity).Error; err != nil {
+		if isDuplicateKeyError(err) {
+			return fmt.Errorf("user: create identity: %w", ErrIdentityAlreadyLinked)
 		}
-		return &existing, nil
+		return mapRepoErr(ctx, "create identity", err)
 	}
+	return nil
+}
+
+func (r *Repository) ListIdentities(ctx context.Context, userID uuid.UUID) ([]Identity, error) {
+	var identities []Identity
 
-	if !errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, mapRepoErr(ctx, "create with oauth find", err)
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&identities).Error
+
+	if err != nil {
+		return nil, mapRepoErr(ctx, "list identities", err)
 	}
 
-	// No existe: crear nuevo usuario con OAuth
-	newUser := User{
-		Name:          info.Name,
-		Email:         info.Email,
-		OAuthProvider: info.Provider,
-		OAuthID:       info.ProviderID,
-		StampsCounter: 0,
+	return identities, nil
+}
+
+func (r *Repository) DeleteIdentity(ctx context.Context, userID uuid.UUID, provider string) error {
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND provider = ?", userID, provider).
+		Delete(&Identity{})
+
+	if result.Error != nil {
+		return mapRepoErr(ctx, "delete identity", result.Error)
 	}
 
-	if createErr := r.db.WithContext(ctx).Create(&newUser).Error; createErr != nil {
-		// Si hay race condition (otro request creó el mismo email justo ahora),
-		// reintentar buscando el usuario existente
-		if isDuplicateKeyError(createErr) {
-			var retry User
-			if retryErr := r.db.WithContext(ctx).Where("email = ?", info.Email).First(&retry).Error; retryErr != nil {
-				return nil, mapRepoErr(ctx, "create with oauth retry find", retryErr)
-			}
-			log.Printf("Race condition OAuth resuelta: id=%s email=%s", retry.ID, retry.Email)
-			return &retry, nil
-		}
-		return nil, mapRepoErr(ctx, "create with oauth", createErr)
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user: delete identity: %w", ErrIdentityNotFound)
 	}
 
-	log.Printf("Usuario nuevo con OAuth creado: id=%s email=%s", newUser.ID, newUser.Email)
-	return &newUser, nil
+	return nil
 }
 
 func (r *Repository) FindByID(ctx context.Context, id uuid.UUID) (*User, error) {
@@ -186,6 +191,46 @@ func (r *Repository) UpdatePassword(ctx context.Context, id uuid.UUID, hashedPas
 	return &user, nil
 }
 
+// MarkEmailVerified guarda la primera vez que el usuario demostró controlar
+// su email.
+func (r *Repository) MarkEmailVerified(ctx context.Context, id uuid.UUID, at time.Time) error {
+	err := r.db.WithContext(ctx).
+		Model(&User{}).
+		Where("id = ? AND email_verified_at IS NULL", id).
+		Update("email_verified_at", at).Error
+	if err != nil {
+		return mapRepoErr(ctx, "mark email verified", err)
+	}
+	return nil
+}
+
+// SyncAdmins deja con rol ADMIN exactamente a las cuentas de emails y pasa
+// a USER a los demás administradores.
+func (r *Repository) SyncAdmins(ctx context.Context, emails []string) (promoted, demoted int64, err error) {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&User{}).
+			Where("lower(email) IN ? AND role <> ?", emails, RoleAdmin).
+			Update("role", RoleAdmin)
+		if res.Error != nil {
+			return res.Error
+		}
+		promoted = res.RowsAffected
+
+		res = tx.Model(&User{}).
+			Where("role = ? AND lower(email) NOT IN ?", RoleAdmin, emails).
+			Update("role", RoleUser)
+		if res.Error != nil {
+			return res.Error
+		}
+		demoted = res.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, 0, mapRepoErr(ctx, "sync admins", err)
+	}
+	return promoted, demoted, nil
+}
+
 func (r *Repository) Delete(ctx context.Context, id uint) error {
 	if err := r.db.WithContext(ctx).Delete(&User{}, id).Error; err != nil {
 		return mapRepoErr(ctx, "delete", err)
@@ -194,28 +239,80 @@ func (r *Repository) Delete(ctx context.Context, id uint) error {
 }
 
 func (r *Repository) IncrementStampsCounter(ctx context.Context, id uuid.UUID) (int, error) {
+	return r.AddStamps(ctx, id, 1)
+}
+
+// AddStamps suma n sellos y devuelve el contador resultante.
+func (r *Repository) AddStamps(ctx context.Context, id uuid.UUID, n int) (int, error) {
 	result := r.db.WithContext(ctx).
 		Model(&User{}).
 		Where("id = ?", id).
-		Update("stamps_counter", gorm.Expr("stamps_counter + ?", 1))
+		Update("stamps_counter", gorm.Expr("stamps_counter + ?", n))
 
 	if result.Error != nil {
-		return 0, mapRepoErr(ctx, "increment stamps counter", result.Error)
+		return 0, mapRepoErr(ctx, "add stamps", result.Error)
 	}
 
 	if result.RowsAffected == 0 {
-		return 0, fmt.Errorf("user: increment stamps counter: %w", ErrNotFound)
+		return 0, fmt.Errorf("user: add stamps: %w", ErrNotFound)
 	}
 
 	var user User
 
 	if err := r.db.WithContext(ctx).Select("stamps_counter").First(&user, id).Error; err != nil {
-		return 0, mapRepoErr(ctx, "increment stamps counter read", err)
+		return 0, mapRepoErr(ctx, "add stamps read", err)
 	}
 
 	return user.StampsCounter, nil
 }
 
+// DebitStamps resta hasta n sellos sin dejar el contador negativo y devuelve
+// cuántos pudo restar. Bloquea la fila del usuario; usar dentro de una
+// transacción.
+func (r *Repository) DebitStamps(ctx context.Context, id uuid.UUID, n int) (int, error) {
+	var user User
+
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "stamps_counter").
+		First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, fmt.Errorf("user: debit stamps: %w", ErrNotFound)
+		}
+		return 0, mapRepoErr(ctx, "debit stamps read", err)
+	}
+
+	debited := min(n, user.StampsCounter)
+	if debited <= 0 {
+		return 0, nil
+	}
+
+	if err := r.db.WithContext(ctx).
+		Model(&User{}).
+		Where("id = ?", id).
+		Update("stamps_counter", gorm.Expr("stamps_counter - ?", debited)).Error; err != nil {
+		return 0, mapRepoErr(ctx, "debit stamps", err)
+	}
+
+	return debited, nil
+}
+
+func (r *Repository) SetStampsCounter(ctx context.Context, id uuid.UUID, n int) error {
+	result := r.db.WithContext(ctx).
+		Model(&User{}).
+		Where("id = ?", id).
+		Update("stamps_counter", n)
+
+	if result.Error != nil {
+		return mapRepoErr(ctx, "set stamps counter", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user: set stamps counter: %w", ErrNotFound)
+	}
+	return nil
+}
+
 func (r *Repository) ResetStampsCounter(ctx context.Context, id uuid.UUID) (int, error) {
 	result := r.db.WithContext(ctx).
 		Model(&User{}).
@@ -260,11 +357,11 @@ func (r *Repository) IncrementLoginAttempt(ctx context.Context, id uuid.UUID) (i
 			return fmt.Errorf("user: increment login attempt: %w", ErrNotFound)
 		}
 
-		if newAttemptCount >= 5 {
+		if lock := LockoutDuration(newAttemptCount); lock > 0 {
 			now := time.Now()
 			if lockErr := tx.Model(&User{}).
 				Where("id = ?", id).
-				Update("locked_until", now.Add(15*time.Minute)).Error; lockErr != nil {
+				Update("locked_until", now.Add(lock)).Error; lockErr != nil {
 				return mapRepoErr(ctx, "increment login attempt lock", lockErr)
 			}
 		}
@@ -280,6 +377,25 @@ func (r *Repository) IncrementLoginAttempt(ctx context.Context, id uuid.UUID) (i
 }
 
 
+// ClearLock levanta el bloqueo vencido pero conserva el contador de intentos,
+// para que el próximo fallo siga escalando la espera.
+func (r *Repository) ClearLock(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&User{}).
+		Where("id = ?", id).
+		Update("locked_until", nil)
+
+	if result.Error != nil {
+		return mapRepoErr(ctx, "clear lock", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user: clear lock: %w", ErrNotFound)
+	}
+
+	return nil
+}
+
 func (r *Repository) UnlockUser(ctx context.Context, id uuid.UUID) error {
 
 	updates := map[string]interface{}{
@@ -303,6 +419,128 @@ func (r *Repository) UnlockUser(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// SetTOTPSecret guarda el secreto pendiente de confirmación. No activa el 2FA.
+func (r *Repository) SetTOTPSecret(ctx context.Context, id uuid.UUID, secret string) error {
+	result := r.db.WithContext(ctx).
+		Model(&User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"totp_secret":    secret,
+			"totp_enabled":   false,
+			"totp_last_step": 0,
+		})
+
+	if result.Error != nil {
+		return mapRepoErr(ctx, "set totp secret", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user: set totp secret: %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *Repository) EnableTOTP(ctx context.Context, id uuid.UUID, step int64) error {
+	result := r.db.WithContext(ctx).
+		Model(&User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"totp_enabled":   true,
+			"totp_last_step": step,
+		})
+
+	if result.Error != nil {
+		return mapRepoErr(ctx, "enable totp", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user: enable totp: %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *Repository) DisableTOTP(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"totp_secret":    nil,
+			"totp_enabled":   false,
+			"totp_last_step": 0,
+		})
+
+	if result.Error != nil {
+		return mapRepoErr(ctx, "disable totp", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user: disable totp: %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// ConsumeTOTPStep registra la ventana usada solo si es posterior a la última,
+// así un mismo código no puede reutilizarse aunque lleguen dos requests juntos.
+func (r *Repository) ConsumeTOTPStep(ctx context.Context, id uuid.UUID, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&User{}).
+		Where("id = ?", id).
+		Where("totp_last_step < ?", step).
+		Update("totp_last_step", step)
+
+	if result.Error != nil {
+		return false, mapRepoErr(ctx, "consume totp step", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// AddPasswordHistory registra un hash y descarta los que exceden keep.
+func (r *Repository) AddPasswordHistory(ctx context.Context, userID uuid.UUID, hash string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	if err := r.db.WithContext(ctx).Create(&PasswordHistory{UserID: userID, PasswordHash: hash}).Error; err != nil {
+		return mapRepoErr(ctx, "add password history", err)
+	}
+
+	err := r.db.WithContext(ctx).Exec(`
+		DELETE FROM user_password_history
+		WHERE user_id = ? AND id NOT IN (
+			SELECT id FROM user_password_history
+			WHERE user_id = ?
+			ORDER BY created_at DESC
+			LIMIT ?
+		)
+	`, userID, userID, keep).Error
+	if err != nil {
+		return mapRepoErr(ctx, "prune password history", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) RecentPasswordHashes(ctx context.Context, userID uuid.UUID, limit int) ([]string, error) {
+	var hashes []string
+
+	err := r.db.WithContext(ctx).
+		Model(&PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Pluck("password_hash", &hashes).Error
+
+	if err != nil {
+		return nil, mapRepoErr(ctx, "recent password hashes", err)
+	}
+
+	return hashes, nil
+}
+
 func mapRepoErr(ctx context.Context, action string, err error) error {
 	if err == nil {
 		return nil
@@ -2,8 +2,12 @@ package user
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math/big"
+	"net/url"
 	"strings"
 	"time"
 
@@ -11,6 +15,8 @@ import (
 	"github.com/sebaactis/powermix-back-mobile/internal/clients/mailer"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/token"
 	"github.com/sebaactis/powermix-back-mobile/internal/security/oauth"
+	"github.com/sebaactis/powermix-back-mobile/internal/security/password"
+	"github.com/sebaactis/powermix-back-mobile/internal/security/totp"
 	"github.com/sebaactis/powermix-back-mobile/internal/validations"
 
 	"golang.org/x/crypto/bcrypt"
@@ -19,25 +25,39 @@ import (
 
 var ErrSameName = errors.New("el nombre no puede ser igual al actual")
 
+const (
+	totpIssuer         = "Powermix Station"
+	recoveryCodesCount = 10
+
+	emailCodeTTL         = 10 * time.Minute
+	emailCodeMaxAttempts = 5
+	emailCodeLoginURL    = "https://powermixstation.com.ar/login-email"
+)
+
 type Service struct {
-	repository   *Repository
-	tokenService *token.Service
-	validator    validations.StructValidator
-	mailer       mailer.Mailer
-	db           *gorm.DB
+	repository     *Repository
+	tokenService   *token.Service
+	validator      validations.StructValidator
+	mailer         mailer.Mailer
+	db             *gorm.DB
+	passwordPolicy password.Policy
+	// breached es opcional: sin dataset configurado no se chequean filtraciones.
+	breached password.BreachedChecker
 }
 
-func NewService(repository *Repository, tokenService *token.Service, v validations.StructValidator, mailer mailer.Mailer) *Service {
-	return &Service{repository: repository, tokenService: tokenService, db: repository.db, validator: v, mailer: mailer}
+func NewService(repository *Repository, tokenService *token.Service, v validations.StructValidator, mailer mailer.Mailer, policy password.Policy, breached password.BreachedChecker) *Service {
+	return &Service{repository: repository, tokenService: tokenService, db: repository.db, validator: v, mailer: mailer, passwordPolicy: policy, breached: breached}
 }
 
 func (s *Service) WithTx(tx *gorm.DB) *Service {
 	return &Service{
-		repository:   s.repository.WithTx(tx),
-		tokenService: s.tokenService,
-		validator:    s.validator,
-		mailer:       s.mailer,
-		db:           tx,
+		repository:     s.repository.WithTx(tx),
+		tokenService:   s.tokenService,
+		validator:      s.validator,
+		mailer:         s.mailer,
+		db:             tx,
+		passwordPolicy: s.passwordPolicy,
+		breached:       s.breached,
 	}
 }
 
@@ -48,6 +68,11 @@ func (s *Service) Create(ctx context.Context, user *UserCreate) (*User, error) {
 
 	name := strings.TrimSpace(user.Name)
 	email := strings.TrimSpace(user.Email)
+
+	if err := s.checkNewPassword(ctx, "Password", strings.TrimSpace(user.Password), nil, email, name); err != nil {
+		return nil, err
+	}
+
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(strings.TrimSpace(user.Password)), bcrypt.DefaultCost)
 
 	if err != nil {
@@ -64,17 +89,213 @@ func (s *Service) Create(ctx context.Context, user *UserCreate) (*User, error) {
 		return nil, wrapServiceErr("create", err)
 	}
 
+	if err := s.repository.AddPasswordHistory(ctx, newUser.ID, newUser.Password, s.passwordPolicy.HistorySize); err != nil {
+		return nil, wrapServiceErr("create password history", err)
+	}
+
 	return newUser, nil
 }
 
+// FindOrCreateFromOAuth resuelve el login con un proveedor externo. Si la
+// identidad ya está vinculada devuelve su dueño; si el email pertenece a una
+// cuenta existente solo se vincula cuando tanto el proveedor como la cuenta
+// tienen el email verificado; si no, crea la cuenta junto con la identidad.
 func (s *Service) FindOrCreateFromOAuth(ctx context.Context, info *oauth.OAuthUserInfo) (*User, error) {
-	u, err := s.repository.CreateWithOAuth(ctx, info)
+	u, err := s.repository.FindByIdentity(ctx, info.Provider, info.ProviderID)
+	if err == nil {
+		return u, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, wrapServiceErr("find or create oauth find identity", err)
+	}
+
+	email := strings.TrimSpace(info.Email)
+
+	existing, err := s.repository.FindByEmail(ctx, email)
+	if err == nil {
+		return s.mergeOAuthIdentity(ctx, existing, info)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, wrapServiceErr("find or create oauth find email", err)
+	}
+
+	newUser := &User{
+		Name:  info.Name,
+		Email: email,
+	}
+	if info.EmailVerified {
+		now := time.Now()
+		newUser.EmailVerifiedAt = &now
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repository.WithTx(tx)
+		if err := txRepo.Create(ctx, newUser); err != nil {
+			return err
+		}
+		return txRepo.CreateIdentity(ctx, &Identity{
+			UserID:     newUser.ID,
+			Provider:   info.Provider,
+			ProviderID: info.ProviderID,
+			Email:      email,
+		})
+	})
+
 	if err != nil {
+		// Otro request creó la cuenta en paralelo: se aplican las reglas de vinculación.
+		if errors.Is(err, ErrDuplicateEmail) {
+			existing, findErr := s.repository.FindByEmail(ctx, email)
+			if findErr != nil {
+				return nil, wrapServiceErr("find or create oauth retry find", findErr)
+			}
+			return s.mergeOAuthIdentity(ctx, existing, info)
+		}
+		if errors.Is(err, ErrIdentityAlreadyLinked) {
+			u, findErr := s.repository.FindByIdentity(ctx, info.Provider, info.ProviderID)
+			if findErr != nil {
+				return nil, wrapServiceErr("find or create oauth retry identity", findErr)
+			}
+			return u, nil
+		}
 		return nil, wrapServiceErr("find or create oauth", err)
 	}
+
+	slog.InfoContext(ctx, "usuario nuevo con OAuth", "user_id", newUser.ID, "provider", info.Provider)
+	return newUser, nil
+}
+
+// mergeOAuthIdentity vincula la identidad a una cuenta existente con el mismo
+// email. Si la cuenta nunca verificó su email no se vincula: cualquiera pudo
+// registrarla con un email ajeno y seguiría teniendo acceso. En ese caso el
+// dueño tiene que vincularla con LinkIdentity.
+func (s *Service) mergeOAuthIdentity(ctx context.Context, u *User, info *oauth.OAuthUserInfo) (*User, error) {
+	if !info.EmailVerified || !u.EmailVerified() {
+		return nil, ErrIdentityLinkRequired
+	}
+
+	err := s.repository.CreateIdentity(ctx, &Identity{
+		UserID:     u.ID,
+		Provider:   info.Provider,
+		ProviderID: info.ProviderID,
+		Email:      strings.TrimSpace(info.Email),
+	})
+	if err != nil {
+		return nil, wrapServiceErr("merge oauth identity", err)
+	}
+
+	slog.InfoContext(ctx, "identidad OAuth vinculada por email verificado", "user_id", u.ID, "provider", info.Provider)
 	return u, nil
 }
 
+func (s *Service) ListIdentities(ctx context.Context, userID uuid.UUID) ([]Identity, error) {
+	identities, err := s.repository.ListIdentities(ctx, userID)
+	if err != nil {
+		return nil, wrapServiceErr("list identities", err)
+	}
+	return identities, nil
+}
+
+// LinkIdentity vincula explícitamente una identidad externa a la cuenta
+// autenticada. No exige que los emails coincidan porque hay reautenticación.
+func (s *Service) LinkIdentity(ctx context.Context, userID uuid.UUID, info *oauth.OAuthUserInfo, reauth ReauthRequest) (*Identity, error) {
+	u, err := s.repository.FindByID(ctx, userID)
+	if err != nil {
+		return nil, wrapServiceErr("link identity find user", err)
+	}
+
+	if err := s.reauthenticate(ctx, u, reauth); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.repository.FindByIdentity(ctx, info.Provider, info.ProviderID)
+	if err == nil {
+		if owner.ID == u.ID {
+			return nil, ErrIdentityAlreadyLinked
+		}
+		return nil, ErrIdentityInUse
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, wrapServiceErr("link identity find owner", err)
+	}
+
+	identity := &Identity{
+		UserID:     u.ID,
+		Provider:   info.Provider,
+		ProviderID: info.ProviderID,
+		Email:      strings.TrimSpace(info.Email),
+	}
+
+	if err := s.repository.CreateIdentity(ctx, identity); err != nil {
+		return nil, wrapServiceErr("link identity", err)
+	}
+
+	return identity, nil
+}
+
+// UnlinkIdentity quita una identidad externa, siempre que la cuenta conserve
+// otro método de acceso.
+func (s *Service) UnlinkIdentity(ctx context.Context, userID uuid.UUID, provider string, reauth ReauthRequest) error {
+	u, err := s.repository.FindByID(ctx, userID)
+	if err != nil {
+		return wrapServiceErr("unlink identity find user", err)
+	}
+
+	if err := s.reauthenticate(ctx, u, reauth); err != nil {
+		return err
+	}
+
+	identities, err := s.repository.ListIdentities(ctx, u.ID)
+	if err != nil {
+		return wrapServiceErr("unlink identity list", err)
+	}
+
+	linked := false
+	for _, id := range identities {
+		if id.Provider == provider {
+			linked = true
+			break
+		}
+	}
+	if !linked {
+		return ErrIdentityNotFound
+	}
+
+	if !u.HasPassword() && len(identities) == 1 {
+		return ErrLastLoginMethod
+	}
+
+	if err := s.repository.DeleteIdentity(ctx, u.ID, provider); err != nil {
+		return wrapServiceErr("unlink identity", err)
+	}
+
+	return nil
+}
+
+func (s *Service) reauthenticate(ctx context.Context, u *User, req ReauthRequest) error {
+	switch {
+	case req.Password != "":
+		if !u.HasPassword() {
+			return ErrReauthFailed
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(req.Password)); err != nil {
+			return ErrReauthFailed
+		}
+		return nil
+
+	case req.EmailCode != "":
+		if err := s.tokenService.ConsumeEmailLoginCode(ctx, u.ID, strings.TrimSpace(req.EmailCode), emailCodeMaxAttempts); err != nil {
+			if errors.Is(err, token.ErrTokenInvalid) {
+				return ErrReauthFailed
+			}
+			return wrapServiceErr("reauthenticate email code", err)
+		}
+		return nil
+
+	default:
+		return ErrReauthRequired
+	}
+}
+
 func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*User, error) {
 	u, err := s.repository.FindByID(ctx, id)
 	if err != nil {
@@ -83,6 +304,34 @@ func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*User, error) {
 	return u, nil
 }
 
+// RoleOf devuelve el rol actual del usuario.
+func (s *Service) RoleOf(ctx context.Context, id uuid.UUID) (string, error) {
+	u, err := s.GetByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return u.Role, nil
+}
+
+// SyncAdmins aplica la lista de administradores configurada (ADMIN_EMAILS).
+// Sin lista no toca los roles existentes.
+func (s *Service) SyncAdmins(ctx context.Context, emails []string) (promoted, demoted int64, err error) {
+	if len(emails) == 0 {
+		return 0, 0, nil
+	}
+
+	normalized := make([]string, 0, len(emails))
+	for _, e := range emails {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(e)))
+	}
+
+	promoted, demoted, err = s.repository.SyncAdmins(ctx, normalized)
+	if err != nil {
+		return 0, 0, wrapServiceErr("sync admins", err)
+	}
+	return promoted, demoted, nil
+}
+
 func (s *Service) GetByEmail(ctx context.Context, email string) (*User, error) {
 	u, err := s.repository.FindByEmail(ctx, email)
 	if err != nil {
@@ -107,6 +356,29 @@ func (s *Service) IncrementStampsCounter(ctx context.Context, id uuid.UUID) (int
 	return n, nil
 }
 
+func (s *Service) AddStamps(ctx context.Context, id uuid.UUID, n int) (int, error) {
+	total, err := s.repository.AddStamps(ctx, id, n)
+	if err != nil {
+		return 0, wrapServiceErr("add stamps", err)
+	}
+	return total, nil
+}
+
+func (s *Service) DebitStamps(ctx context.Context, id uuid.UUID, n int) (int, error) {
+	debited, err := s.repository.DebitStamps(ctx, id, n)
+	if err != nil {
+		return 0, wrapServiceErr("debit stamps", err)
+	}
+	return debited, nil
+}
+
+func (s *Service) SetStampsCounter(ctx context.Context, id uuid.UUID, n int) error {
+	if err := s.repository.SetStampsCounter(ctx, id, n); err != nil {
+		return wrapServiceErr("set stamps counter", err)
+	}
+	return nil
+}
+
 func (s *Service) ResetStampsCounter(ctx context.Context, id uuid.UUID) (int, error) {
 	n, err := s.repository.ResetStampsCounter(ctx, id)
 	if err != nil {
@@ -122,6 +394,17 @@ func (s *Service) UnlockUser(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// ResetLoginAttempts vuelve a cero el backoff tras un login completo.
+func (s *Service) ResetLoginAttempts(ctx context.Context, u *User) error {
+	if u.LoginAttempt == 0 && u.LockedUntil.IsZero() {
+		return nil
+	}
+	if err := s.repository.UnlockUser(ctx, u.ID); err != nil {
+		return wrapServiceErr("reset login attempts", err)
+	}
+	return nil
+}
+
 func (s *Service) CheckAndUnlockIfExpired(ctx context.Context, userID uuid.UUID) (bool, error) {
 	user, err := s.repository.FindByID(ctx, userID)
 	if err != nil {
@@ -132,7 +415,7 @@ func (s *Service) CheckAndUnlockIfExpired(ctx context.Context, userID uuid.UUID)
 
 		if !user.LockedUntil.IsZero() {
 
-			err := s.repository.UnlockUser(ctx, user.ID)
+			err := s.repository.ClearLock(ctx, user.ID)
 			if err != nil {
 				return false, wrapServiceErr("check and unlock", err)
 			}
@@ -150,6 +433,15 @@ func (s *Service) UpdatePasswordByRecovery(ctx context.Context, req UserRecovery
 		return nil, &validations.ValidationError{Fields: fields}
 	}
 
+	current, err := s.repository.FindByID(ctx, req.UserID)
+	if err != nil {
+		return nil, wrapServiceErr("update password by recovery find user", err)
+	}
+
+	if err := s.checkNewPassword(ctx, "Password", strings.TrimSpace(req.Password), current, current.Email, current.Name); err != nil {
+		return nil, err
+	}
+
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(strings.TrimSpace(req.Password)), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
@@ -177,6 +469,15 @@ func (s *Service) UpdatePasswordByRecovery(ctx context.Context, req UserRecovery
 			return wrapServiceErr("update password by recovery revoke token", err)
 		}
 
+		if err := txUserRepo.AddPasswordHistory(ctx, user.ID, string(passwordHash), s.passwordPolicy.HistorySize); err != nil {
+			return wrapServiceErr("update password by recovery history", err)
+		}
+
+		// El link de recuperación llegó al email, así que queda verificado.
+		if err := txUserRepo.MarkEmailVerified(ctx, user.ID, time.Now()); err != nil {
+			return wrapServiceErr("update password by recovery verify email", err)
+		}
+
 		return nil
 	})
 
@@ -192,11 +493,15 @@ func (s *Service) UpdatePassword(ctx context.Context, userId uuid.UUID, req User
 		return nil, &validations.ValidationError{Fields: fields}
 	}
 
-	_, err := s.repository.FindByID(ctx, userId)
+	current, err := s.repository.FindByID(ctx, userId)
 	if err != nil {
 		return nil, wrapServiceErr("update password find user", err)
 	}
 
+	if err := s.checkNewPassword(ctx, "NewPassword", strings.TrimSpace(req.NewPassword), current, current.Email, current.Name); err != nil {
+		return nil, err
+	}
+
 	passwordHash, err := bcrypt.GenerateFromPassword([]byte(strings.TrimSpace(req.NewPassword)), bcrypt.DefaultCost)
 
 	if err != nil {
@@ -207,6 +512,10 @@ func (s *Service) UpdatePassword(ctx context.Context, userId uuid.UUID, req User
 	if err != nil {
 		return nil, wrapServiceErr("update password", err)
 	}
+
+	if err := s.repository.AddPasswordHistory(ctx, userId, string(passwordHash), s.passwordPolicy.HistorySize); err != nil {
+		return nil, wrapServiceErr("update password history", err)
+	}
 	return u, nil
 }
 
@@ -237,6 +546,287 @@ func (s *Service) Update(ctx context.Context, userId uuid.UUID, req UserUpdate)
 	return u, nil
 }
 
+// StartTOTPEnrollment genera un secreto nuevo y devuelve la URI para el QR.
+// El 2FA queda inactivo hasta que el usuario confirme con un código válido.
+func (s *Service) StartTOTPEnrollment(ctx context.Context, userID uuid.UUID) (*TOTPEnrollmentResponse, error) {
+	u, err := s.repository.FindByID(ctx, userID)
+	if err != nil {
+		return nil, wrapServiceErr("start totp enrollment find user", err)
+	}
+
+	if u.TOTPEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, wrapServiceErr("start totp enrollment secret", err)
+	}
+
+	if err := s.repository.SetTOTPSecret(ctx, u.ID, secret); err != nil {
+		return nil, wrapServiceErr("start totp enrollment", err)
+	}
+
+	return &TOTPEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(totpIssuer, u.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTPEnrollment activa el 2FA y devuelve los códigos de recuperación en claro.
+func (s *Service) ConfirmTOTPEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	u, err := s.repository.FindByID(ctx, userID)
+	if err != nil {
+		return nil, wrapServiceErr("confirm totp enrollment find user", err)
+	}
+
+	if u.TOTPEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	if u.TOTPSecret == nil {
+		return nil, ErrMFANotEnrolled
+	}
+
+	step, ok := totp.Validate(*u.TOTPSecret, code, time.Now())
+	if !ok {
+		return nil, ErrMFAInvalidCode
+	}
+
+	codes, err := totp.GenerateRecoveryCodes(recoveryCodesCount)
+	if err != nil {
+		return nil, wrapServiceErr("confirm totp enrollment recovery codes", err)
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.repository.WithTx(tx).EnableTOTP(ctx, u.ID, step); err != nil {
+			return err
+		}
+		return s.tokenService.WithTx(tx).ReplaceRecoveryCodes(ctx, u.ID, codes)
+	})
+	if err != nil {
+		return nil, wrapServiceErr("confirm totp enrollment", err)
+	}
+
+	return codes, nil
+}
+
+// VerifySecondFactor valida un código TOTP o, en su defecto, consume un código
+// de recuperación.
+func (s *Service) VerifySecondFactor(ctx context.Context, userID uuid.UUID, req MFACodeRequest) error {
+	if fields, ok := s.validator.ValidateStruct(req); !ok {
+		return &validations.ValidationError{Fields: fields}
+	}
+
+	if req.Code == "" && req.RecoveryCode == "" {
+		return &validations.ValidationError{Fields: map[string]string{"code": "El código es requerido"}}
+	}
+
+	u, err := s.repository.FindByID(ctx, userID)
+	if err != nil {
+		return wrapServiceErr("verify second factor find user", err)
+	}
+
+	if !u.TOTPEnabled || u.TOTPSecret == nil {
+		return ErrMFANotEnrolled
+	}
+
+	if req.Code != "" {
+		step, ok := totp.Validate(*u.TOTPSecret, req.Code, time.Now())
+		if !ok {
+			return ErrMFAInvalidCode
+		}
+
+		consumed, err := s.repository.ConsumeTOTPStep(ctx, u.ID, step)
+		if err != nil {
+			return wrapServiceErr("verify second factor", err)
+		}
+		if !consumed {
+			return ErrMFAInvalidCode
+		}
+		return nil
+	}
+
+	if err := s.tokenService.ConsumeRecoveryCode(ctx, u.ID, totp.NormalizeRecoveryCode(req.RecoveryCode)); err != nil {
+		if errors.Is(err, token.ErrTokenInvalid) {
+			return ErrMFAInvalidCode
+		}
+		return wrapServiceErr("verify second factor recovery code", err)
+	}
+
+	return nil
+}
+
+// DisableTOTP desactiva el 2FA previa verificación del segundo factor.
+func (s *Service) DisableTOTP(ctx context.Context, userID uuid.UUID, req MFACodeRequest) error {
+	u, err := s.repository.FindByID(ctx, userID)
+	if err != nil {
+		return wrapServiceErr("disable totp find user", err)
+	}
+
+	if u.IsAdmin() {
+		return ErrMFARequiredForAdmin
+	}
+
+	if err := s.VerifySecondFactor(ctx, userID, req); err != nil {
+		return err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.repository.WithTx(tx).DisableTOTP(ctx, u.ID); err != nil {
+			return err
+		}
+		return s.tokenService.WithTx(tx).RevokeRecoveryCodes(ctx, u.ID)
+	})
+	if err != nil {
+		return wrapServiceErr("disable totp", err)
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes reemplaza los códigos de recuperación vigentes.
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, req MFACodeRequest) ([]string, error) {
+	if err := s.VerifySecondFactor(ctx, userID, req); err != nil {
+		return nil, err
+	}
+
+	codes, err := totp.GenerateRecoveryCodes(recoveryCodesCount)
+	if err != nil {
+		return nil, wrapServiceErr("regenerate recovery codes", err)
+	}
+
+	if err := s.tokenService.ReplaceRecoveryCodes(ctx, userID, codes); err != nil {
+		return nil, wrapServiceErr("regenerate recovery codes", err)
+	}
+
+	return codes, nil
+}
+
+// RequestEmailLoginCode envía un código de acceso de un solo uso. Si el email
+// no existe no hace nada: el handler responde igual para no revelar cuentas.
+func (s *Service) RequestEmailLoginCode(ctx context.Context, req EmailCodeRequest) error {
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+
+	if fields, ok := s.validator.ValidateStruct(req); !ok {
+		return &validations.ValidationError{Fields: fields}
+	}
+
+	u, err := s.repository.FindByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return wrapServiceErr("request email login code find user", err)
+	}
+
+	code, err := generateNumericCode(6)
+	if err != nil {
+		return wrapServiceErr("request email login code generate", err)
+	}
+
+	if err := s.tokenService.CreateEmailLoginCode(ctx, u.ID, code, time.Now().Add(emailCodeTTL)); err != nil {
+		return wrapServiceErr("request email login code", err)
+	}
+
+	q := url.Values{}
+	q.Set("email", u.Email)
+	q.Set("code", code)
+	loginURL := emailCodeLoginURL + "?" + q.Encode()
+
+	if err := s.mailer.SendLoginCodeEmail(ctx, u.Email, code, loginURL); err != nil {
+		return wrapServiceErr("request email login code send", err)
+	}
+
+	return nil
+}
+
+// VerifyEmailLoginCode canjea el código por el usuario. El llamador decide si
+// corresponde pedir segundo factor antes de emitir tokens.
+func (s *Service) VerifyEmailLoginCode(ctx context.Context, req EmailCodeVerifyRequest) (*User, error) {
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	req.Code = strings.TrimSpace(req.Code)
+
+	if fields, ok := s.validator.ValidateStruct(req); !ok {
+		return nil, &validations.ValidationError{Fields: fields}
+	}
+
+	u, err := s.repository.FindByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrEmailCodeInvalid
+		}
+		return nil, wrapServiceErr("verify email login code find user", err)
+	}
+
+	if err := s.tokenService.ConsumeEmailLoginCode(ctx, u.ID, req.Code, emailCodeMaxAttempts); err != nil {
+		if errors.Is(err, token.ErrTokenInvalid) {
+			return u, ErrEmailCodeInvalid
+		}
+		return nil, wrapServiceErr("verify email login code", err)
+	}
+
+	if !u.EmailVerified() {
+		if err := s.repository.MarkEmailVerified(ctx, u.ID, time.Now()); err != nil {
+			return nil, wrapServiceErr("verify email login code mark verified", err)
+		}
+	}
+
+	return u, nil
+}
+
+func generateNumericCode(digits int) (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < digits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", digits, n.Int64()), nil
+}
+
+// checkNewPassword aplica la política de contraseñas. Los problemas vuelven
+// como ValidationError sobre field; current es nil al registrarse.
+func (s *Service) checkNewPassword(ctx context.Context, field, pw string, current *User, email, name string) error {
+	problems := s.passwordPolicy.Validate(pw, email, name)
+
+	if s.breached != nil {
+		breached, err := s.breached.IsBreached(ctx, pw)
+		if err != nil {
+			// Un dataset ilegible no debe impedir registrarse o cambiar la contraseña.
+			slog.WarnContext(ctx, "no se pudo consultar contraseñas filtradas", "error", err)
+		} else if breached {
+			problems = append(problems, "Esta contraseña apareció en filtraciones conocidas, elegí otra")
+		}
+	}
+
+	if current != nil && s.passwordPolicy.HistorySize > 0 {
+		hashes, err := s.repository.RecentPasswordHashes(ctx, current.ID, s.passwordPolicy.HistorySize)
+		if err != nil {
+			return wrapServiceErr("check password history", err)
+		}
+		if current.HasPassword() {
+			hashes = append(hashes, current.Password)
+		}
+
+		for _, h := range hashes {
+			if bcrypt.CompareHashAndPassword([]byte(h), []byte(pw)) == nil {
+				problems = append(problems, "No podés reutilizar una de tus últimas contraseñas")
+				break
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &validations.ValidationError{Fields: map[string]string{field: strings.Join(problems, ". ")}}
+	}
+	return nil
+}
+
 func wrapServiceErr(action string, err error) error {
 	if err == nil {
 		return nil
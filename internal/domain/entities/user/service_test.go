@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/sebaactis/powermix-back-mobile/internal/security/oauth"
 	"github.com/sebaactis/powermix-back-mobile/internal/validations"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
@@ -64,3 +66,96 @@ func TestService_Update_nilName_returnsValidationError(t *testing.T) {
 		t.Fatalf("expected 'El nombre es requerido', got %q", msg)
 	}
 }
+
+func TestUser_RequiresMFA(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		user User
+		want bool
+	}{
+		{"usuario sin 2FA", User{Role: RoleUser}, false},
+		{"usuario con 2FA", User{Role: RoleUser, TOTPEnabled: true}, true},
+		{"admin sin enrolar", User{Role: RoleAdmin}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.user.RequiresMFA(); got != tt.want {
+			t.Fatalf("%s: RequiresMFA() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateNumericCode(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < 50; i++ {
+		code, err := generateNumericCode(6)
+		if err != nil {
+			t.Fatalf("generateNumericCode: %v", err)
+		}
+		if len(code) != 6 {
+			t.Fatalf("len(%q) = %d, want 6", code, len(code))
+		}
+		for _, c := range code {
+			if c < '0' || c > '9' {
+				t.Fatalf("code %q contains non-digit", code)
+			}
+		}
+	}
+}
+
+func TestService_reauthenticate(t *testing.T) {
+	t.Parallel()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secreto123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+
+	s := &Service{}
+	withPassword := &User{Password: string(hash)}
+	oauthOnly := &User{}
+
+	tests := []struct {
+		name string
+		user *User
+		req  ReauthRequest
+		want error
+	}{
+		{"sin datos", withPassword, ReauthRequest{}, ErrReauthRequired},
+		{"contraseña correcta", withPassword, ReauthRequest{Password: "secreto123"}, nil},
+		{"contraseña incorrecta", withPassword, ReauthRequest{Password: "otra"}, ErrReauthFailed},
+		{"cuenta sin contraseña", oauthOnly, ReauthRequest{Password: "secreto123"}, ErrReauthFailed},
+	}
+
+	for _, tt := range tests {
+		got := s.reauthenticate(context.Background(), tt.user, tt.req)
+		if !errors.Is(got, tt.want) {
+			t.Fatalf("%s: reauthenticate() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestService_mergeOAuthIdentity_requiresVerifiedEmails(t *testing.T) {
+	t.Parallel()
+
+	s := &Service{}
+	verified := &oauth.OAuthUserInfo{Provider: "google", ProviderID: "123", Email: "ana@example.com", EmailVerified: true}
+
+	tests := []struct {
+		name string
+		user *User
+		info *oauth.OAuthUserInfo
+	}{
+		{"cuenta sin email verificado", &User{Email: "ana@example.com"}, verified},
+		{"proveedor sin email verificado", &User{Email: "ana@example.com"}, &oauth.OAuthUserInfo{Provider: "google", ProviderID: "123", Email: "ana@example.com"}},
+	}
+
+	for _, tt := range tests {
+		if _, err := s.mergeOAuthIdentity(context.Background(), tt.user, tt.info); !errors.Is(err, ErrIdentityLinkRequired) {
+			t.Fatalf("%s: mergeOAuthIdentity() = %v, want ErrIdentityLinkRequired", tt.name, err)
+		}
+	}
+}
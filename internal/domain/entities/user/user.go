@@ -4,8 +4,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/loginevent"
 )
 
+// OAuthProvider y OAuthID quedan solo por compatibilidad: las identidades
+// externas viven en user_identities (ver Identity).
 type User struct {
 	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	Name          string    `gorm:"not null"`
@@ -16,6 +19,49 @@ type User struct {
 	LockedUntil   time.Time `json:"locked_until" gorm:"column:locked_until;default:null"`
 	OAuthProvider string    `gorm:"column:oauth_provider;type:varchar(20);default:null"`
 	OAuthID       string    `gorm:"column:oauth_id;type:varchar(100);default:null"`
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	Role          string    `gorm:"type:varchar(20);not null;default:USER"`
+	TOTPSecret    *string   `gorm:"column:totp_secret;type:varchar(64);default:null"`
+	TOTPEnabled   bool      `gorm:"column:totp_enabled;default:false"`
+	TOTPLastStep  int64     `gorm:"column:totp_last_step;default:0"`
+	// EmailVerifiedAt se completa cuando el usuario demuestra que controla el
+	// email: código de login, reset de contraseña o alta con un proveedor que
+	// lo verificó.
+	EmailVerifiedAt *time.Time `gorm:"column:email_verified_at;default:null"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+const (
+	RoleUser  = "USER"
+	RoleAdmin = "ADMIN"
+
+	lockoutThreshold = 5
+	lockoutBaseDelay = time.Minute
+	lockoutMaxDelay  = 24 * time.Hour
+)
+
+// LockoutDuration devuelve cuánto se bloquea la cuenta tras n intentos
+// fallidos: 1 minuto al quinto y el doble por cada fallo siguiente.
+func LockoutDuration(attempts int) time.Duration {
+	return loginevent.Backoff(attempts, lockoutThreshold, lockoutBaseDelay, lockoutMaxDelay)
+}
+
+// HasPassword distingue las cuentas creadas solo con un proveedor externo.
+func (u *User) HasPassword() bool {
+	return u.Password != ""
+}
+
+// EmailVerified indica si el dueño de la cuenta demostró controlar el email.
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// RequiresMFA indica si el login debe pedir segundo factor. Los administradores
+// lo necesitan siempre: si todavía no enrolaron, deben hacerlo antes de entrar.
+func (u *User) RequiresMFA() bool {
+	return u.TOTPEnabled || u.IsAdmin()
 }
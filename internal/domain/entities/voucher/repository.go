@@ -114,6 +114,82 @@ func (r *Repository) MarkUsed(ctx context.Context, id uuid.UUID, now time.Time)
 	return nil
 }
 
+// RevokeLatestActive anula el último voucher activo asignado al usuario desde
+// since. Devuelve nil si no hay ninguno.
+func (r *Repository) RevokeLatestActive(ctx context.Context, userID uuid.UUID, since time.Time) (*Voucher, error) {
+	var v Voucher
+
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND is_assigned = ? AND status = ? AND assigned_date >= ?", userID, true, VoucherStatusActive, since).
+		Order("assigned_date DESC").
+		First(&v).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapVoucherRepoErr(ctx, "revoke latest active", err)
+	}
+
+	if err := r.db.WithContext(ctx).
+		Model(&v).
+		Update("status", VoucherStatusRevoked).Error; err != nil {
+		return nil, mapVoucherRepoErr(ctx, "revoke latest active save", err)
+	}
+	v.Status = VoucherStatusRevoked
+
+	return &v, nil
+}
+
+// FlagLatestUsed marca para revisión el último voucher usado del usuario
+// asignado desde since. Devuelve nil si no hay ninguno.
+func (r *Repository) FlagLatestUsed(ctx context.Context, userID uuid.UUID, since time.Time, reason string, now time.Time) (*Voucher, error) {
+	var v Voucher
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ? AND assigned_date >= ? AND flagged_at IS NULL", userID, VoucherStatusUsed, since).
+		Order("assigned_date DESC").
+		First(&v).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, mapVoucherRepoErr(ctx, "flag latest used", err)
+	}
+
+	if err := r.db.WithContext(ctx).
+		Model(&v).
+		Updates(map[string]any{"flagged_at": &now, "flag_reason": reason}).Error; err != nil {
+		return nil, mapVoucherRepoErr(ctx, "flag latest used save", err)
+	}
+
+	return &v, nil
+}
+
+// RevokeOrFlag anula el voucher si sigue activo. Si ya se usó no se puede
+// anular, así que queda marcado para revisión con reason. Devuelve true si
+// llegó a anularlo.
+func (r *Repository) RevokeOrFlag(ctx context.Context, id uuid.UUID, reason string, now time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&Voucher{}).
+		Where("id = ? AND status = ?", id, VoucherStatusActive).
+		Update("status", VoucherStatusRevoked)
+	if res.Error != nil {
+		return false, mapVoucherRepoErr(ctx, "revoke or flag", res.Error)
+	}
+	if res.RowsAffected > 0 {
+		return true, nil
+	}
+
+	if err := r.db.WithContext(ctx).
+		Model(&Voucher{}).
+		Where("id = ? AND status = ? AND flagged_at IS NULL", id, VoucherStatusUsed).
+		Updates(map[string]any{"flagged_at": &now, "flag_reason": reason}).Error; err != nil {
+		return false, mapVoucherRepoErr(ctx, "revoke or flag save", err)
+	}
+	return false, nil
+}
+
 func (r *Repository) TouchChecked(ctx context.Context, id uuid.UUID, now time.Time) error {
 	if err := r.db.WithContext(ctx).
 		Model(&Voucher{}).
@@ -124,6 +124,14 @@ func (s *Service) CheckUsedVouchers(ctx context.Context, batch int) error {
 	return nil
 }
 
+func (s *Service) RevokeLatestActive(ctx context.Context, userID uuid.UUID, since time.Time) (*Voucher, error) {
+	return s.repo.RevokeLatestActive(ctx, userID, since)
+}
+
+func (s *Service) FlagLatestUsed(ctx context.Context, userID uuid.UUID, since time.Time, reason string) (*Voucher, error) {
+	return s.repo.FlagLatestUsed(ctx, userID, since, reason, time.Now())
+}
+
 // -------- PRIVADO -------- //
 
 func (s *Service) GetVoucherImageUrl(storagePath string) string {
@@ -11,6 +11,9 @@ type VoucherStatus string
 const (
 	VoucherStatusActive VoucherStatus = "ACTIVE"
 	VoucherStatusUsed   VoucherStatus = "USED"
+	// VoucherStatusRevoked es un voucher anulado porque el pago que lo
+	// generó fue devuelto o desconocido.
+	VoucherStatusRevoked VoucherStatus = "REVOKED"
 )
 
 type Voucher struct {
@@ -25,4 +28,8 @@ type Voucher struct {
 
 	UsedAt        *time.Time `gorm:"column:used_at;default:null" json:"used_at"`
 	LastCheckedAt *time.Time `gorm:"column:last_checked_at;default:null" json:"last_checked_at"`
+
+	// FlaggedAt marca vouchers ya usados cuyo pago se revirtió, para revisión manual.
+	FlaggedAt  *time.Time `gorm:"column:flagged_at;default:null" json:"flagged_at,omitempty"`
+	FlagReason *string    `gorm:"column:flag_reason;type:varchar(100)" json:"flag_reason,omitempty"`
 }
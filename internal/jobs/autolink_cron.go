@@ -0,0 +1,108 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/mppayment"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/proof"
+)
+
+// AutoLinkCron toma los pagos aprobados del espejo y los atribuye a los
+// usuarios que vincularon su DNI o tarjeta.
+type AutoLinkCron struct {
+	payments *mppayment.Service
+	proofs   *proof.Service
+	spec     string
+	lookback time.Duration
+	timeout  time.Duration
+
+	mu      sync.Mutex
+	running bool
+
+	cron *cron.Cron
+}
+
+func NewAutoLinkCron(payments *mppayment.Service, proofs *proof.Service, spec string, lookback, timeout time.Duration) *AutoLinkCron {
+	if spec == "" {
+		spec = "@every 5m"
+	}
+	if lookback <= 0 {
+		lookback = 2 * time.Hour
+	}
+	if timeout <= 0 {
+		timeout = 2 * time.Minute
+	}
+
+	return &AutoLinkCron{
+		payments: payments,
+		proofs:   proofs,
+		spec:     spec,
+		lookback: lookback,
+		timeout:  timeout,
+	}
+}
+
+func (ac *AutoLinkCron) Start() error {
+	ac.cron = cron.New()
+
+	_, err := ac.cron.AddFunc(ac.spec, func() {
+		ac.runOnce()
+	})
+
+	if err != nil {
+		return err
+	}
+
+	ac.cron.Start()
+	log.Printf("[cron] autolink job started spec=%s lookback=%s timeout=%s", ac.spec, ac.lookback, ac.timeout)
+	return nil
+}
+
+func (ac *AutoLinkCron) Stop() {
+	if ac.cron != nil {
+		ctx := ac.cron.Stop()
+		<-ctx.Done()
+		log.Printf("[cron] autolink job stopped")
+	}
+}
+
+func (ac *AutoLinkCron) runOnce() {
+	ac.mu.Lock()
+
+	if ac.running {
+		ac.mu.Unlock()
+		log.Printf("[cron] autolink job skipped (previous run still running)")
+		return
+	}
+
+	ac.running = true
+	ac.mu.Unlock()
+
+	defer func() {
+		ac.mu.Lock()
+		ac.running = false
+		ac.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), ac.timeout)
+	defer cancel()
+
+	payments, err := ac.payments.ApprovedSince(ctx, time.Now().Add(-ac.lookback))
+	if err != nil {
+		log.Printf("[cron] autolink job failed: %v", err)
+		return
+	}
+
+	res, err := ac.proofs.AutoAttribute(ctx, payments)
+	if err != nil {
+		log.Printf("[cron] autolink job failed: %v", err)
+	}
+
+	if res.Linked > 0 || res.Suggested > 0 {
+		log.Printf("[cron] autolink job linked=%d suggested=%d", res.Linked, res.Suggested)
+	}
+}
@@ -0,0 +1,93 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/mppayment"
+)
+
+// MPSyncCron mantiene actualizado el espejo local de pagos de Mercado Pago.
+type MPSyncCron struct {
+	payments *mppayment.Service
+	spec     string
+	timeout  time.Duration
+
+	mu      sync.Mutex
+	running bool
+
+	cron *cron.Cron
+}
+
+func NewMPSyncCron(payments *mppayment.Service, spec string, timeout time.Duration) *MPSyncCron {
+	if spec == "" {
+		spec = "@every 1m"
+	}
+	if timeout <= 0 {
+		timeout = 50 * time.Second
+	}
+
+	return &MPSyncCron{
+		payments: payments,
+		spec:     spec,
+		timeout:  timeout,
+	}
+}
+
+func (mc *MPSyncCron) Start() error {
+	mc.cron = cron.New()
+
+	_, err := mc.cron.AddFunc(mc.spec, func() {
+		mc.runOnce()
+	})
+
+	if err != nil {
+		return err
+	}
+
+	mc.cron.Start()
+	log.Printf("[cron] mp sync job started spec=%s timeout=%s", mc.spec, mc.timeout)
+	return nil
+}
+
+func (mc *MPSyncCron) Stop() {
+	if mc.cron != nil {
+		ctx := mc.cron.Stop()
+		<-ctx.Done()
+		log.Printf("[cron] mp sync job stopped")
+	}
+}
+
+func (mc *MPSyncCron) runOnce() {
+	mc.mu.Lock()
+
+	if mc.running {
+		mc.mu.Unlock()
+		log.Printf("[cron] mp sync job skipped (previous run still running)")
+		return
+	}
+
+	mc.running = true
+	mc.mu.Unlock()
+
+	defer func() {
+		mc.mu.Lock()
+		mc.running = false
+		mc.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), mc.timeout)
+	defer cancel()
+
+	n, err := mc.payments.Sync(ctx)
+	if err != nil {
+		log.Printf("[cron] mp sync job failed after %d payments: %v", n, err)
+		return
+	}
+	if n > 0 {
+		log.Printf("[cron] mp sync job synced %d payments", n)
+	}
+}
@@ -0,0 +1,121 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/prode"
+)
+
+// ProdeFeedCron importa los fixtures del feed deportivo cada importSpec y
+// consulta los resultados de los partidos en juego cada pollSpec.
+type ProdeFeedCron struct {
+	feed       *prode.FeedSync
+	importSpec string
+	pollSpec   string
+	timeout    time.Duration
+
+	mu      sync.Mutex
+	running bool
+
+	cron *cron.Cron
+}
+
+func NewProdeFeedCron(feed *prode.FeedSync, importSpec, pollSpec string, timeout time.Duration) *ProdeFeedCron {
+	if importSpec == "" {
+		importSpec = "@every 6h"
+	}
+	if pollSpec == "" {
+		pollSpec = "@every 5m"
+	}
+	if timeout <= 0 {
+		timeout = 2 * time.Minute
+	}
+
+	return &ProdeFeedCron{
+		feed:       feed,
+		importSpec: importSpec,
+		pollSpec:   pollSpec,
+		timeout:    timeout,
+	}
+}
+
+func (fc *ProdeFeedCron) Start() error {
+	fc.cron = cron.New()
+
+	if _, err := fc.cron.AddFunc(fc.importSpec, func() {
+		fc.run("import", fc.importFixtures)
+	}); err != nil {
+		return err
+	}
+
+	if _, err := fc.cron.AddFunc(fc.pollSpec, func() {
+		fc.run("poll", fc.pollResults)
+	}); err != nil {
+		return err
+	}
+
+	fc.cron.Start()
+	log.Printf("[cron] prode feed job started import=%s poll=%s timeout=%s", fc.importSpec, fc.pollSpec, fc.timeout)
+
+	// Los fixtures se importan también al arrancar para no esperar el primer
+	// ciclo.
+	go fc.run("import", fc.importFixtures)
+	return nil
+}
+
+func (fc *ProdeFeedCron) Stop() {
+	if fc.cron != nil {
+		ctx := fc.cron.Stop()
+		<-ctx.Done()
+		log.Printf("[cron] prode feed job stopped")
+	}
+}
+
+// run evita que una importación y un poll se pisen entre sí.
+func (fc *ProdeFeedCron) run(name string, fn func(context.Context)) {
+	fc.mu.Lock()
+
+	if fc.running {
+		fc.mu.Unlock()
+		log.Printf("[cron] prode feed %s skipped (previous run still running)", name)
+		return
+	}
+
+	fc.running = true
+	fc.mu.Unlock()
+
+	defer func() {
+		fc.mu.Lock()
+		fc.running = false
+		fc.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), fc.timeout)
+	defer cancel()
+
+	fn(ctx)
+}
+
+func (fc *ProdeFeedCron) importFixtures(ctx context.Context) {
+	res, err := fc.feed.ImportFixtures(ctx)
+	if err != nil {
+		log.Printf("[cron] prode feed import failed: %v", err)
+	}
+	if res.Created > 0 || res.Updated > 0 {
+		log.Printf("[cron] prode feed import created=%d updated=%d", res.Created, res.Updated)
+	}
+}
+
+func (fc *ProdeFeedCron) pollResults(ctx context.Context) {
+	res, err := fc.feed.PollResults(ctx)
+	if err != nil {
+		log.Printf("[cron] prode feed poll failed: %v", err)
+	}
+	if res.Recorded > 0 {
+		log.Printf("[cron] prode feed poll recorded=%d extraTime=%d", res.Recorded, res.ExtraTime)
+	}
+}
@@ -0,0 +1,101 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/prode"
+)
+
+// ProdeLifecycleCron abre y cierra los partidos del prode según el horario y
+// opcionalmente evalúa los que ya tienen resultado.
+type ProdeLifecycleCron struct {
+	prode      *prode.Service
+	spec       string
+	openLead   time.Duration
+	autoSettle bool
+	timeout    time.Duration
+
+	mu      sync.Mutex
+	running bool
+
+	cron *cron.Cron
+}
+
+func NewProdeLifecycleCron(prodeService *prode.Service, spec string, openLead time.Duration, autoSettle bool, timeout time.Duration) *ProdeLifecycleCron {
+	if spec == "" {
+		spec = "@every 1m"
+	}
+	if openLead <= 0 {
+		openLead = 72 * time.Hour
+	}
+	if timeout <= 0 {
+		timeout = 2 * time.Minute
+	}
+
+	return &ProdeLifecycleCron{
+		prode:      prodeService,
+		spec:       spec,
+		openLead:   openLead,
+		autoSettle: autoSettle,
+		timeout:    timeout,
+	}
+}
+
+func (pc *ProdeLifecycleCron) Start() error {
+	pc.cron = cron.New()
+
+	_, err := pc.cron.AddFunc(pc.spec, func() {
+		pc.runOnce()
+	})
+
+	if err != nil {
+		return err
+	}
+
+	pc.cron.Start()
+	log.Printf("[cron] prode lifecycle job started spec=%s openLead=%s autoSettle=%t timeout=%s", pc.spec, pc.openLead, pc.autoSettle, pc.timeout)
+	return nil
+}
+
+func (pc *ProdeLifecycleCron) Stop() {
+	if pc.cron != nil {
+		ctx := pc.cron.Stop()
+		<-ctx.Done()
+		log.Printf("[cron] prode lifecycle job stopped")
+	}
+}
+
+func (pc *ProdeLifecycleCron) runOnce() {
+	pc.mu.Lock()
+
+	if pc.running {
+		pc.mu.Unlock()
+		log.Printf("[cron] prode lifecycle job skipped (previous run still running)")
+		return
+	}
+
+	pc.running = true
+	pc.mu.Unlock()
+
+	defer func() {
+		pc.mu.Lock()
+		pc.running = false
+		pc.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), pc.timeout)
+	defer cancel()
+
+	res, err := pc.prode.AdvanceMatches(ctx, pc.openLead, pc.autoSettle)
+	if err != nil {
+		log.Printf("[cron] prode lifecycle job failed: %v", err)
+	}
+
+	if res.Opened > 0 || res.Closed > 0 || res.Settled > 0 {
+		log.Printf("[cron] prode lifecycle job opened=%d closed=%d settled=%d", res.Opened, res.Closed, res.Settled)
+	}
+}
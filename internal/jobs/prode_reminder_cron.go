@@ -0,0 +1,99 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/prode"
+)
+
+// ProdeReminderCron avisa a los usuarios que todavía no pronosticaron los
+// partidos que están por cerrar.
+type ProdeReminderCron struct {
+	prode   *prode.Service
+	spec    string
+	lead    time.Duration
+	timeout time.Duration
+
+	mu      sync.Mutex
+	running bool
+
+	cron *cron.Cron
+}
+
+func NewProdeReminderCron(prodeService *prode.Service, spec string, lead, timeout time.Duration) *ProdeReminderCron {
+	if spec == "" {
+		spec = "@every 10m"
+	}
+	if lead <= 0 {
+		lead = 3 * time.Hour
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+
+	return &ProdeReminderCron{
+		prode:   prodeService,
+		spec:    spec,
+		lead:    lead,
+		timeout: timeout,
+	}
+}
+
+func (rc *ProdeReminderCron) Start() error {
+	rc.cron = cron.New()
+
+	_, err := rc.cron.AddFunc(rc.spec, func() {
+		rc.runOnce()
+	})
+
+	if err != nil {
+		return err
+	}
+
+	rc.cron.Start()
+	log.Printf("[cron] prode reminder job started spec=%s lead=%s timeout=%s", rc.spec, rc.lead, rc.timeout)
+	return nil
+}
+
+func (rc *ProdeReminderCron) Stop() {
+	if rc.cron != nil {
+		ctx := rc.cron.Stop()
+		<-ctx.Done()
+		log.Printf("[cron] prode reminder job stopped")
+	}
+}
+
+func (rc *ProdeReminderCron) runOnce() {
+	rc.mu.Lock()
+
+	if rc.running {
+		rc.mu.Unlock()
+		log.Printf("[cron] prode reminder job skipped (previous run still running)")
+		return
+	}
+
+	rc.running = true
+	rc.mu.Unlock()
+
+	defer func() {
+		rc.mu.Lock()
+		rc.running = false
+		rc.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), rc.timeout)
+	defer cancel()
+
+	res, err := rc.prode.SendReminders(ctx, rc.lead)
+	if err != nil {
+		log.Printf("[cron] prode reminder job failed: %v", err)
+	}
+
+	if res.Sent > 0 || res.Failed > 0 {
+		log.Printf("[cron] prode reminder job matches=%d sent=%d failed=%d", res.Matches, res.Sent, res.Failed)
+	}
+}
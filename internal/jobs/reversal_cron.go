@@ -0,0 +1,116 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/mppayment"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/proof"
+)
+
+// ReversalCron revisa el espejo de pagos y anula los comprobantes de pagos
+// devueltos o contracargados que el webhook no haya procesado.
+type ReversalCron struct {
+	payments *mppayment.Service
+	proofs   *proof.Service
+	spec     string
+	lookback time.Duration
+	timeout  time.Duration
+
+	mu      sync.Mutex
+	running bool
+
+	cron *cron.Cron
+}
+
+func NewReversalCron(payments *mppayment.Service, proofs *proof.Service, spec string, lookback, timeout time.Duration) *ReversalCron {
+	if spec == "" {
+		spec = "@every 30m"
+	}
+	if lookback <= 0 {
+		lookback = 7 * 24 * time.Hour
+	}
+	if timeout <= 0 {
+		timeout = 2 * time.Minute
+	}
+
+	return &ReversalCron{
+		payments: payments,
+		proofs:   proofs,
+		spec:     spec,
+		lookback: lookback,
+		timeout:  timeout,
+	}
+}
+
+func (rc *ReversalCron) Start() error {
+	rc.cron = cron.New()
+
+	_, err := rc.cron.AddFunc(rc.spec, func() {
+		rc.runOnce()
+	})
+
+	if err != nil {
+		return err
+	}
+
+	rc.cron.Start()
+	log.Printf("[cron] reversal job started spec=%s lookback=%s timeout=%s", rc.spec, rc.lookback, rc.timeout)
+	return nil
+}
+
+func (rc *ReversalCron) Stop() {
+	if rc.cron != nil {
+		ctx := rc.cron.Stop()
+		<-ctx.Done()
+		log.Printf("[cron] reversal job stopped")
+	}
+}
+
+func (rc *ReversalCron) runOnce() {
+	rc.mu.Lock()
+
+	if rc.running {
+		rc.mu.Unlock()
+		log.Printf("[cron] reversal job skipped (previous run still running)")
+		return
+	}
+
+	rc.running = true
+	rc.mu.Unlock()
+
+	defer func() {
+		rc.mu.Lock()
+		rc.running = false
+		rc.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), rc.timeout)
+	defer cancel()
+
+	payments, err := rc.payments.ReversedSince(ctx, time.Now().Add(-rc.lookback))
+	if err != nil {
+		log.Printf("[cron] reversal job failed: %v", err)
+		return
+	}
+
+	reversed := 0
+	for _, p := range payments {
+		ok, err := rc.proofs.ReversePayment(ctx, strconv.FormatInt(p.ID, 10), p.Status)
+		if err != nil {
+			log.Printf("[cron] reversal job failed paymentID=%d err=%v", p.ID, err)
+			continue
+		}
+		if ok {
+			reversed++
+		}
+	}
+
+	if reversed > 0 {
+		log.Printf("[cron] reversal job reversed %d proofs", reversed)
+	}
+}
@@ -0,0 +1,123 @@
+package middlewares
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+var (
+	trustedMu      sync.RWMutex
+	trustedProxies []*net.IPNet
+)
+
+// SetTrustedProxies configura los proxies (IPs o CIDRs) cuyos headers de
+// reenvío se aceptan. Sin proxies configurados ClientIP ignora los headers y
+// usa la IP de la conexión.
+func SetTrustedProxies(entries []string) error {
+	nets := make([]*net.IPNet, 0, len(entries))
+	for _, e := range entries {
+		if !strings.Contains(e, "/") {
+			ip := net.ParseIP(e)
+			if ip == nil {
+				return fmt.Errorf("proxy confiable inválido: %q", e)
+			}
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, n, err := net.ParseCIDR(e)
+		if err != nil {
+			return fmt.Errorf("proxy confiable inválido: %q", e)
+		}
+		nets = append(nets, n)
+	}
+
+	trustedMu.Lock()
+	trustedProxies = nets
+	trustedMu.Unlock()
+	return nil
+}
+
+func isTrustedProxy(nets []*net.IPNet, host string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP extrae la IP del cliente. Con proxies confiables configurados
+// recorre X-Forwarded-For de derecha a izquierda y devuelve la primera IP
+// que no es un proxy propio, así un cliente no puede falsificarla. Sin
+// proxies configurados devuelve la IP de la conexión, sin puerto.
+// Exportada para que el login y el rate limit usen la misma IP.
+func ClientIP(r *http.Request) string {
+	trustedMu.RLock()
+	nets := trustedProxies
+	trustedMu.RUnlock()
+
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+	if !isTrustedProxy(nets, remote) {
+		return remote
+	}
+
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		hops := strings.Split(fwd, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if hop == "" {
+				continue
+			}
+			if !isTrustedProxy(nets, hop) || i == 0 {
+				return hop
+			}
+		}
+	}
+	if real := strings.TrimSpace(r.Header.Get("X-Real-Ip")); real != "" {
+		return real
+	}
+	return remote
+}
+
+// hasTrustedProxies indica si se configuró TRUSTED_PROXIES.
+func hasTrustedProxies() bool {
+	trustedMu.RLock()
+	defer trustedMu.RUnlock()
+	return len(trustedProxies) > 0
+}
+
+// extractClientIP es la IP que se loguea. Sin proxies configurados respeta
+// X-Forwarded-For sin validar el origen (para Render.com); sirve solo para
+// logs, nunca para identificar al cliente.
+func extractClientIP(r *http.Request) string {
+	if hasTrustedProxies() {
+		return ClientIP(r)
+	}
+
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		// X-Forwarded-For puede ser una lista separada por comas
+		if idx := strings.Index(fwd, ","); idx != -1 {
+			return strings.TrimSpace(fwd[:idx])
+		}
+		return fwd
+	}
+	if real := r.Header.Get("X-Real-Ip"); real != "" {
+		return real
+	}
+	return r.RemoteAddr
+}
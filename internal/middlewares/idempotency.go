@@ -0,0 +1,180 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/sebaactis/powermix-back-mobile/internal/platform/idempotency"
+	"github.com/sebaactis/powermix-back-mobile/internal/utils"
+)
+
+const (
+	idempotencyHeader = "Idempotency-Key"
+	idempotencyTTL    = 24 * time.Hour
+	maxIdempotencyKey = 255
+	// defaultIdempotencyBody es el body máximo que se lee para calcular el hash
+	// cuando la ruta no indica otro límite.
+	defaultIdempotencyBody = 1 << 20
+)
+
+// captureWriter copia status y body de la respuesta mientras se escribe.
+type captureWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (cw *captureWriter) WriteHeader(code int) {
+	if cw.status == 0 {
+		cw.status = code
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *captureWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	cw.body.Write(b)
+	return cw.ResponseWriter.Write(b)
+}
+
+// Idempotency repite la respuesta guardada cuando un cliente reintenta un
+// pedido con el mismo Idempotency-Key. Las claves se separan por usuario
+// (o "global" en rutas sin usuario) y duran 24h. Reusar la clave con otro
+// body devuelve 409. Sin header, o en métodos de solo lectura, el pedido pasa
+// sin cambios. maxBody es el límite de body de las rutas (0 usa 1MB): el body
+// se lee entero para el hash, así que se corta antes de llegar al handler.
+func Idempotency(store idempotency.Store, maxBody int64) func(http.Handler) http.Handler {
+	if maxBody <= 0 {
+		maxBody = defaultIdempotencyBody
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotencyHeader)
+			if key == "" || isSafeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKey {
+				utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+					Code:    utils.ErrCodeValidation,
+					Message: "Idempotency-Key inválida",
+				})
+				return
+			}
+
+			var body []byte
+			if r.Body != nil {
+				b, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBody))
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					utils.WriteError(w, http.StatusRequestEntityTooLarge, utils.WriteErrorOpts{
+						Code:    utils.ErrCodeValidation,
+						Message: "El body supera el tamaño permitido",
+					})
+					return
+				}
+				if err != nil {
+					utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+						Code:    utils.ErrCodeValidation,
+						Message: "No se pudo leer el body",
+					})
+					return
+				}
+				r.Body.Close()
+				body = b
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			scope := "global"
+			if id, ok := UserIDFromContext(r.Context()); ok {
+				scope = id.String()
+			}
+			hash := idempotency.RequestHash(r.Method, r.URL.Path, body)
+
+			stored, err := store.Begin(r.Context(), scope, key, hash, time.Now(), idempotencyTTL)
+			switch {
+			case errors.Is(err, idempotency.ErrKeyReused):
+				utils.WriteError(w, http.StatusConflict, utils.WriteErrorOpts{
+					Code:    utils.ErrCodeConflict,
+					Message: "La Idempotency-Key ya se usó con otro pedido",
+				})
+				return
+			case errors.Is(err, idempotency.ErrInProgress):
+				w.Header().Set("Retry-After", "1")
+				utils.WriteError(w, http.StatusConflict, utils.WriteErrorOpts{
+					Code:    utils.ErrCodeConflict,
+					Message: "El pedido original todavía se está procesando",
+				})
+				return
+			case err != nil:
+				slog.ErrorContext(r.Context(), "idempotency no disponible", "error", err)
+				utils.WriteError(w, http.StatusInternalServerError, utils.WriteErrorOpts{
+					Code:    utils.ErrCodeInternal,
+					Message: "Error interno",
+				})
+				return
+			case stored != nil:
+				if stored.ContentType != "" {
+					w.Header().Set("Content-Type", stored.ContentType)
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(stored.Status)
+				_, _ = w.Write(stored.Body)
+				return
+			}
+
+			cw := &captureWriter{ResponseWriter: w}
+			// Si el handler entra en pánico liberamos la clave para permitir el reintento.
+			completed := false
+			defer func() {
+				if !completed {
+					_ = store.Release(context.WithoutCancel(r.Context()), scope, key)
+				}
+			}()
+
+			next.ServeHTTP(cw, r)
+
+			ctx := context.WithoutCancel(r.Context())
+			completed = true
+			status := cw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			// Los errores del servidor no se guardan: el reintento debe volver a ejecutarse.
+			if status >= http.StatusInternalServerError {
+				if err := store.Release(ctx, scope, key); err != nil {
+					slog.ErrorContext(ctx, "no se pudo liberar la idempotency key", "error", err)
+				}
+				return
+			}
+
+			resp := idempotency.Response{
+				Status:      status,
+				ContentType: cw.Header().Get("Content-Type"),
+				Body:        cw.body.Bytes(),
+			}
+			if err := store.Complete(ctx, scope, key, resp); err != nil {
+				slog.ErrorContext(ctx, "no se pudo guardar la respuesta idempotente", "error", err)
+			}
+		})
+	}
+}
+
+// isSafeMethod indica los métodos que no modifican nada: repetirlos no tiene
+// efectos y guardar su respuesta solo serviría datos viejos.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sebaactis/powermix-back-mobile/internal/platform/idempotency"
+)
+
+func TestIdempotency_ReplayAndConflict(t *testing.T) {
+	calls := 0
+	handler := Idempotency(idempotency.NewMemoryStore(), 0)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+
+	do := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/proof", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	first := do("abc", `{"proofId":"1"}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first status = %d, want %d", first.Code, http.StatusCreated)
+	}
+
+	replay := do("abc", `{"proofId":"1"}`)
+	if replay.Code != http.StatusCreated || replay.Body.String() != `{"id":1}` {
+		t.Fatalf("replay = (%d, %q), want stored response", replay.Code, replay.Body.String())
+	}
+	if replay.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatal("expected Idempotent-Replayed header on replay")
+	}
+	if calls != 1 {
+		t.Fatalf("handler calls = %d, want 1", calls)
+	}
+
+	if conflict := do("abc", `{"proofId":"2"}`); conflict.Code != http.StatusConflict {
+		t.Fatalf("reuse status = %d, want %d", conflict.Code, http.StatusConflict)
+	}
+
+	do("", `{"proofId":"1"}`)
+	if calls != 2 {
+		t.Fatalf("handler calls without key = %d, want 2", calls)
+	}
+}
+
+func TestIdempotency_ServerErrorIsNotStored(t *testing.T) {
+	calls := 0
+	handler := Idempotency(idempotency.NewMemoryStore(), 0)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/proof", strings.NewReader(`{}`))
+		req.Header.Set("Idempotency-Key", "k")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if calls != 2 {
+		t.Fatalf("handler calls = %d, want 2", calls)
+	}
+}
+
+func TestIdempotency_BodyTooLarge(t *testing.T) {
+	calls := 0
+	handler := Idempotency(idempotency.NewMemoryStore(), 8)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/proof", strings.NewReader(`{"proofId":"123456"}`))
+	req.Header.Set("Idempotency-Key", "k")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusRequestEntityTooLarge)
+	}
+	if calls != 0 {
+		t.Fatalf("handler calls = %d, want 0", calls)
+	}
+}
+
+func TestIdempotency_SkipsSafeMethods(t *testing.T) {
+	calls := 0
+	handler := Idempotency(idempotency.NewMemoryStore(), 0)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/prode/admin/matches/1/winners.csv", nil)
+		req.Header.Set("Idempotency-Key", "k")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Header().Get("Idempotent-Replayed") != "" {
+			t.Fatal("GET should not be replayed")
+		}
+	}
+
+	if calls != 2 {
+		t.Fatalf("handler calls = %d, want 2", calls)
+	}
+}
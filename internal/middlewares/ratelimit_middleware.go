@@ -1,88 +1,76 @@
 package middlewares
 
 import (
-	"net"
+	"log/slog"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
 	"time"
 
+	"github.com/sebaactis/powermix-back-mobile/internal/platform/ratelimit"
 	"github.com/sebaactis/powermix-back-mobile/internal/utils"
 )
 
-type bucket struct {
-	count      int
-	windowFrom time.Time
-}
-
+// RateLimiter aplica políticas token bucket sobre un ratelimit.Store y
+// publica el estado en los headers RateLimit-* y Retry-After.
 type RateLimiter struct {
-	mu        sync.Mutex
-	perWindow int
-	window    time.Duration
-	buckets   map[string]*bucket
+	store ratelimit.Store
+	now   func() time.Time
 }
 
-func NewRateLimiter(perWindow int, window time.Duration) *RateLimiter {
-	rl := &RateLimiter{
-		perWindow: perWindow,
-		window:    window,
-		buckets:   make(map[string]*bucket),
-	}
-	go rl.cleanupLoop()
-	return rl
+func NewRateLimiter(store ratelimit.Store) *RateLimiter {
+	return &RateLimiter{store: store, now: time.Now}
 }
 
-// cleanupLoop barre el mapa cada 2 ventanas y elimina buckets expirados.
-// Se ejecuta en su propia goroutine hasta que el proceso termina.
-func (rl *RateLimiter) cleanupLoop() {
-	ticker := time.NewTicker(rl.window * 2)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		rl.mu.Lock()
-		now := time.Now()
-		for ip, b := range rl.buckets {
-			if now.Sub(b.windowFrom) >= rl.window {
-				delete(rl.buckets, ip)
-			}
-		}
-		rl.mu.Unlock()
-	}
+// ByIP limita por IP de cliente.
+func (rl *RateLimiter) ByIP(p ratelimit.Policy) func(http.Handler) http.Handler {
+	return rl.middleware(p, func(r *http.Request) string {
+		return "ip:" + ClientIP(r)
+	})
 }
 
-func clientIP(r *http.Request) string {
-	host, _, err := net.SplitHostPort(r.RemoteAddr)
-
-	if err != nil {
-		return r.RemoteAddr
-	}
-
-	return host
+// ByUser limita por usuario autenticado; debe ir después de RequireAuth.
+// Sin usuario en el contexto cae a la IP.
+func (rl *RateLimiter) ByUser(p ratelimit.Policy) func(http.Handler) http.Handler {
+	return rl.middleware(p, func(r *http.Request) string {
+		if id, ok := UserIDFromContext(r.Context()); ok {
+			return "user:" + id.String()
+		}
+		return "ip:" + ClientIP(r)
+	})
 }
 
-func (rl *RateLimiter) Middleware() func(http.Handler) http.Handler {
+func (rl *RateLimiter) middleware(p ratelimit.Policy, key func(*http.Request) string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ip := clientIP(r)
-
-			rl.mu.Lock()
-			b, ok := rl.buckets[ip]
-			now := time.Now()
-			if !ok || now.Sub(b.windowFrom) >= rl.window {
-				b = &bucket{count: 0, windowFrom: now}
-				rl.buckets[ip] = b
+			d, err := rl.store.Take(r.Context(), p.Name+":"+key(r), p, rl.now())
+			if err != nil {
+				// Si el store falla preferimos atender el pedido antes que cortar la API.
+				slog.Warn("rate limit no disponible", "policy", p.Name, "error", err)
+				next.ServeHTTP(w, r)
+				return
 			}
-			if b.count >= rl.perWindow {
-				rl.mu.Unlock()
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(d.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(d.Reset)))
+			h.Set("RateLimit-Policy", strconv.Itoa(p.Capacity)+";w="+strconv.Itoa(ceilSeconds(p.Period)))
+
+			if !d.Allowed {
+				h.Set("Retry-After", strconv.Itoa(ceilSeconds(d.RetryAfter)))
 				utils.WriteError(w, http.StatusTooManyRequests, utils.WriteErrorOpts{
-					Code:    utils.ErrCodeInternal,
-					Message: "Rate limit excedido",
+					Code:    utils.ErrCodeRateLimited,
+					Message: "Demasiados pedidos, intentá de nuevo más tarde",
 				})
 				return
 			}
-			b.count++
-			rl.mu.Unlock()
 
 			next.ServeHTTP(w, r)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
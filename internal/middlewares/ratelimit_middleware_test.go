@@ -0,0 +1,136 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sebaactis/powermix-back-mobile/internal/platform/ratelimit"
+)
+
+func TestRateLimiter_HeadersAndRejection(t *testing.T) {
+	rl := NewRateLimiter(ratelimit.NewMemoryStore())
+	now := time.Unix(1700000000, 0)
+	rl.now = func() time.Time { return now }
+
+	p := ratelimit.Policy{Name: "test", Capacity: 2, Period: time.Minute}
+	handler := rl.ByIP(p)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	do := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "203.0.113.9:5000"
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	first := do()
+	if first.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", first.Code, http.StatusNoContent)
+	}
+	if got := first.Header().Get("RateLimit-Limit"); got != "2" {
+		t.Fatalf("RateLimit-Limit = %q, want 2", got)
+	}
+	if got := first.Header().Get("RateLimit-Remaining"); got != "1" {
+		t.Fatalf("RateLimit-Remaining = %q, want 1", got)
+	}
+	if got := first.Header().Get("RateLimit-Policy"); got != "2;w=60" {
+		t.Fatalf("RateLimit-Policy = %q, want 2;w=60", got)
+	}
+
+	do()
+	rejected := do()
+	if rejected.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", rejected.Code, http.StatusTooManyRequests)
+	}
+	if got := rejected.Header().Get("Retry-After"); got != "30" {
+		t.Fatalf("Retry-After = %q, want 30", got)
+	}
+	if got := rejected.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Fatalf("RateLimit-Remaining = %q, want 0", got)
+	}
+}
+
+func TestRateLimiter_ByUserUsesSeparateBuckets(t *testing.T) {
+	rl := NewRateLimiter(ratelimit.NewMemoryStore())
+	p := ratelimit.Policy{Name: "user", Capacity: 1, Period: time.Minute}
+	handler := rl.ByUser(p)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	do := func(userID uuid.UUID) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(context.WithValue(req.Context(), ctxUserID, userID))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	a, b := uuid.New(), uuid.New()
+	if code := do(a); code != http.StatusNoContent {
+		t.Fatalf("user a first = %d", code)
+	}
+	if code := do(a); code != http.StatusTooManyRequests {
+		t.Fatalf("user a second = %d, want 429", code)
+	}
+	if code := do(b); code != http.StatusNoContent {
+		t.Fatalf("user b first = %d, want 204", code)
+	}
+}
+
+func TestClientIP_WithoutTrustedProxies(t *testing.T) {
+	if err := SetTrustedProxies(nil); err != nil {
+		t.Fatalf("SetTrustedProxies: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "203.0.113.5:51234"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4")
+	req.Header.Set("X-Real-Ip", "5.6.7.8")
+
+	if got := ClientIP(req); got != "203.0.113.5" {
+		t.Errorf("ClientIP() = %q, want 203.0.113.5", got)
+	}
+}
+
+func TestClientIP_TrustedProxies(t *testing.T) {
+	if err := SetTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"}); err != nil {
+		t.Fatalf("SetTrustedProxies: %v", err)
+	}
+	t.Cleanup(func() { _ = SetTrustedProxies(nil) })
+
+	tests := []struct {
+		name     string
+		remote   string
+		xff      string
+		expected string
+	}{
+		{"untrusted remote ignores header", "203.0.113.5:443", "1.2.3.4", "203.0.113.5"},
+		{"trusted remote uses header", "10.1.2.3:443", "198.51.100.7", "198.51.100.7"},
+		{"spoofed left entry is skipped", "10.1.2.3:443", "1.2.3.4, 198.51.100.7, 10.0.0.2", "198.51.100.7"},
+		{"single trusted ip", "192.168.1.1:80", "198.51.100.8", "198.51.100.8"},
+		{"trusted remote without header", "10.1.2.3:443", "", "10.1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			if tt.xff != "" {
+				req.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			if got := ClientIP(req); got != tt.expected {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if err := SetTrustedProxies([]string{"not-an-ip"}); err == nil {
+		t.Error("SetTrustedProxies(invalid) = nil, want error")
+	}
+}
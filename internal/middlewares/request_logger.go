@@ -17,6 +17,7 @@ var sensitiveFields = map[string]bool{
 	"password": true, "currentPassword": true, "newPassword": true,
 	"confirmPassword": true, "token": true, "refreshToken": true,
 	"accessToken": true, "access_token": true, "refresh_token": true,
+	"mfaToken": true, "code": true, "recoveryCode": true,
 }
 
 // responseWriter envuelve http.ResponseWriter para capturar el status code.
@@ -87,23 +88,28 @@ func shouldLogBody(method string) bool {
 }
 
 // readAndRestoreBody lee el body del request para loguear y lo restaura
-// así los handlers de abajo pueden leerlo de nuevo.
+// así los handlers de abajo pueden leerlo de nuevo. Solo se leen los
+// primeros 10KB: el resto queda en el body original sin consumir.
 func readAndRestoreBody(r *http.Request) map[string]any {
 	if r.Body == nil || r.ContentLength == 0 {
 		return nil
 	}
 
-	// Limitamos la lectura del body a 10KB
+	// Uploads (multipart, imágenes) no se loguean.
+	if ct := r.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "application/json") {
+		return nil
+	}
+
 	const maxBodySize = 10 * 1024
-	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
-	if err != nil {
+	head, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), r.Body), Closer: r.Body}
+	if err != nil || len(head) == maxBodySize {
+		// Body incompleto: no es JSON parseable.
 		return nil
 	}
-	r.Body.Close()
-	r.Body = io.NopCloser(bytes.NewReader(body))
 
 	var m map[string]any
-	if err := json.Unmarshal(body, &m); err != nil {
+	if err := json.Unmarshal(head, &m); err != nil {
 		return nil
 	}
 	return m
@@ -122,17 +128,8 @@ func sanitizeBody(m map[string]any) map[string]any {
 	return result
 }
 
-// extractClientIP extrae la IP del cliente, respetando X-Forwarded-For (para Render.com).
-func extractClientIP(r *http.Request) string {
-	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
-		// X-Forwarded-For puede ser una lista separada por comas
-		if idx := strings.Index(fwd, ","); idx != -1 {
-			return strings.TrimSpace(fwd[:idx])
-		}
-		return fwd
-	}
-	if real := r.Header.Get("X-Real-Ip"); real != "" {
-		return real
-	}
-	return r.RemoteAddr
+// readCloser combina el body ya leído con el resto, cerrando el original.
+type readCloser struct {
+	io.Reader
+	io.Closer
 }
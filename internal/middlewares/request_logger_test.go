@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -190,6 +191,25 @@ func TestRequestLogger_ContextPropagation(t *testing.T) {
 	}
 }
 
+func TestRequestLogger_PreservesLargeBody(t *testing.T) {
+	log := slog.New(logger.NewContextHandler(&captureHandler{}))
+	body := `{"data":"` + strings.Repeat("x", 20*1024) + `"}`
+
+	var got string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got = string(b)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/proof", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	RequestLogger(log)(handler).ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != body {
+		t.Fatalf("handler body length = %d, want %d", len(got), len(body))
+	}
+}
+
 func TestExtractClientIP(t *testing.T) {
 	tests := []struct {
 		name     string
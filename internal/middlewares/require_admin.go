@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/user"
+	"github.com/sebaactis/powermix-back-mobile/internal/utils"
+)
+
+// RoleResolver devuelve el rol vigente del usuario. El JWT no lleva el rol:
+// se consulta en cada pedido para que quitar permisos tenga efecto inmediato.
+type RoleResolver interface {
+	RoleOf(ctx context.Context, userID uuid.UUID) (string, error)
+}
+
+// RequireAdmin deja pasar solo a usuarios con rol ADMIN. Va después de
+// RequireAuth.
+func RequireAdmin(roles RoleResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := UserIDFromContext(r.Context())
+			if !ok {
+				utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+					Code:    utils.ErrCodeUnauthorized,
+					Message: "No autorizado",
+				})
+				return
+			}
+
+			role, err := roles.RoleOf(r.Context(), userID)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "no se pudo resolver el rol", "user_id", userID, "error", err)
+				utils.WriteError(w, http.StatusInternalServerError, utils.WriteErrorOpts{
+					Code:    utils.ErrCodeInternal,
+					Message: "No se pudo validar los permisos",
+				})
+				return
+			}
+
+			if role != user.RoleAdmin {
+				utils.WriteError(w, http.StatusForbidden, utils.WriteErrorOpts{
+					Code:    utils.ErrCodeUnauthorized,
+					Message: "Se requieren permisos de administrador",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
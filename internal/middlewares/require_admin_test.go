@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type stubRoles map[uuid.UUID]string
+
+func (s stubRoles) RoleOf(_ context.Context, id uuid.UUID) (string, error) {
+	role, ok := s[id]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return role, nil
+}
+
+func TestRequireAdmin(t *testing.T) {
+	admin, user, unknown := uuid.New(), uuid.New(), uuid.New()
+	roles := stubRoles{admin: "ADMIN", user: "USER"}
+
+	handler := RequireAdmin(roles)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	cases := []struct {
+		name   string
+		userID *uuid.UUID
+		want   int
+	}{
+		{"admin", &admin, http.StatusNoContent},
+		{"usuario común", &user, http.StatusForbidden},
+		{"rol no resuelto", &unknown, http.StatusInternalServerError},
+		{"sin autenticar", nil, http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		if tc.userID != nil {
+			req = req.WithContext(context.WithValue(req.Context(), ctxUserID, *tc.userID))
+		}
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, rr.Code, tc.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -15,6 +16,58 @@ type Config struct {
 	CoffejiSecret          string
 	ResendKey              string
 	HashToken              string
+	// GeoCountryHeader es el header con el país del cliente que agrega el
+	// proxy/CDN (ej. CF-IPCountry). Vacío desactiva la detección por país.
+	GeoCountryHeader string
+
+	// Política de contraseñas. Los valores por defecto salen de password.DefaultPolicy.
+	PasswordMinLength     int
+	PasswordRequireUpper  bool
+	PasswordRequireLower  bool
+	PasswordRequireDigit  bool
+	PasswordRequireSymbol bool
+	PasswordHistorySize   int
+	// BreachedPasswordsDir apunta al dataset local de hashes filtrados
+	// (formato de rangos de HIBP). Vacío desactiva el chequeo.
+	BreachedPasswordsDir string
+
+	// TrustedProxies son las IPs o CIDRs de los proxies propios. Solo se
+	// confía en X-Forwarded-For cuando el pedido llega desde uno de ellos.
+	TrustedProxies []string
+	// BehindProxy indica que la app recibe el tráfico a través de un proxy
+	// (en Render por defecto). Así TRUSTED_PROXIES es obligatorio: sin él el
+	// rate limit y el bloqueo por IP verían a todos como la IP del proxy.
+	BehindProxy bool
+	// AdminEmails son las cuentas con rol ADMIN (revisión de comprobantes).
+	// Se aplica al arrancar: quien no esté en la lista deja de ser admin.
+	// Vacío no modifica los roles.
+	AdminEmails []string
+	// OCRTesseractBin y OCRLang configuran el OCR local de comprobantes.
+	// Vacíos usan "tesseract" del PATH y el idioma "spa".
+	OCRTesseractBin string
+	OCRLang         string
+	// Conciliación de comprobantes "otros" contra Mercado Pago.
+	MPReconcileWindowMin    int
+	MPReconcileToleranceMin int
+	MPReconcileAmountTol    float64
+	// MPWebhookSecret valida la firma de las notificaciones de Mercado Pago.
+	// Vacío desactiva el webhook; las devoluciones se detectan igual por el job.
+	MPWebhookSecret string
+	// Reglas de elegibilidad de comprobantes. Cero o vacío desactiva la regla.
+	ProofMaxAgeDays int
+	ProofMinAmount  float64
+	MPCollectorID   int
+	ProofProducts   []string
+	// Sellos por comprobante: "single" (uno), "item" (uno por unidad del
+	// pedido, tope StampMaxPerProof) o "amount" (tramos de StampAmountTiers).
+	StampMode        string
+	StampMaxPerProof int
+	StampAmountTiers []string
+	// FraudReviewThreshold es el puntaje de riesgo desde el que un comprobante
+	// queda en revisión manual. 0 desactiva la revisión.
+	FraudReviewThreshold float64
+	// RateLimitStore elige dónde viven los buckets: "memory" o "postgres".
+	RateLimitStore string
 
 	// ProdeEnabled activa/desactiva toda la feature PRODE.
 	// false = las rutas /api/v1/prode/* no se registran, las tablas existen pero
@@ -25,6 +78,25 @@ type Config struct {
 	ProdeMaintenanceEnabled bool
 	ProdeAdminAPIKey       string
 	ProdeAdminEmails       []string
+	// Puntos del prode por resultado exacto, por acertar ganador o empate y
+	// bono por acertar además la diferencia de gol.
+	ProdePointsExact    int
+	ProdePointsOutcome  int
+	ProdePointsGoalDiff int
+	// ProdeOpenLeadHours es cuántas horas antes del inicio el scheduler abre
+	// un partido; ProdeAutoSettle evalúa solo los partidos con resultado.
+	ProdeOpenLeadHours int
+	ProdeAutoSettle    bool
+	// ProdeReminderLeadHours es cuántas horas antes del corte se recuerda a
+	// los usuarios que no pronosticaron. 0 desactiva los recordatorios.
+	ProdeReminderLeadHours int
+	// Feed de resultados (football-data.org). Sin token no se importa nada.
+	SportsAPIURL      string
+	SportsAPIToken    string
+	SportsTeamID      int
+	SportsCompetition string
+	// SportsTournamentSlug es el torneo donde se cargan los partidos del feed.
+	SportsTournamentSlug string
 }
 
 func Load() (Config, error) {
@@ -37,9 +109,58 @@ func Load() (Config, error) {
 		CoffejiSecret:           os.Getenv("COFFEJI_SECRET"),
 		ResendKey:               os.Getenv("RESEND_API_KEY"),
 		HashToken:               os.Getenv("JWT_REFRESH_HASH"),
+		GeoCountryHeader:        os.Getenv("GEO_COUNTRY_HEADER"),
+		PasswordMinLength:       envInt("PASSWORD_MIN_LENGTH", 8),
+		PasswordRequireUpper:    envBool("PASSWORD_REQUIRE_UPPER", true),
+		PasswordRequireLower:    envBool("PASSWORD_REQUIRE_LOWER", true),
+		PasswordRequireDigit:    envBool("PASSWORD_REQUIRE_DIGIT", true),
+		PasswordRequireSymbol:   envBool("PASSWORD_REQUIRE_SYMBOL", false),
+		PasswordHistorySize:     envInt("PASSWORD_HISTORY_SIZE", 5),
+		BreachedPasswordsDir:    os.Getenv("BREACHED_PASSWORDS_DIR"),
+		TrustedProxies:          envList("TRUSTED_PROXIES"),
+		BehindProxy:             envBool("BEHIND_PROXY", os.Getenv("RENDER") == "true"),
+		AdminEmails:             envList("ADMIN_EMAILS"),
+		RateLimitStore:          os.Getenv("RATE_LIMIT_STORE"),
+		OCRTesseractBin:         os.Getenv("OCR_TESSERACT_BIN"),
+		OCRLang:                 os.Getenv("OCR_LANG"),
+		MPReconcileWindowMin:    envInt("MP_RECONCILE_WINDOW_MINUTES", 10),
+		MPReconcileToleranceMin: envInt("MP_RECONCILE_TOLERANCE_MINUTES", 5),
+		MPReconcileAmountTol:    envFloat("MP_RECONCILE_AMOUNT_TOLERANCE", 0.01),
+		MPWebhookSecret:         os.Getenv("MP_WEBHOOK_SECRET"),
+		ProofMaxAgeDays:         envInt("PROOF_MAX_AGE_DAYS", 30),
+		ProofMinAmount:          envFloat("PROOF_MIN_AMOUNT", 0),
+		MPCollectorID:           envInt("MP_COLLECTOR_ID", 0),
+		ProofProducts:           envList("PROOF_PRODUCT_ALLOWLIST"),
+		StampMode:               os.Getenv("STAMP_MODE"),
+		StampMaxPerProof:        envInt("STAMP_MAX_PER_PROOF", 0),
+		StampAmountTiers:        envList("STAMP_AMOUNT_TIERS"),
+		FraudReviewThreshold:    envFloat("FRAUD_REVIEW_THRESHOLD", 0.5),
 		ProdeEnabled:            os.Getenv("PRODE_ENABLED") == "true",
 		ProdeMaintenanceEnabled: os.Getenv("PRODE_MAINTENANCE_ENABLED") == "true",
 		ProdeAdminAPIKey:        os.Getenv("PRODE_ADMIN_API_KEY"),
+		ProdePointsExact:        envInt("PRODE_POINTS_EXACT", 3),
+		ProdePointsOutcome:      envInt("PRODE_POINTS_OUTCOME", 1),
+		ProdePointsGoalDiff:     envInt("PRODE_POINTS_GOAL_DIFF", 1),
+		ProdeOpenLeadHours:      envInt("PRODE_OPEN_LEAD_HOURS", 72),
+		ProdeAutoSettle:         envBool("PRODE_AUTO_SETTLE", false),
+		ProdeReminderLeadHours:  envInt("PRODE_REMINDER_LEAD_HOURS", 3),
+		SportsAPIURL:            os.Getenv("SPORTS_API_URL"),
+		SportsAPIToken:          os.Getenv("SPORTS_API_TOKEN"),
+		SportsTeamID:            envInt("SPORTS_TEAM_ID", 762),
+		SportsCompetition:       os.Getenv("SPORTS_COMPETITION"),
+		SportsTournamentSlug:    os.Getenv("SPORTS_TOURNAMENT_SLUG"),
+	}
+
+	if cfg.StampMode == "" {
+		cfg.StampMode = "single"
+	}
+
+	if cfg.SportsTournamentSlug == "" {
+		cfg.SportsTournamentSlug = "mundial-2026"
+	}
+
+	if cfg.RateLimitStore == "" {
+		cfg.RateLimitStore = "memory"
 	}
 
 	if emails := os.Getenv("PRODE_ADMIN_EMAILS"); emails != "" {
@@ -87,9 +208,106 @@ func (c Config) validate() error {
 		}
 	}
 
+	if c.PasswordMinLength < 8 || c.PasswordMinLength > 30 {
+		return fmt.Errorf("PASSWORD_MIN_LENGTH debe estar entre 8 y 30")
+	}
+
+	if c.PasswordHistorySize < 0 {
+		return fmt.Errorf("PASSWORD_HISTORY_SIZE no puede ser negativo")
+	}
+
+	if c.MPReconcileToleranceMin < 1 || c.MPReconcileWindowMin < c.MPReconcileToleranceMin {
+		return fmt.Errorf("MP_RECONCILE_WINDOW_MINUTES debe ser mayor o igual a MP_RECONCILE_TOLERANCE_MINUTES (mínimo 1)")
+	}
+
+	if c.MPReconcileAmountTol < 0 {
+		return fmt.Errorf("MP_RECONCILE_AMOUNT_TOLERANCE no puede ser negativo")
+	}
+
+	if c.ProofMaxAgeDays < 0 || c.ProofMinAmount < 0 {
+		return fmt.Errorf("PROOF_MAX_AGE_DAYS y PROOF_MIN_AMOUNT no pueden ser negativos")
+	}
+
+	if c.FraudReviewThreshold < 0 {
+		return fmt.Errorf("FRAUD_REVIEW_THRESHOLD no puede ser negativo")
+	}
+
+	switch c.StampMode {
+	case "single", "item":
+	case "amount":
+		if len(c.StampAmountTiers) == 0 {
+			return fmt.Errorf("STAMP_AMOUNT_TIERS es requerido cuando STAMP_MODE es amount")
+		}
+	default:
+		return fmt.Errorf("STAMP_MODE debe ser single, item o amount")
+	}
+
+	if c.BehindProxy && len(c.TrustedProxies) == 0 {
+		return fmt.Errorf("TRUSTED_PROXIES es requerido cuando BEHIND_PROXY es true")
+	}
+
+	if c.RateLimitStore != "memory" && c.RateLimitStore != "postgres" {
+		return fmt.Errorf("RATE_LIMIT_STORE debe ser memory o postgres")
+	}
+
+	if c.ProdePointsExact < 0 || c.ProdePointsOutcome < 0 || c.ProdePointsGoalDiff < 0 {
+		return fmt.Errorf("PRODE_POINTS_EXACT, PRODE_POINTS_OUTCOME y PRODE_POINTS_GOAL_DIFF no pueden ser negativos")
+	}
+
+	if c.ProdeOpenLeadHours < 1 {
+		return fmt.Errorf("PRODE_OPEN_LEAD_HOURS debe ser al menos 1")
+	}
+
+	if c.ProdeReminderLeadHours < 0 {
+		return fmt.Errorf("PRODE_REMINDER_LEAD_HOURS no puede ser negativo")
+	}
+
 	if c.ProdeMaintenanceEnabled && strings.TrimSpace(c.ProdeAdminAPIKey) == "" {
 		return fmt.Errorf("PRODE_ADMIN_API_KEY is required when PRODE_MAINTENANCE_ENABLED is true")
 	}
 
 	return nil
 }
+
+// envInt lee un entero de entorno; si falta o es inválido usa def.
+func envInt(key string, def int) int {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// envFloat lee un decimal de entorno; si falta o es inválido usa def.
+func envFloat(key string, def float64) float64 {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+// envBool acepta "true"/"false"; cualquier otro valor deja def.
+func envBool(key string, def bool) bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(key))) {
+	case "true":
+		return true
+	case "false":
+		return false
+	default:
+		return def
+	}
+}
+
+// envList lee una lista separada por comas, descartando elementos vacíos.
+func envList(key string) []string {
+	var out []string
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
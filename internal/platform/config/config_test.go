@@ -141,6 +141,160 @@ func TestConfigLoad(t *testing.T) {
 	})
 }
 
+func TestConfigPasswordPolicy(t *testing.T) {
+	setRequired := func(t *testing.T) {
+		t.Setenv("HTTP_ADDR", "localhost:8080")
+		t.Setenv("DB_DRIVER", "postgres")
+		t.Setenv("DSN", "postgres://localhost")
+		t.Setenv("MERCAGO_PAGO_TOKEN", "token")
+		t.Setenv("COFFEJI_KEY", "key")
+		t.Setenv("COFFEJI_SECRET", "secret")
+		t.Setenv("RESEND_API_KEY", "resend_key")
+		t.Setenv("JWT_REFRESH_HASH", "hash")
+	}
+
+	t.Run("Load uses password policy defaults", func(t *testing.T) {
+		setRequired(t)
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+
+		if cfg.PasswordMinLength != 8 || !cfg.PasswordRequireUpper || cfg.PasswordRequireSymbol || cfg.PasswordHistorySize != 5 {
+			t.Errorf("unexpected password defaults: %+v", cfg)
+		}
+	})
+
+	t.Run("Load reads password policy overrides", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("PASSWORD_MIN_LENGTH", "12")
+		t.Setenv("PASSWORD_REQUIRE_SYMBOL", "true")
+		t.Setenv("PASSWORD_HISTORY_SIZE", "0")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+
+		if cfg.PasswordMinLength != 12 || !cfg.PasswordRequireSymbol || cfg.PasswordHistorySize != 0 {
+			t.Errorf("overrides not applied: min=%d symbol=%v history=%d",
+				cfg.PasswordMinLength, cfg.PasswordRequireSymbol, cfg.PasswordHistorySize)
+		}
+	})
+
+	t.Run("Load rejects a min length below 8", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("PASSWORD_MIN_LENGTH", "4")
+
+		if _, err := Load(); err == nil {
+			t.Errorf("Expected error for PASSWORD_MIN_LENGTH=4, got nil")
+		}
+	})
+}
+
+func TestConfigRateLimit(t *testing.T) {
+	setRequired := func(t *testing.T) {
+		t.Setenv("HTTP_ADDR", "localhost:8080")
+		t.Setenv("DB_DRIVER", "postgres")
+		t.Setenv("DSN", "postgres://localhost")
+		t.Setenv("MERCAGO_PAGO_TOKEN", "token")
+		t.Setenv("COFFEJI_KEY", "key")
+		t.Setenv("COFFEJI_SECRET", "secret")
+		t.Setenv("RESEND_API_KEY", "resend_key")
+		t.Setenv("JWT_REFRESH_HASH", "hash")
+	}
+
+	t.Run("Load parses trusted proxies and defaults the store", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("TRUSTED_PROXIES", " 10.0.0.0/8, ,192.168.1.1 ")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+
+		if len(cfg.TrustedProxies) != 2 || cfg.TrustedProxies[0] != "10.0.0.0/8" || cfg.TrustedProxies[1] != "192.168.1.1" {
+			t.Errorf("TrustedProxies = %q", cfg.TrustedProxies)
+		}
+		if cfg.RateLimitStore != "memory" {
+			t.Errorf("RateLimitStore = %q, want memory", cfg.RateLimitStore)
+		}
+	})
+
+	t.Run("Load requires trusted proxies behind a proxy", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("BEHIND_PROXY", "true")
+
+		if _, err := Load(); err == nil {
+			t.Errorf("Expected error for BEHIND_PROXY without TRUSTED_PROXIES, got nil")
+		}
+
+		t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8")
+		if _, err := Load(); err != nil {
+			t.Errorf("Load() error: %v", err)
+		}
+	})
+
+	t.Run("Load assumes a proxy on Render", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("BEHIND_PROXY", "")
+		t.Setenv("RENDER", "true")
+
+		cfg, err := Load()
+		if err == nil {
+			t.Errorf("Expected error on Render without TRUSTED_PROXIES, got nil")
+		}
+		if !isEmptyConfig(cfg) {
+			t.Errorf("Expected empty Config when error occurs")
+		}
+	})
+
+	t.Run("Load rejects an unknown store", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("RATE_LIMIT_STORE", "redis")
+
+		if _, err := Load(); err == nil {
+			t.Errorf("Expected error for RATE_LIMIT_STORE=redis, got nil")
+		}
+	})
+}
+
+func TestConfigStampMode(t *testing.T) {
+	setRequired := func(t *testing.T) {
+		t.Setenv("HTTP_ADDR", "localhost:8080")
+		t.Setenv("DB_DRIVER", "postgres")
+		t.Setenv("DSN", "postgres://localhost")
+		t.Setenv("MERCAGO_PAGO_TOKEN", "token")
+		t.Setenv("COFFEJI_KEY", "key")
+		t.Setenv("COFFEJI_SECRET", "secret")
+		t.Setenv("RESEND_API_KEY", "resend_key")
+		t.Setenv("JWT_REFRESH_HASH", "hash")
+	}
+
+	t.Run("Load defaults the stamp mode and requires tiers for amount", func(t *testing.T) {
+		setRequired(t)
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if cfg.StampMode != "single" {
+			t.Errorf("StampMode = %q, want single", cfg.StampMode)
+		}
+
+		t.Setenv("STAMP_MODE", "amount")
+		if _, err := Load(); err == nil {
+			t.Errorf("Expected error for STAMP_MODE=amount without tiers, got nil")
+		}
+
+		t.Setenv("STAMP_AMOUNT_TIERS", "3000:2")
+		if _, err := Load(); err != nil {
+			t.Errorf("Load() error: %v", err)
+		}
+	})
+}
+
 // Test: Validate that the error message contains the missing variable name
 func TestConfigErrorMessages(t *testing.T) {
 	t.Run("Error message includes the name of the missing variable", func(t *testing.T) {
@@ -3,12 +3,16 @@ package database
 import (
 	"fmt"
 
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/loginevent"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/mppayment"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/prode"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/proof"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/token"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/user"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/voucher"
 	"github.com/sebaactis/powermix-back-mobile/internal/platform/config"
+	"github.com/sebaactis/powermix-back-mobile/internal/platform/idempotency"
+	"github.com/sebaactis/powermix-back-mobile/internal/platform/ratelimit"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -28,13 +32,151 @@ func Open(cfg config.Config) (*gorm.DB, error) {
 }
 
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := renameProdeGoalColumns(db); err != nil {
+		return err
+	}
+
+	err := db.AutoMigrate(
 		&user.User{},
+		&user.Identity{},
+		&user.PasswordHistory{},
 		&voucher.Voucher{},
 		&proof.Proof{},
+		&proof.PaymentLink{},
+		&proof.Suggestion{},
 		&token.Token{},
+		&loginevent.LoginEvent{},
+		&mppayment.Payment{},
+		&mppayment.SyncState{},
+		&prode.ProdeTournament{},
+		&prode.ProdeTeam{},
 		&prode.ProdeMatch{},
 		&prode.ProdePrediction{},
+		&prode.ProdePredictionRevision{},
 		&prode.ProdeReward{},
+		&prode.ProdeMatchTransition{},
+		&prode.ProdeSettlementRun{},
+		&prode.ProdeLeague{},
+		&prode.ProdeLeagueMember{},
+		&prode.ProdeLeaguePrize{},
+		&prode.ProdeReminder{},
+		&prode.ProdeReminderOptOut{},
+		&ratelimit.Bucket{},
+		&idempotency.Record{},
 	)
+	if err != nil {
+		return err
+	}
+
+	if err := backfillUserIdentities(db); err != nil {
+		return err
+	}
+	if err := backfillEmailVerified(db); err != nil {
+		return err
+	}
+	if err := backfillProdeTournament(db); err != nil {
+		return err
+	}
+	return backfillProdePredictionRevisions(db)
+}
+
+// backfillUserIdentities copia los vínculos OAuth que antes vivían en la fila
+// del usuario. Es idempotente: las identidades ya migradas se ignoran.
+func backfillUserIdentities(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO user_identities (user_id, provider, provider_id, email, created_at, updated_at)
+		SELECT id, oauth_provider, oauth_id, email, NOW(), NOW()
+		FROM users
+		WHERE oauth_provider IS NOT NULL AND oauth_provider <> ''
+		  AND oauth_id IS NOT NULL AND oauth_id <> ''
+		ON CONFLICT DO NOTHING
+	`).Error
+}
+
+// backfillEmailVerified marca como verificadas las cuentas creadas solo con un
+// proveedor externo, que ya verificó el email al darlas de alta. Las cuentas
+// con contraseña quedan sin verificar hasta que usen un código de login o
+// recuperen la contraseña.
+func backfillEmailVerified(db *gorm.DB) error {
+	return db.Exec(`
+		UPDATE users SET email_verified_at = created_at
+		WHERE email_verified_at IS NULL AND (password IS NULL OR password = '')
+	`).Error
+}
+
+// backfillProdePredictionRevisions crea la primera revisión de las
+// predicciones anteriores al historial. Las pendientes toman su última
+// edición; las ya evaluadas, su creación, porque el settlement les cambió
+// updated_at. Es idempotente.
+func backfillProdePredictionRevisions(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO prode_prediction_revisions (id, prediction_id, user_id, match_id, home_goals, away_goals, request_id, created_at)
+		SELECT gen_random_uuid(), p.id, p.user_id, p.match_id, p.home_goals, p.away_goals, ?,
+		       CASE WHEN p.points IS NULL THEN p.updated_at ELSE p.created_at END
+		FROM prode_predictions p
+		WHERE NOT EXISTS (SELECT 1 FROM prode_prediction_revisions r WHERE r.prediction_id = p.id)
+	`, prode.RevisionBackfill).Error
+}
+
+// renameProdeGoalColumns pasa los goles de Argentina/rival a local/visitante.
+// Corre antes del AutoMigrate para que no cree columnas nuevas vacías.
+func renameProdeGoalColumns(db *gorm.DB) error {
+	renames := map[string]string{
+		"argentina_goals": "home_goals",
+		"opponent_goals":  "away_goals",
+	}
+	for _, model := range []any{&prode.ProdeMatch{}, &prode.ProdePrediction{}} {
+		m := db.Migrator()
+		if !m.HasTable(model) {
+			continue
+		}
+		for from, to := range renames {
+			if m.HasColumn(model, from) && !m.HasColumn(model, to) {
+				if err := m.RenameColumn(model, from, to); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// legacyProdeTournament es el torneo al que se asignan los partidos cargados
+// cuando el prode era solo de Argentina.
+const legacyProdeTournament = "mundial-2026"
+
+// backfillProdeTournament mueve los partidos con columna opponent al torneo
+// del Mundial, con Argentina como local, y después borra la columna. Si la
+// columna ya no existe no hace nada.
+func backfillProdeTournament(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&prode.ProdeMatch{}, "opponent") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		stmts := []struct {
+			sql  string
+			args []any
+		}{
+			{`INSERT INTO prode_tournaments (id, slug, name, timezone, cutoff_minutes, reward_rule, is_active, created_at, updated_at)
+			  VALUES (gen_random_uuid(), ?, 'Mundial 2026', ?, ?, ?, true, NOW(), NOW())
+			  ON CONFLICT (slug) DO NOTHING`,
+				[]any{legacyProdeTournament, prode.DefaultTimezone, prode.DefaultCutoffMinutes, prode.RewardRuleExact}},
+			{`INSERT INTO prode_teams (id, name, created_at, updated_at)
+			  SELECT gen_random_uuid(), name, NOW(), NOW()
+			  FROM (SELECT 'Argentina' AS name UNION SELECT DISTINCT opponent FROM prode_matches WHERE opponent <> '') t
+			  ON CONFLICT (name) DO NOTHING`, nil},
+			{`UPDATE prode_matches m SET
+			    tournament_id = (SELECT id FROM prode_tournaments WHERE slug = ?),
+			    home_team_id  = (SELECT id FROM prode_teams WHERE name = 'Argentina'),
+			    away_team_id  = (SELECT id FROM prode_teams t WHERE t.name = m.opponent)
+			  WHERE m.tournament_id IS NULL`, []any{legacyProdeTournament}},
+		}
+		for _, st := range stmts {
+			if err := tx.Exec(st.sql, st.args...).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropColumn(&prode.ProdeMatch{}, "opponent")
+	})
 }
@@ -0,0 +1,47 @@
+// Package idempotency guarda el resultado de pedidos identificados por un
+// Idempotency-Key para poder repetirlo cuando el cliente reintenta.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrKeyReused indica que la clave ya se usó con otro pedido.
+	ErrKeyReused = errors.New("idempotency: key reused with a different request")
+	// ErrInProgress indica que el pedido original todavía se está procesando.
+	ErrInProgress = errors.New("idempotency: request in progress")
+)
+
+// Response es la respuesta guardada de un pedido terminado.
+type Response struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// Store reserva claves y guarda sus respuestas.
+type Store interface {
+	// Begin reserva la clave. Devuelve la respuesta guardada si el pedido ya
+	// terminó, nil si el llamador debe procesarlo, o ErrKeyReused/ErrInProgress.
+	Begin(ctx context.Context, scope, key, requestHash string, now time.Time, ttl time.Duration) (*Response, error)
+	// Complete guarda la respuesta de un pedido reservado con Begin.
+	Complete(ctx context.Context, scope, key string, resp Response) error
+	// Release libera la clave para que un reintento vuelva a procesarse.
+	Release(ctx context.Context, scope, key string) error
+}
+
+// RequestHash identifica un pedido por método, ruta y body.
+func RequestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,47 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	s := NewMemoryStore()
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	hash := RequestHash("POST", "/proof", []byte(`{"a":1}`))
+
+	if resp, err := s.Begin(ctx, "u1", "k", hash, now, time.Hour); resp != nil || err != nil {
+		t.Fatalf("first Begin = (%v, %v), want (nil, nil)", resp, err)
+	}
+
+	if _, err := s.Begin(ctx, "u1", "k", hash, now, time.Hour); !errors.Is(err, ErrInProgress) {
+		t.Fatalf("Begin while in progress: errors.Is(ErrInProgress) = false, got %v", err)
+	}
+
+	other := RequestHash("POST", "/proof", []byte(`{"a":2}`))
+	if _, err := s.Begin(ctx, "u1", "k", other, now, time.Hour); !errors.Is(err, ErrKeyReused) {
+		t.Fatalf("Begin with other body: errors.Is(ErrKeyReused) = false, got %v", err)
+	}
+
+	_ = s.Complete(ctx, "u1", "k", Response{Status: 201, ContentType: "application/json", Body: []byte(`{}`)})
+
+	resp, err := s.Begin(ctx, "u1", "k", hash, now, time.Hour)
+	if err != nil || resp == nil || resp.Status != 201 {
+		t.Fatalf("replay Begin = (%+v, %v), want stored 201", resp, err)
+	}
+
+	// Otro usuario con la misma clave no comparte el resultado.
+	if resp, err := s.Begin(ctx, "u2", "k", hash, now, time.Hour); resp != nil || err != nil {
+		t.Fatalf("Begin other scope = (%v, %v), want (nil, nil)", resp, err)
+	}
+
+	// Vencida la ventana la clave se puede reutilizar.
+	if resp, err := s.Begin(ctx, "u1", "k", other, now.Add(2*time.Hour), time.Hour); resp != nil || err != nil {
+		t.Fatalf("Begin after expiry = (%v, %v), want (nil, nil)", resp, err)
+	}
+}
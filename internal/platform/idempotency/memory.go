@@ -0,0 +1,53 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore guarda las claves en el proceso. Sirve para tests o una sola
+// instancia.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+func (s *MemoryStore) Begin(_ context.Context, scope, key, requestHash string, now time.Time, ttl time.Duration) (*Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := scope + "\x00" + key
+	if rec, ok := s.records[id]; ok && now.Before(rec.ExpiresAt) {
+		return rec.result(requestHash)
+	}
+
+	s.records[id] = Record{Scope: scope, Key: key, RequestHash: requestHash, CreatedAt: now, ExpiresAt: now.Add(ttl)}
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, scope, key string, resp Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := scope + "\x00" + key
+	rec, ok := s.records[id]
+	if !ok {
+		return nil
+	}
+	rec.Status, rec.ContentType, rec.Body = resp.Status, resp.ContentType, resp.Body
+	s.records[id] = rec
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, scope, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, scope+"\x00"+key)
+	return nil
+}
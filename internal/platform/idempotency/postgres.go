@@ -0,0 +1,107 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Record es una clave reservada. Status en 0 significa que el pedido sigue
+// en curso.
+type Record struct {
+	Scope       string    `gorm:"primaryKey;type:varchar(64)"`
+	Key         string    `gorm:"primaryKey;type:varchar(255)"`
+	RequestHash string    `gorm:"type:char(64);not null"`
+	Status      int       `gorm:"not null;default:0"`
+	ContentType string    `gorm:"type:varchar(100)"`
+	Body        []byte    `gorm:"type:bytea"`
+	CreatedAt   time.Time `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
+
+func (Record) TableName() string {
+	return "idempotency_keys"
+}
+
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+const pruneProbability = 0.01
+
+func (s *PostgresStore) Begin(ctx context.Context, scope, key, requestHash string, now time.Time, ttl time.Duration) (*Response, error) {
+	db := s.db.WithContext(ctx)
+
+	if rand.Float64() < pruneProbability {
+		db.Where("expires_at < ?", now).Delete(&Record{})
+	}
+
+	// Una clave vencida se puede volver a usar.
+	if err := db.Where("scope = ? AND key = ? AND expires_at < ?", scope, key, now).
+		Delete(&Record{}).Error; err != nil {
+		return nil, fmt.Errorf("idempotency: delete expired: %w", err)
+	}
+
+	rec := Record{Scope: scope, Key: key, RequestHash: requestHash, CreatedAt: now, ExpiresAt: now.Add(ttl)}
+	res := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rec)
+	if res.Error != nil {
+		return nil, fmt.Errorf("idempotency: reserve: %w", res.Error)
+	}
+	if res.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing Record
+	if err := db.Where("scope = ? AND key = ?", scope, key).First(&existing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Se liberó entre el insert y la lectura: el cliente puede reintentar.
+			return nil, ErrInProgress
+		}
+		return nil, fmt.Errorf("idempotency: load: %w", err)
+	}
+
+	return existing.result(requestHash)
+}
+
+func (r Record) result(requestHash string) (*Response, error) {
+	if r.RequestHash != requestHash {
+		return nil, ErrKeyReused
+	}
+	if r.Status == 0 {
+		return nil, ErrInProgress
+	}
+	return &Response{Status: r.Status, ContentType: r.ContentType, Body: r.Body}, nil
+}
+
+func (s *PostgresStore) Complete(ctx context.Context, scope, key string, resp Response) error {
+	err := s.db.WithContext(ctx).Model(&Record{}).
+		Where("scope = ? AND key = ?", scope, key).
+		Updates(map[string]any{
+			"status":       resp.Status,
+			"content_type": resp.ContentType,
+			"body":         resp.Body,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("idempotency: complete: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) Release(ctx context.Context, scope, key string) error {
+	err := s.db.WithContext(ctx).
+		Where("scope = ? AND key = ?", scope, key).
+		Delete(&Record{}).Error
+	if err != nil {
+		return fmt.Errorf("idempotency: release: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package ratelimit
+
+import "time"
+
+// Policies agrupa los límites que usa el router.
+type Policies struct {
+	// Global aplica por IP a toda la API.
+	Global Policy
+	// Auth aplica por IP a login, registro y recuperación de contraseña.
+	Auth Policy
+	// User aplica por usuario autenticado.
+	User Policy
+	// Submit aplica por usuario a la carga de comprobantes, que llama a APIs externas.
+	Submit Policy
+}
+
+func DefaultPolicies() Policies {
+	return Policies{
+		Global: Policy{Name: "global", Capacity: 120, Period: time.Minute},
+		Auth:   Policy{Name: "auth", Capacity: 20, Period: 5 * time.Minute},
+		User:   Policy{Name: "user", Capacity: 60, Period: time.Minute},
+		Submit: Policy{Name: "submit", Capacity: 10, Period: 10 * time.Minute},
+	}
+}
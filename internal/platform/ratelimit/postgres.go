@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Bucket es el estado persistido de un bucket.
+type Bucket struct {
+	Key       string    `gorm:"primaryKey;type:varchar(200)"`
+	Tokens    float64   `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null;index"`
+}
+
+func (Bucket) TableName() string {
+	return "rate_limit_buckets"
+}
+
+// PostgresStore comparte los buckets entre instancias. Cada Take bloquea la
+// fila de su clave, así que dos instancias no pueden gastar el mismo token.
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// pruneProbability y pruneIdle controlan la limpieza oportunista de buckets
+// viejos, para no depender de un cron.
+const (
+	pruneProbability = 0.001
+	pruneIdle        = 24 * time.Hour
+)
+
+func (s *PostgresStore) Take(ctx context.Context, key string, p Policy, now time.Time) (Decision, error) {
+	var d Decision
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		seed := Bucket{Key: key, Tokens: float64(p.Capacity), UpdatedAt: now}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&seed).Error; err != nil {
+			return err
+		}
+
+		var b Bucket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("key = ?", key).
+			First(&b).Error; err != nil {
+			return err
+		}
+
+		var tokens float64
+		tokens, d = take(b.Tokens, b.UpdatedAt, p, now)
+
+		return tx.Model(&Bucket{}).
+			Where("key = ?", key).
+			Updates(map[string]any{"tokens": tokens, "updated_at": now}).Error
+	})
+	if err != nil {
+		return Decision{}, fmt.Errorf("ratelimit: postgres take: %w", err)
+	}
+
+	if rand.Float64() < pruneProbability {
+		s.db.WithContext(ctx).Where("updated_at < ?", now.Add(-pruneIdle)).Delete(&Bucket{})
+	}
+
+	return d, nil
+}
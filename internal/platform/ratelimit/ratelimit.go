@@ -0,0 +1,69 @@
+// Package ratelimit implementa un limitador token bucket con almacenamiento
+// intercambiable: en memoria para una sola instancia o en Postgres para
+// compartir el estado entre instancias. Cualquier store con operaciones
+// atómicas por clave (por ejemplo Redis) puede implementar Store.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Policy define un bucket: Capacity pedidos de ráfaga que se recargan
+// completos a lo largo de Period.
+type Policy struct {
+	Name     string
+	Capacity int
+	Period   time.Duration
+}
+
+// ratePerSecond es la velocidad de recarga del bucket.
+func (p Policy) ratePerSecond() float64 {
+	return float64(p.Capacity) / p.Period.Seconds()
+}
+
+// Decision es el resultado de consumir un token.
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset es el tiempo hasta que el bucket vuelve a estar lleno.
+	Reset time.Duration
+	// RetryAfter es el tiempo hasta el próximo token cuando Allowed es false.
+	RetryAfter time.Duration
+}
+
+// Store consume un token del bucket identificado por key.
+type Store interface {
+	Take(ctx context.Context, key string, p Policy, now time.Time) (Decision, error)
+}
+
+// take aplica el algoritmo sobre el estado guardado y devuelve el nuevo
+// estado. Lo comparten todas las implementaciones de Store.
+func take(tokens float64, last time.Time, p Policy, now time.Time) (float64, Decision) {
+	capacity := float64(p.Capacity)
+	rate := p.ratePerSecond()
+
+	if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+		tokens = math.Min(capacity, tokens+elapsed*rate)
+	}
+
+	d := Decision{Limit: p.Capacity}
+
+	if tokens >= 1 {
+		tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+
+	d.Remaining = int(math.Floor(tokens))
+	d.Reset = secondsToDuration((capacity - tokens) / rate)
+
+	return tokens, d
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	t.Parallel()
+
+	s := NewMemoryStore()
+	p := Policy{Name: "test", Capacity: 3, Period: 3 * time.Second}
+	now := time.Unix(1700000000, 0)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		d, err := s.Take(ctx, "k", p, now)
+		if err != nil || !d.Allowed {
+			t.Fatalf("take %d = (%+v, %v), want allowed", i, d, err)
+		}
+		if d.Remaining != 2-i {
+			t.Fatalf("take %d remaining = %d, want %d", i, d.Remaining, 2-i)
+		}
+	}
+
+	d, _ := s.Take(ctx, "k", p, now)
+	if d.Allowed {
+		t.Fatal("4th take should be rejected")
+	}
+	if d.RetryAfter != time.Second {
+		t.Fatalf("RetryAfter = %v, want 1s", d.RetryAfter)
+	}
+
+	// Un segundo después se recargó un token.
+	d, _ = s.Take(ctx, "k", p, now.Add(time.Second))
+	if !d.Allowed {
+		t.Fatal("take after refill should be allowed")
+	}
+
+	// Otra clave tiene su propio bucket.
+	if d, _ := s.Take(ctx, "other", p, now); !d.Allowed {
+		t.Fatal("independent key should be allowed")
+	}
+}
+
+func TestTake_ResetAndCap(t *testing.T) {
+	t.Parallel()
+
+	p := Policy{Capacity: 10, Period: 10 * time.Second}
+	now := time.Unix(0, 0)
+
+	tokens, d := take(10, now, p, now)
+	if tokens != 9 || d.Reset != time.Second {
+		t.Fatalf("take(full) = (%v, reset %v), want (9, 1s)", tokens, d.Reset)
+	}
+
+	// Mucho tiempo después el bucket no supera la capacidad.
+	tokens, _ = take(0, now, p, now.Add(time.Hour))
+	if tokens != 9 {
+		t.Fatalf("tokens after long idle = %v, want 9", tokens)
+	}
+}
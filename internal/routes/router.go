@@ -12,6 +12,8 @@ import (
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/voucher"
 	"github.com/sebaactis/powermix-back-mobile/internal/middlewares"
 	"github.com/sebaactis/powermix-back-mobile/internal/platform/config"
+	"github.com/sebaactis/powermix-back-mobile/internal/platform/idempotency"
+	"github.com/sebaactis/powermix-back-mobile/internal/platform/ratelimit"
 	"github.com/sebaactis/powermix-back-mobile/internal/security/auth"
 	"github.com/sebaactis/powermix-back-mobile/internal/validations"
 )
@@ -19,6 +21,7 @@ import (
 type Deps struct {
 	UserHandler    *user.HTTPHandler
 	ProofHandler   *proof.HTTPHandler
+	WebhookHandler *proof.WebhookHandler
 	VoucherHandler *voucher.HTTPHandler
 	TokenHandler   *token.HTTPHandler
 	AuthHandler    *auth.HTTPHandler
@@ -26,7 +29,10 @@ type Deps struct {
 	Config         config.Config
 	Validator      *validations.Validator
 	RateLimiter    *middlewares.RateLimiter
+	RateLimits     ratelimit.Policies
+	Idempotency    idempotency.Store
 	AuthMiddleware *middlewares.AuthMiddleware
+	RoleResolver   middlewares.RoleResolver
 }
 
 func Router(d Deps) *chi.Mux {
@@ -38,25 +44,40 @@ func Router(d Deps) *chi.Mux {
 		middlewares.RequestLogger(slog.Default()),
 		middlewares.Recoverer(slog.Default()),
 		middlewares.JSONContentType(),
-		d.RateLimiter.Middleware(),
+		d.RateLimiter.ByIP(d.RateLimits.Global),
 		middlewares.Timeout(30*time.Second),
 	)
 
 	r.Route("/api/v1", func(r chi.Router) {
-		// Autenticación
-		r.Post("/register", d.UserHandler.Create)
-		r.Post("/login", d.AuthHandler.Login)
-		r.Post("/login-google", d.AuthHandler.OAuthGoogle)
-
-		// Password de usuario
-		r.Post("/recoveryPassword", d.AuthHandler.RecoveryPasswordRequest)
-		r.Post("/updatePasswordRecovery", d.AuthHandler.UpdatePasswordByRecovery)
+		// Autenticación, registro, recuperación y refresh: límite estricto por IP
+		r.Group(func(ar chi.Router) {
+			ar.Use(d.RateLimiter.ByIP(d.RateLimits.Auth))
+
+			ar.Post("/register", d.UserHandler.Create)
+			ar.Post("/login", d.AuthHandler.Login)
+			ar.Post("/login-google", d.AuthHandler.OAuthGoogle)
+			ar.Post("/login/email-code", d.AuthHandler.LoginEmailCode)
+			ar.Post("/login/email-code/verify", d.AuthHandler.LoginEmailCodeVerify)
+			ar.Post("/login/mfa", d.AuthHandler.LoginMFA)
+			ar.Post("/login/mfa/enroll", d.AuthHandler.LoginMFAEnroll)
+
+			// Password de usuario
+			ar.Post("/recoveryPassword", d.AuthHandler.RecoveryPasswordRequest)
+			ar.Post("/updatePasswordRecovery", d.AuthHandler.UpdatePasswordByRecovery)
+
+			// Token
+			ar.Post("/refreshToken", d.AuthHandler.RefreshToken)
+			ar.Post("/sessions/revoke", d.AuthHandler.RevokeSession)
+		})
 
-		// Token
-		r.Post("/refreshToken", d.AuthHandler.RefreshToken)
+		// Notificaciones de Mercado Pago (firmadas, sin JWT)
+		if d.WebhookHandler != nil {
+			r.Post("/webhooks/mercadopago", d.WebhookHandler.MercadoPago)
+		}
 
 		r.Group(func(pr chi.Router) {
 			pr.Use(d.AuthMiddleware.RequireAuth())
+			pr.Use(d.RateLimiter.ByUser(d.RateLimits.User))
 
 			// User
 			pr.Get("/user/{id}", d.UserHandler.GetByID)
@@ -64,14 +85,41 @@ func Router(d Deps) *chi.Mux {
 			pr.Put("/user/update", d.UserHandler.Update)
 			pr.Put("/user/change-password", d.UserHandler.UpdatePassword)
 			pr.Post("/user/contact", d.UserHandler.SendEmailContact)
+			pr.Post("/user/me/mfa/totp", d.UserHandler.StartTOTPEnrollment)
+			pr.Post("/user/me/mfa/totp/verify", d.UserHandler.ConfirmTOTPEnrollment)
+			pr.Delete("/user/me/mfa/totp", d.UserHandler.DisableTOTP)
+			pr.Post("/user/me/mfa/recovery-codes", d.UserHandler.RegenerateRecoveryCodes)
+			pr.Get("/user/me/identities", d.UserHandler.ListIdentities)
+			pr.Post("/user/me/identities/{provider}", d.UserHandler.LinkIdentity)
+			pr.Delete("/user/me/identities/{provider}", d.UserHandler.UnlinkIdentity)
 
 			// Proof
 			pr.Get("/proofs/me", d.ProofHandler.GetAllByUserID)
 			pr.Get("/proofs/me/paginated", d.ProofHandler.GetAllByUserIDPaginated)
 			pr.Get("/proofs/me/last3", d.ProofHandler.GetLastThreeByUserID)
+			pr.Get("/proofs/me/links", d.ProofHandler.ListPaymentLinks)
+			pr.Post("/proofs/me/links", d.ProofHandler.LinkPaymentIdentity)
+			pr.Delete("/proofs/me/links/{id}", d.ProofHandler.DeletePaymentLink)
+			pr.Get("/proofs/me/suggested", d.ProofHandler.ListSuggested)
+			pr.Post("/proofs/me/suggested/{id}/dismiss", d.ProofHandler.DismissSuggestion)
 			pr.Get("/proofs/me/{id}", d.ProofHandler.GetByID)
-			pr.Post("/proof", d.ProofHandler.Create)
-			pr.Post("/proof/others", d.ProofHandler.CreateFromOthers)
+			pr.Group(func(sr chi.Router) {
+				sr.Use(d.RateLimiter.ByUser(d.RateLimits.Submit), middlewares.Idempotency(d.Idempotency, proof.MaxReceiptSize))
+
+				sr.Post("/proof", d.ProofHandler.Create)
+				sr.Post("/proof/others", d.ProofHandler.CreateFromOthers)
+				sr.Post("/proof/others/select", d.ProofHandler.SelectPayment)
+				sr.Post("/proof/receipt", d.ProofHandler.CreateFromReceipt)
+				sr.Post("/proofs/me/suggested/{id}/claim", d.ProofHandler.ClaimSuggestion)
+			})
+
+			// Revisión antifraude de comprobantes (solo admins)
+			pr.Group(func(adm chi.Router) {
+				adm.Use(middlewares.RequireAdmin(d.RoleResolver))
+
+				adm.Get("/admin/proofs/review", d.ProofHandler.ListPendingReview)
+				adm.Post("/admin/proofs/{id}/review", d.ProofHandler.Review)
+			})
 
 			// Voucher
 			pr.Get("/voucher/me", d.VoucherHandler.GetAllByUserID)
@@ -80,22 +128,52 @@ func Router(d Deps) *chi.Mux {
 
 			// PRODE
 			if d.Config.IsProdeEnabled() {
-				pr.Get("/prode/matches", d.ProdeHandler.ListMatches)
+				pr.Get("/prode/tournaments", d.ProdeHandler.ListTournaments)
+				pr.Get("/prode/tournaments/{tournament}/matches", d.ProdeHandler.ListMatches)
+				pr.Get("/prode/tournaments/{tournament}/predictions/me", d.ProdeHandler.GetMyPredictions)
+				pr.Get("/prode/tournaments/{tournament}/leaderboard", d.ProdeHandler.GetLeaderboard)
+				pr.Get("/prode/tournaments/{tournament}/leaderboard/me", d.ProdeHandler.GetMyLeaderboardEntry)
+				pr.Get("/prode/leagues", d.ProdeHandler.ListMyLeagues)
+				pr.Post("/prode/leagues", d.ProdeHandler.CreateLeague)
+				pr.Post("/prode/leagues/join", d.ProdeHandler.JoinLeague)
+				pr.Get("/prode/leagues/{leagueID}", d.ProdeHandler.GetLeague)
+				pr.Get("/prode/leagues/{leagueID}/leaderboard", d.ProdeHandler.GetLeagueLeaderboard)
+				pr.Delete("/prode/leagues/{leagueID}/members/me", d.ProdeHandler.LeaveLeague)
+				pr.Delete("/prode/leagues/{leagueID}/members/{userID}", d.ProdeHandler.KickLeagueMember)
+				pr.Get("/prode/reminders", d.ProdeHandler.GetReminderPreference)
+				pr.Put("/prode/reminders", d.ProdeHandler.SetReminderPreference)
+				pr.Get("/prode/predictions/me/{matchID}/history", d.ProdeHandler.GetPredictionHistory)
+				// Rutas anteriores a los torneos, apuntan al torneo activo.
+				pr.Get("/prode/matches", d.ProdeHandler.ActiveTournamentAlias(d.ProdeHandler.ListMatches, "matches"))
+				pr.Get("/prode/predictions/me", d.ProdeHandler.ActiveTournamentAlias(d.ProdeHandler.GetMyPredictions, "predictions/me"))
 				pr.Get("/prode/matches/{matchID}", d.ProdeHandler.GetMatch)
 				pr.Put("/prode/matches/{matchID}/prediction", d.ProdeHandler.CreateOrUpdatePrediction)
-				pr.Get("/prode/predictions/me", d.ProdeHandler.GetMyPredictions)
 			}
 		})
 
 		// PRODE Admin — protegido por maintenance key
 		if d.Config.IsProdeEnabled() {
 			r.Group(func(ar chi.Router) {
-				ar.Use(middlewares.MaintenanceKey(d.Config))
+				ar.Use(middlewares.MaintenanceKey(d.Config), middlewares.Idempotency(d.Idempotency, 0))
 
+				ar.Get("/prode/admin/tournaments", d.ProdeHandler.AdminListTournaments)
+				ar.Post("/prode/admin/tournaments", d.ProdeHandler.AdminCreateTournament)
+				ar.Patch("/prode/admin/tournaments/{tournamentID}", d.ProdeHandler.AdminUpdateTournament)
+				ar.Get("/prode/admin/teams", d.ProdeHandler.AdminListTeams)
+				ar.Post("/prode/admin/teams", d.ProdeHandler.AdminCreateTeam)
 				ar.Post("/prode/admin/matches", d.ProdeHandler.AdminCreateMatch)
 				ar.Patch("/prode/admin/matches/{matchID}", d.ProdeHandler.AdminUpdateMatch)
 				ar.Put("/prode/admin/matches/{matchID}/result", d.ProdeHandler.AdminRecordResult)
+				ar.Post("/prode/admin/matches/{matchID}/result/confirm", d.ProdeHandler.AdminConfirmResult)
+				ar.Put("/prode/admin/matches/{matchID}/result/correction", d.ProdeHandler.AdminCorrectResult)
+				ar.Get("/prode/admin/matches/{matchID}/transitions", d.ProdeHandler.AdminGetMatchTransitions)
 				ar.Post("/prode/admin/matches/{matchID}/settle", d.ProdeHandler.AdminSettleMatch)
+				ar.Get("/prode/admin/matches/{matchID}/settlement", d.ProdeHandler.AdminGetSettlementStatus)
+				ar.Get("/prode/admin/matches/{matchID}/stats", d.ProdeHandler.AdminGetMatchStats)
+				ar.Get("/prode/admin/matches/{matchID}/winners.csv", d.ProdeHandler.AdminExportWinners)
+				ar.Get("/prode/admin/leagues", d.ProdeHandler.AdminListLeagues)
+				ar.Put("/prode/admin/leagues/{leagueID}/prizes", d.ProdeHandler.AdminSetLeaguePrizes)
+				ar.Post("/prode/admin/leagues/{leagueID}/prizes/award", d.ProdeHandler.AdminAwardLeaguePrizes)
 				ar.Post("/prode/admin/rewards/retry", d.ProdeHandler.AdminRetryPendingRewards)
 			})
 		}
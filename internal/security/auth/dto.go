@@ -1,6 +1,10 @@
 package auth
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email,min=6,max=32"`
@@ -13,6 +17,26 @@ type LoginResponse struct {
 	StampsCounter int    `json:"stampsCounter"`
 	Token         string `json:"token"`
 	RefreshToken  string `json:"refreshToken"`
+	// RecoveryCodes solo viaja cuando el login completó un enrolamiento de 2FA.
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+}
+
+// MFAChallengeResponse reemplaza al par de tokens cuando la cuenta exige segundo factor.
+type MFAChallengeResponse struct {
+	MFARequired        bool      `json:"mfaRequired"`
+	MFAToken           string    `json:"mfaToken"`
+	EnrollmentRequired bool      `json:"enrollmentRequired"`
+	ExpiresAt          time.Time `json:"expiresAt"`
+}
+
+type LoginMFARequest struct {
+	MFAToken     string `json:"mfaToken"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recoveryCode"`
+}
+
+type LoginMFAEnrollRequest struct {
+	MFAToken string `json:"mfaToken"`
 }
 
 type RecoveryPasswordRequest struct {
@@ -26,3 +50,7 @@ type RecoveryPasswordRequestResponse struct {
 type UnlockUserReq struct {
 	UserId uuid.UUID `json:"userId"`
 }
+
+type RevokeSessionRequest struct {
+	Token string `json:"token"`
+}
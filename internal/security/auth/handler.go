@@ -6,14 +6,20 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/sebaactis/powermix-back-mobile/internal/clients/mailer"
+	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/loginevent"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/token"
 	"github.com/sebaactis/powermix-back-mobile/internal/domain/entities/user"
+	"github.com/sebaactis/powermix-back-mobile/internal/middlewares"
 	jwtx "github.com/sebaactis/powermix-back-mobile/internal/security/jwt"
 	"github.com/sebaactis/powermix-back-mobile/internal/security/oauth"
 	"github.com/sebaactis/powermix-back-mobile/internal/utils"
@@ -27,20 +33,28 @@ type HTTPHandler struct {
 	jwt       *jwtx.JWT
 	validator validations.StructValidator
 	mailer    mailer.Mailer
+	events    *loginevent.Service
+	geo       loginevent.GeoResolver
 }
 
 func NewHTTPHandler(users *user.Service,
 	tokens *token.Service,
 	jwt *jwtx.JWT,
 	validator validations.StructValidator,
-	mailer mailer.Mailer) *HTTPHandler {
+	mailer mailer.Mailer,
+	events *loginevent.Service,
+	geo loginevent.GeoResolver) *HTTPHandler {
 	return &HTTPHandler{users: users,
 		tokens:    tokens,
 		jwt:       jwt,
 		validator: validator,
-		mailer:    mailer}
+		mailer:    mailer,
+		events:    events,
+		geo:       geo}
 }
 
+const sessionRevokeURL = "https://powermixstation.com.ar/revoke-session"
+
 func (h *HTTPHandler) Login(w http.ResponseWriter, r *http.Request) {
 	req, err := h.parseLoginRequest(r)
 	if err != nil {
@@ -51,15 +65,191 @@ func (h *HTTPHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.rejectIfIPLocked(w, r) {
+		return
+	}
+
 	user, err := h.authenticateUser(r.Context(), req)
 	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			h.recordFailure(r, user, req.Email, loginevent.MethodPassword)
+		}
 		h.handleLoginError(w, r.Context(), err, user, req.Email)
 		return
 	}
 
-	tokens, err := h.generateTokens(r.Context(), user)
+	h.completeLogin(w, r, user, loginevent.MethodPassword)
+}
+
+// LoginEmailCode envía un código de acceso (y link mágico) al email indicado.
+// La respuesta es la misma exista o no la cuenta.
+func (h *HTTPHandler) LoginEmailCode(w http.ResponseWriter, r *http.Request) {
+	var req user.EmailCodeRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeValidation,
+			Message: "No se pudo parsear el cuerpo de la solicitud",
+		})
+		return
+	}
+
+	if err := h.users.RequestEmailLoginCode(r.Context(), req); err != nil {
+		if fields, ok := validations.AsValidationError(err); ok {
+			utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+				Code:    utils.ErrCodeValidation,
+				Message: "Error de validación",
+				Fields:  fields,
+			})
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al enviar código de acceso", "error", err)
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, map[string]any{
+		"email":   req.Email,
+		"message": "Si el email está registrado, recibirá un código de acceso",
+	})
+}
+
+// LoginEmailCodeVerify canjea el código enviado por email por el mismo
+// resultado que Login (tokens o challenge de 2FA).
+func (h *HTTPHandler) LoginEmailCodeVerify(w http.ResponseWriter, r *http.Request) {
+	var req user.EmailCodeVerifyRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeValidation,
+			Message: "No se pudo parsear el cuerpo de la solicitud",
+		})
+		return
+	}
+
+	if h.rejectIfIPLocked(w, r) {
+		return
+	}
+
+	u, err := h.users.VerifyEmailLoginCode(r.Context(), req)
 	if err != nil {
-		slog.ErrorContext(r.Context(), "error al generar tokens en login", "user_id", user.ID, "error", err)
+		if fields, ok := validations.AsValidationError(err); ok {
+			utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+				Code:    utils.ErrCodeValidation,
+				Message: "Error de validación",
+				Fields:  fields,
+			})
+			return
+		}
+		if errors.Is(err, user.ErrEmailCodeInvalid) {
+			h.recordFailure(r, u, req.Email, loginevent.MethodEmailCode)
+			utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+				Code:    utils.ErrCodeInvalidCreds,
+				Message: "Código inválido o expirado",
+			})
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al verificar código de acceso", "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeInternal,
+			Message: "Error interno del servidor",
+		})
+		return
+	}
+
+	if u.LockedUntil.After(time.Now()) {
+		utils.WriteError(w, http.StatusLocked, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeInvalidCreds,
+			Message: "Cuenta temporalmente bloqueada",
+		})
+		return
+	}
+
+	h.completeLogin(w, r, u, loginevent.MethodEmailCode)
+}
+
+// RevokeSession atiende el link "No fui yo" del aviso de login: revoca la
+// familia de refresh tokens de esa sesión.
+func (h *HTTPHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	var req RevokeSessionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeValidation,
+			Message: "El token es requerido",
+		})
+		return
+	}
+
+	userID, err := h.tokens.RevokeSession(r.Context(), req.Token)
+	if err != nil {
+		if errors.Is(err, token.ErrTokenInvalid) {
+			utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+				Code:    utils.ErrCodeUnauthorized,
+				Message: "El link es inválido, ya fue usado o expiró",
+			})
+			return
+		}
+		slog.ErrorContext(r.Context(), "error al revocar sesión", "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeInternal,
+			Message: "Error interno del servidor",
+		})
+		return
+	}
+
+	slog.InfoContext(r.Context(), "sesión revocada por el usuario", "user_id", userID)
+	utils.WriteSuccess(w, http.StatusOK, map[string]any{
+		"message": "Cerramos esa sesión. Te recomendamos cambiar tu contraseña",
+	})
+}
+
+// LoginMFA es el segundo paso del login: canjea el challenge más un código
+// TOTP (o de recuperación) por el par de tokens.
+func (h *HTTPHandler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	var req LoginMFARequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeValidation,
+			Message: "No se pudo parsear el cuerpo de la solicitud",
+		})
+		return
+	}
+
+	if h.rejectIfIPLocked(w, r) {
+		return
+	}
+
+	u, ok := h.userFromMFAChallenge(w, r, req.MFAToken)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	codeReq := user.MFACodeRequest{Code: strings.TrimSpace(req.Code), RecoveryCode: req.RecoveryCode}
+
+	var (
+		recoveryCodes []string
+		err           error
+	)
+
+	if u.TOTPEnabled {
+		err = h.users.VerifySecondFactor(ctx, u.ID, codeReq)
+	} else {
+		// Administrador sin 2FA: el login solo se completa confirmando el enrolamiento.
+		recoveryCodes, err = h.users.ConfirmTOTPEnrollment(ctx, u.ID, codeReq.Code)
+	}
+
+	if err != nil {
+		if errors.Is(err, user.ErrMFAInvalidCode) {
+			h.recordFailure(r, u, u.Email, loginevent.MethodMFA)
+		}
+		h.handleMFAError(w, ctx, err, u)
+		return
+	}
+
+	tokens, err := h.generateTokens(ctx, u)
+	if err != nil {
+		slog.ErrorContext(ctx, "error al generar tokens en login mfa", "user_id", u.ID, "error", err)
 		utils.WriteError(w, http.StatusInternalServerError, utils.WriteErrorOpts{
 			Code:    utils.ErrCodeInternal,
 			Message: "No se pueden generar los tokens",
@@ -67,7 +257,35 @@ func (h *HTTPHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.respondWithTokens(w, user, tokens)
+	h.recordSuccess(r, u, loginevent.MethodMFA, tokens.FamilyID)
+	h.respondWithTokens(w, u, tokens, recoveryCodes)
+}
+
+// LoginMFAEnroll entrega el secreto TOTP a un administrador que todavía no
+// configuró el segundo factor, usando el challenge del login.
+func (h *HTTPHandler) LoginMFAEnroll(w http.ResponseWriter, r *http.Request) {
+	var req LoginMFAEnrollRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeValidation,
+			Message: "No se pudo parsear el cuerpo de la solicitud",
+		})
+		return
+	}
+
+	u, ok := h.userFromMFAChallenge(w, r, req.MFAToken)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.users.StartTOTPEnrollment(r.Context(), u.ID)
+	if err != nil {
+		h.handleMFAError(w, r.Context(), err, u)
+		return
+	}
+
+	utils.WriteSuccess(w, http.StatusOK, enrollment)
 }
 
 func (h *HTTPHandler) OAuthGoogle(w http.ResponseWriter, r *http.Request) {
@@ -97,6 +315,13 @@ func (h *HTTPHandler) OAuthGoogle(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.users.FindOrCreateFromOAuth(r.Context(), userInfo)
 	if err != nil {
+		if isOAuthLinkConflict(err) {
+			utils.WriteError(w, http.StatusConflict, utils.WriteErrorOpts{
+				Code:    utils.ErrCodeConflict,
+				Message: "Ya existe una cuenta con este email. Iniciá sesión y vinculá Google desde tu perfil",
+			})
+			return
+		}
 		slog.ErrorContext(r.Context(), "error al guardar usuario OAuth", "email", userInfo.Email, "error", err)
 		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
 			Code:    utils.ErrCodeUnauthorized,
@@ -105,6 +330,11 @@ func (h *HTTPHandler) OAuthGoogle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if user.RequiresMFA() {
+		h.respondWithMFAChallenge(w, r, user)
+		return
+	}
+
 	accessToken, accessExpiration, err := h.jwt.Sign(user.ID, user.Email, jwtx.TokenTypeAccess)
 	if err != nil {
 		slog.ErrorContext(r.Context(), "error al generar access token OAuth", "user_id", user.ID, "error", err)
@@ -125,7 +355,8 @@ func (h *HTTPHandler) OAuthGoogle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err = h.tokens.CreateInitialRefreshToken(r.Context(), user.ID, refreshToken, refreshExpiration); err != nil {
+	refreshRow, err := h.tokens.CreateInitialRefreshToken(r.Context(), user.ID, refreshToken, refreshExpiration)
+	if err != nil {
 		slog.ErrorContext(r.Context(), "error al persistir refresh token OAuth", "user_id", user.ID, "error", err)
 		utils.WriteError(w, http.StatusInternalServerError, utils.WriteErrorOpts{
 			Code:    utils.ErrCodeInternal,
@@ -134,6 +365,8 @@ func (h *HTTPHandler) OAuthGoogle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.recordSuccess(r, user, loginevent.MethodOAuth, refreshRow.FamilyID)
+
 	utils.WriteSuccess(w, http.StatusOK, map[string]any{
 		"user": map[string]interface{}{
 			"id":    user.ID,
@@ -418,13 +651,15 @@ func (h *HTTPHandler) generateTokens(ctx context.Context, user *user.User) (*Tok
 		return nil, err
 	}
 
-	if _, err = h.tokens.CreateInitialRefreshToken(ctx, user.ID, refreshToken, expirationRefresh); err != nil {
+	refreshRow, err := h.tokens.CreateInitialRefreshToken(ctx, user.ID, refreshToken, expirationRefresh)
+	if err != nil {
 		return nil, err
 	}
 
 	return &TokenPair{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
+		FamilyID:     refreshRow.FamilyID,
 	}, nil
 }
 
@@ -478,13 +713,14 @@ func (h *HTTPHandler) handleLoginError(w http.ResponseWriter, ctx context.Contex
 	}
 }
 
-func (h *HTTPHandler) respondWithTokens(w http.ResponseWriter, user *user.User, tokens *TokenPair) {
+func (h *HTTPHandler) respondWithTokens(w http.ResponseWriter, user *user.User, tokens *TokenPair, recoveryCodes []string) {
 	response := LoginResponse{
 		Email:         user.Email,
 		Name:          user.Name,
 		StampsCounter: user.StampsCounter,
 		Token:         tokens.AccessToken,
 		RefreshToken:  tokens.RefreshToken,
+		RecoveryCodes: recoveryCodes,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -492,9 +728,229 @@ func (h *HTTPHandler) respondWithTokens(w http.ResponseWriter, user *user.User,
 	json.NewEncoder(w).Encode(response)
 }
 
+// completeLogin cierra cualquier login exitoso del primer factor: entrega los
+// tokens o, si la cuenta exige 2FA, el challenge para el segundo paso.
+func (h *HTTPHandler) completeLogin(w http.ResponseWriter, r *http.Request, u *user.User, method string) {
+	if u.RequiresMFA() {
+		h.respondWithMFAChallenge(w, r, u)
+		return
+	}
+
+	tokens, err := h.generateTokens(r.Context(), u)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al generar tokens en login", "user_id", u.ID, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeInternal,
+			Message: "No se pueden generar los tokens",
+		})
+		return
+	}
+
+	h.recordSuccess(r, u, method, tokens.FamilyID)
+	h.respondWithTokens(w, u, tokens, nil)
+}
+
+func (h *HTTPHandler) respondWithMFAChallenge(w http.ResponseWriter, r *http.Request, u *user.User) {
+	mfaToken, exp, err := h.jwt.Sign(u.ID, u.Email, jwtx.TokenTypeMFAChallenge)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al generar challenge mfa", "user_id", u.ID, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeInternal,
+			Message: "No se pueden generar los tokens",
+		})
+		return
+	}
+
+	response := MFAChallengeResponse{
+		MFARequired:        true,
+		MFAToken:           mfaToken,
+		EnrollmentRequired: !u.TOTPEnabled,
+		ExpiresAt:          exp,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// userFromMFAChallenge valida el challenge y devuelve el usuario, respetando el
+// bloqueo por intentos fallidos. Si algo falla ya escribió la respuesta.
+func (h *HTTPHandler) userFromMFAChallenge(w http.ResponseWriter, r *http.Request, mfaToken string) (*user.User, bool) {
+	userID, _, _, err := h.jwt.Parse(mfaToken, jwtx.TokenTypeMFAChallenge)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeUnauthorized,
+			Message: "La verificación expiró, iniciá sesión nuevamente",
+		})
+		return nil, false
+	}
+
+	u, err := h.users.GetByID(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeUnauthorized,
+			Message: "La verificación expiró, iniciá sesión nuevamente",
+		})
+		return nil, false
+	}
+
+	if u.LockedUntil.After(time.Now()) {
+		utils.WriteError(w, http.StatusLocked, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeInvalidCreds,
+			Message: "Cuenta temporalmente bloqueada",
+		})
+		return nil, false
+	}
+
+	return u, true
+}
+
+func (h *HTTPHandler) handleMFAError(w http.ResponseWriter, ctx context.Context, err error, u *user.User) {
+	if fields, ok := validations.AsValidationError(err); ok {
+		utils.WriteError(w, http.StatusBadRequest, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeValidation,
+			Message: "Error de validación",
+			Fields:  fields,
+		})
+		return
+	}
+
+	switch {
+	case errors.Is(err, user.ErrMFAInvalidCode):
+		// Los códigos fallidos cuentan para el mismo bloqueo que la contraseña.
+		h.users.IncrementLoginAttempt(ctx, u.ID)
+		utils.WriteError(w, http.StatusUnauthorized, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeInvalidCreds,
+			Message: "Código inválido",
+		})
+	case errors.Is(err, user.ErrMFANotEnrolled):
+		utils.WriteError(w, http.StatusConflict, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeConflict,
+			Message: "Primero debés configurar la app autenticadora",
+		})
+	case errors.Is(err, user.ErrMFAAlreadyEnabled):
+		utils.WriteError(w, http.StatusConflict, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeConflict,
+			Message: "El segundo factor ya está activo",
+		})
+	default:
+		slog.ErrorContext(ctx, "error interno en login mfa", "user_id", u.ID, "error", err)
+		utils.WriteError(w, http.StatusInternalServerError, utils.WriteErrorOpts{
+			Code:    utils.ErrCodeInternal,
+			Message: "Error interno del servidor",
+		})
+	}
+}
+
+// rejectIfIPLocked corta el intento si la IP acumula demasiados fallos
+// recientes. Si la consulta falla se deja pasar: el bloqueo por cuenta sigue activo.
+func (h *HTTPHandler) rejectIfIPLocked(w http.ResponseWriter, r *http.Request) bool {
+	wait, err := h.events.IPLockout(r.Context(), middlewares.ClientIP(r))
+	if err != nil {
+		slog.ErrorContext(r.Context(), "error al consultar bloqueo por IP", "error", err)
+		return false
+	}
+	if wait <= 0 {
+		return false
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	utils.WriteError(w, http.StatusTooManyRequests, utils.WriteErrorOpts{
+		Code:    utils.ErrCodeInvalidCreds,
+		Message: "Demasiados intentos fallidos. Probá de nuevo más tarde",
+	})
+	return true
+}
+
+func (h *HTTPHandler) loginAttempt(r *http.Request, userID *uuid.UUID, email, method string) loginevent.Attempt {
+	attempt := loginevent.Attempt{
+		UserID:    userID,
+		Email:     email,
+		IP:        middlewares.ClientIP(r),
+		UserAgent: r.UserAgent(),
+		DeviceID:  r.Header.Get("X-Device-Id"),
+		Method:    method,
+	}
+	if h.geo != nil {
+		attempt.Country = h.geo.Country(r)
+	}
+	return attempt
+}
+
+func (h *HTTPHandler) recordFailure(r *http.Request, u *user.User, email, method string) {
+	var userID *uuid.UUID
+	if u != nil {
+		userID = &u.ID
+	}
+
+	if err := h.events.RecordFailure(r.Context(), h.loginAttempt(r, userID, email, method)); err != nil {
+		slog.ErrorContext(r.Context(), "error al registrar login fallido", "error", err)
+	}
+}
+
+// recordSuccess se llama cuando se emiten tokens: reinicia el backoff, guarda
+// el evento y, si el dispositivo o país es nuevo, avisa por email.
+func (h *HTTPHandler) recordSuccess(r *http.Request, u *user.User, method string, familyID uuid.UUID) {
+	ctx := r.Context()
+
+	if err := h.users.ResetLoginAttempts(ctx, u); err != nil {
+		slog.ErrorContext(ctx, "error al reiniciar intentos de login", "user_id", u.ID, "error", err)
+	}
+
+	attempt := h.loginAttempt(r, &u.ID, u.Email, method)
+	anomaly, err := h.events.RecordSuccess(ctx, attempt)
+	if err != nil {
+		slog.ErrorContext(ctx, "error al registrar login", "user_id", u.ID, "error", err)
+		return
+	}
+
+	if !anomaly.Any() {
+		return
+	}
+
+	slog.InfoContext(ctx, "login desde dispositivo o país nuevo",
+		"user_id", u.ID, "new_device", anomaly.NewDevice, "new_country", anomaly.NewCountry)
+
+	// El aviso no debe demorar la respuesta del login.
+	go h.sendLoginAlert(context.WithoutCancel(ctx), u, attempt, familyID)
+}
+
+func (h *HTTPHandler) sendLoginAlert(ctx context.Context, u *user.User, attempt loginevent.Attempt, familyID uuid.UUID) {
+	raw, err := token.NewOpaqueToken()
+	if err != nil {
+		slog.ErrorContext(ctx, "error al generar link de revocación", "user_id", u.ID, "error", err)
+		return
+	}
+
+	expiresAt := time.Now().Add(h.jwt.GetTTL(jwtx.TokenTypeRefresh))
+	if err := h.tokens.CreateSessionRevokeToken(ctx, u.ID, familyID, raw, expiresAt); err != nil {
+		slog.ErrorContext(ctx, "error al guardar link de revocación", "user_id", u.ID, "error", err)
+		return
+	}
+
+	alert := mailer.LoginAlert{
+		IP:        attempt.IP,
+		Country:   attempt.Country,
+		UserAgent: attempt.UserAgent,
+		At:        time.Now(),
+	}
+	revokeURL := sessionRevokeURL + "?token=" + url.QueryEscape(raw)
+
+	if err := h.mailer.SendLoginAlertEmail(ctx, u.Email, alert, revokeURL); err != nil {
+		slog.ErrorContext(ctx, "error al enviar aviso de login", "user_id", u.ID, "error", err)
+	}
+}
+
+// isOAuthLinkConflict indica que el email ya tiene cuenta y la identidad no
+// puede vincularse sola (email no verificado u otra cuenta del proveedor).
+func isOAuthLinkConflict(err error) bool {
+	return errors.Is(err, user.ErrIdentityLinkRequired) || errors.Is(err, user.ErrIdentityAlreadyLinked)
+}
+
 type TokenPair struct {
 	AccessToken  string
 	RefreshToken string
+	FamilyID     uuid.UUID
 }
 
 var (
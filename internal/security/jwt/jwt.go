@@ -22,6 +22,7 @@ type JWT struct {
 	ttlReset     time.Duration
 	ttlNormal    time.Duration
 	ttlRefresh   time.Duration
+	ttlMFA       time.Duration
 }
 
 func NewJWT() (*JWT, error) {
@@ -38,6 +39,7 @@ func NewJWT() (*JWT, error) {
 	ttlMin := 60
 	ttlMinReset := 15
 	ttlMinRefresh := 1440
+	ttlMinMFA := 5
 
 	if s := os.Getenv("JWT_TTL_MINUTES"); s != "" {
 		if n, err := strconv.Atoi(s); err == nil && n > 0 {
@@ -57,12 +59,19 @@ func NewJWT() (*JWT, error) {
 		}
 	}
 
+	if s := os.Getenv("JWT_TTL_MFA_MINUTES"); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n > 0 {
+			ttlMinMFA = n
+		}
+	}
+
 	return &JWT{
 		secret:       []byte(sec),
 		reset_secret: []byte(resetSec),
 		ttlReset:     time.Duration(ttlMinReset) * time.Minute,
 		ttlNormal:    time.Duration(ttlMin) * time.Minute,
 		ttlRefresh:   time.Duration(ttlMinRefresh) * time.Minute,
+		ttlMFA:       time.Duration(ttlMinMFA) * time.Minute,
 	}, nil
 }
 
@@ -151,6 +160,10 @@ func (j *JWT) getExpiration(tokenType TokenType, now time.Time) time.Time {
 		return now.Add(j.ttlReset)
 	}
 
+	if tokenType == TokenTypeMFAChallenge {
+		return now.Add(j.ttlMFA)
+	}
+
 	return now.Add(j.ttlRefresh)
 }
 
@@ -159,6 +172,8 @@ func (j *JWT) GetTTL(tokenType TokenType) time.Duration {
 
 	if tokenType == TokenTypeAccess {
 		ttl = j.ttlNormal
+	} else if tokenType == TokenTypeMFAChallenge {
+		ttl = j.ttlMFA
 	} else {
 		ttl = j.ttlRefresh
 	}
@@ -6,4 +6,16 @@ const (
 	TokenTypeAccess        TokenType = "access"
 	TokenTypeRefresh       TokenType = "refresh"
 	TokenTypeResetPassword TokenType = "resetPassword"
+	// TokenTypeMFAChallenge identifica el token corto que se entrega tras validar
+	// la contraseña cuando el usuario todavía debe presentar el segundo factor.
+	TokenTypeMFAChallenge TokenType = "mfaChallenge"
+	// TokenTypeMFARecovery no es un JWT: es el tipo con el que se persisten los
+	// códigos de recuperación de 2FA en la tabla de tokens.
+	TokenTypeMFARecovery TokenType = "mfaRecovery"
+	// TokenTypeEmailLogin es el código de acceso sin contraseña enviado por email.
+	// Igual que los códigos de recuperación, se persiste hasheado y no es un JWT.
+	TokenTypeEmailLogin TokenType = "emailLogin"
+	// TokenTypeSessionRevoke es el link del aviso "¿fuiste vos?": guarda la
+	// familia de refresh tokens que revoca.
+	TokenTypeSessionRevoke TokenType = "sessionRevoke"
 )
@@ -1,10 +1,12 @@
 package oauth
 
 type OAuthUserInfo struct {
-	Provider string
+	Provider   string
 	ProviderID string
-	Email string
-	Name string
+	Email      string
+	Name       string
 	PictureURL string
+	// EmailVerified indica si el proveedor garantiza que el email es del usuario.
+	// Solo así se permite vincular automáticamente con una cuenta existente.
+	EmailVerified bool
 }
-
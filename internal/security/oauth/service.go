@@ -3,10 +3,25 @@ package oauth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 )
 
+const ProviderGoogle = "google"
+
+var ErrUnsupportedProvider = errors.New("oauth: proveedor no soportado")
+
+// GetUserInfo valida el access token contra el proveedor indicado.
+func GetUserInfo(ctx context.Context, provider, accessToken string) (*OAuthUserInfo, error) {
+	switch provider {
+	case ProviderGoogle:
+		return GetGoogleUserInfo(ctx, accessToken)
+	default:
+		return nil, ErrUnsupportedProvider
+	}
+}
+
 
 func GetGoogleUserInfo(ctx context.Context, accessToken string) (*OAuthUserInfo, error) {
 	req, _ := http.NewRequestWithContext(ctx, "GET", "https://www.googleapis.com/oauth2/v2/userinfo", nil)
@@ -31,6 +46,8 @@ func GetGoogleUserInfo(ctx context.Context, accessToken string) (*OAuthUserInfo,
 		Email   string `json:"email"`
 		Name    string `json:"name"`
 		Picture string `json:"picture"`
+		// VerifiedEmail viene de la API v2 de userinfo.
+		VerifiedEmail bool `json:"verified_email"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
@@ -38,10 +55,11 @@ func GetGoogleUserInfo(ctx context.Context, accessToken string) (*OAuthUserInfo,
 	}
 
 	return &OAuthUserInfo{
-		Provider:   "google",
-		ProviderID: data.ID,
-		Email:      data.Email,
-		Name:       data.Name,
-		PictureURL: data.Picture,
+		Provider:      ProviderGoogle,
+		ProviderID:    data.ID,
+		Email:         data.Email,
+		Name:          data.Name,
+		PictureURL:    data.Picture,
+		EmailVerified: data.VerifiedEmail,
 	}, nil
 }
@@ -0,0 +1,93 @@
+package password
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BreachedChecker indica si una contraseña aparece en filtraciones conocidas.
+type BreachedChecker interface {
+	IsBreached(ctx context.Context, pw string) (bool, error)
+}
+
+// PrefixDirChecker consulta un dataset local con el formato de rangos de
+// HIBP: un archivo por prefijo de 5 caracteres del SHA-1 (ej. 21BD1.txt) con
+// líneas SUFIJO:CANTIDAD. Solo se abre el archivo del prefijo, así que
+// funciona offline y el dataset puede ser parcial.
+type PrefixDirChecker struct {
+	dir string
+}
+
+func NewPrefixDirChecker(dir string) (*PrefixDirChecker, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("password: dataset de contraseñas filtradas: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("password: %s no es un directorio", dir)
+	}
+	return &PrefixDirChecker{dir: dir}, nil
+}
+
+func (c *PrefixDirChecker) IsBreached(ctx context.Context, pw string) (bool, error) {
+	hash := SHA1Hex(pw)
+	prefix, suffix := hash[:5], hash[5:]
+
+	f, err := os.Open(filepath.Join(c.dir, prefix+".txt"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("password: abrir rango %s: %w", prefix, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		line := strings.TrimSpace(scanner.Text())
+		candidate, count, _ := strings.Cut(line, ":")
+		if strings.EqualFold(candidate, suffix) && count != "0" {
+			return true, nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("password: leer rango %s: %w", prefix, err)
+	}
+	return false, nil
+}
+
+// MemoryChecker es un dataset en memoria de hashes SHA-1 completos.
+type MemoryChecker struct {
+	hashes map[string]struct{}
+}
+
+func NewMemoryChecker(passwords ...string) *MemoryChecker {
+	c := &MemoryChecker{hashes: make(map[string]struct{}, len(passwords))}
+	for _, pw := range passwords {
+		c.hashes[SHA1Hex(pw)] = struct{}{}
+	}
+	return c
+}
+
+func (c *MemoryChecker) IsBreached(_ context.Context, pw string) (bool, error) {
+	_, ok := c.hashes[SHA1Hex(pw)]
+	return ok, nil
+}
+
+// SHA1Hex devuelve el SHA-1 en hexadecimal mayúscula, como lo publica HIBP.
+func SHA1Hex(pw string) string {
+	sum := sha1.Sum([]byte(pw))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
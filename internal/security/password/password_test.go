@@ -0,0 +1,101 @@
+package password
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	p := DefaultPolicy()
+
+	tests := []struct {
+		name     string
+		pw       string
+		problems int
+	}{
+		{"válida", "Cafe2024x", 0},
+		{"corta", "Ca1", 1},
+		{"sin mayúscula ni número", "cafecafe", 2},
+		{"parecida al email", "Ana.Perez1", 0},
+		{"igual al usuario del email", "Anaperez1", 1},
+		{"igual al nombre", "Juan Carlos1", 0},
+	}
+
+	for _, tt := range tests {
+		got := p.Validate(tt.pw, "anaperez1@example.com", "Juan")
+		if len(got) != tt.problems {
+			t.Fatalf("%s: Validate(%q) = %v, want %d problems", tt.name, tt.pw, got, tt.problems)
+		}
+	}
+
+	if got := p.Validate("Juan", "x@example.com", "juan"); !containsPrefix(got, "No puede ser igual a tu nombre") {
+		t.Fatalf("expected name problem, got %v", got)
+	}
+}
+
+func TestPolicy_Validate_symbol(t *testing.T) {
+	t.Parallel()
+
+	p := Policy{MinLength: 4, RequireSymbol: true}
+
+	if got := p.Validate("abcd", "", ""); len(got) != 1 {
+		t.Fatalf("Validate(abcd) = %v, want symbol problem", got)
+	}
+	if got := p.Validate("ab#d", "", ""); len(got) != 0 {
+		t.Fatalf("Validate(ab#d) = %v, want none", got)
+	}
+}
+
+func TestPrefixDirChecker(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	hash := SHA1Hex("password123")
+
+	content := "0000000000000000000000000000000000A:3\n" + hash[5:] + ":42\n"
+	if err := os.WriteFile(filepath.Join(dir, hash[:5]+".txt"), []byte(content), 0o600); err != nil {
+		t.Fatalf("write dataset: %v", err)
+	}
+
+	c, err := NewPrefixDirChecker(dir)
+	if err != nil {
+		t.Fatalf("NewPrefixDirChecker: %v", err)
+	}
+
+	if breached, err := c.IsBreached(context.Background(), "password123"); err != nil || !breached {
+		t.Fatalf("IsBreached(password123) = (%v, %v), want (true, nil)", breached, err)
+	}
+	if breached, err := c.IsBreached(context.Background(), "Otra-Clave-99"); err != nil || breached {
+		t.Fatalf("IsBreached(other) = (%v, %v), want (false, nil)", breached, err)
+	}
+
+	if _, err := NewPrefixDirChecker(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("NewPrefixDirChecker should fail for a missing directory")
+	}
+}
+
+func TestMemoryChecker(t *testing.T) {
+	t.Parallel()
+
+	c := NewMemoryChecker("123456")
+	if breached, _ := c.IsBreached(context.Background(), "123456"); !breached {
+		t.Fatal("expected 123456 to be breached")
+	}
+	if breached, _ := c.IsBreached(context.Background(), "1234567"); breached {
+		t.Fatal("expected 1234567 not to be breached")
+	}
+}
+
+func containsPrefix(list []string, prefix string) bool {
+	for _, s := range list {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
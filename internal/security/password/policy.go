@@ -0,0 +1,83 @@
+// Package password concentra las reglas que debe cumplir una contraseña nueva
+// y el chequeo contra contraseñas filtradas.
+package password
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Policy define los requisitos de una contraseña. HistorySize es cuántas
+// contraseñas anteriores no se pueden reutilizar (0 desactiva el chequeo).
+type Policy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	HistorySize   int
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength:    8,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+		HistorySize:  5,
+	}
+}
+
+// Validate devuelve los requisitos incumplidos, en el orden en que conviene
+// mostrárselos al usuario. Vacío significa que la contraseña es válida.
+func (p Policy) Validate(pw, email, name string) []string {
+	var problems []string
+
+	if utf8.RuneCountInString(pw) < p.MinLength {
+		problems = append(problems, "Debe tener al menos "+strconv.Itoa(p.MinLength)+" caracteres")
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range pw {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	if p.RequireUpper && !upper {
+		problems = append(problems, "Debe incluir al menos una mayúscula")
+	}
+	if p.RequireLower && !lower {
+		problems = append(problems, "Debe incluir al menos una minúscula")
+	}
+	if p.RequireDigit && !digit {
+		problems = append(problems, "Debe incluir al menos un número")
+	}
+	if p.RequireSymbol && !symbol {
+		problems = append(problems, "Debe incluir al menos un símbolo")
+	}
+
+	normalized := strings.ToLower(strings.TrimSpace(pw))
+	if e := strings.ToLower(strings.TrimSpace(email)); e != "" {
+		local, _, _ := strings.Cut(e, "@")
+		if normalized == e || normalized == local {
+			problems = append(problems, "No puede ser igual a tu email")
+		}
+	}
+	if n := strings.ToLower(strings.TrimSpace(name)); n != "" {
+		if normalized == n || normalized == strings.ReplaceAll(n, " ", "") {
+			problems = append(problems, "No puede ser igual a tu nombre")
+		}
+	}
+
+	return problems
+}
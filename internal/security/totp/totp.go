@@ -0,0 +1,141 @@
+// Package totp implementa códigos de un solo uso basados en tiempo (RFC 6238)
+// y la generación de códigos de recuperación para el segundo factor.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Period es la duración de cada ventana de tiempo.
+	Period = 30 * time.Second
+	// Digits es la cantidad de dígitos del código.
+	Digits = 6
+	// Skew es la cantidad de ventanas aceptadas antes y después de la actual,
+	// para tolerar relojes de celulares levemente desfasados.
+	Skew = 1
+
+	secretSize       = 20
+	recoveryCodeSize = 10
+)
+
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret devuelve un secreto aleatorio codificado en base32 (sin padding).
+func GenerateSecret() (string, error) {
+	buf := make([]byte, secretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("totp: generar secreto: %w", err)
+	}
+	return b32.EncodeToString(buf), nil
+}
+
+// ProvisioningURI arma la URI otpauth:// que las apps autenticadoras leen desde un QR.
+func ProvisioningURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprintf("%d", Digits))
+	q.Set("period", fmt.Sprintf("%d", int(Period.Seconds())))
+
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
+
+// Step devuelve el número de ventana correspondiente a t.
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+// Code calcula el código para una ventana puntual.
+func Code(secret string, step int64) (string, error) {
+	key, err := b32.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil {
+		return "", fmt.Errorf("totp: secreto inválido: %w", err)
+	}
+	return hotp(key, step, Digits), nil
+}
+
+// Validate verifica el código contra la ventana actual ± Skew. Devuelve la
+// ventana que matcheó para que el llamador pueda rechazar reutilizaciones.
+func Validate(secret, code string, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	key, err := b32.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil {
+		return 0, false
+	}
+
+	current := Step(now)
+	for i := -Skew; i <= Skew; i++ {
+		step := current + int64(i)
+		if hmac.Equal([]byte(hotp(key, step, Digits)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// GenerateRecoveryCodes devuelve n códigos de recuperación legibles (xxxxx-xxxxx).
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	const alphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+	codes := make([]string, 0, n)
+	buf := make([]byte, recoveryCodeSize)
+
+	for i := 0; i < n; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("totp: generar códigos de recuperación: %w", err)
+		}
+		var sb strings.Builder
+		for j, b := range buf {
+			if j == recoveryCodeSize/2 {
+				sb.WriteByte('-')
+			}
+			sb.WriteByte(alphabet[int(b)%len(alphabet)])
+		}
+		codes = append(codes, sb.String())
+	}
+
+	return codes, nil
+}
+
+// NormalizeRecoveryCode limpia lo que tipea el usuario (espacios, mayúsculas).
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+}
+
+func hotp(key []byte, counter int64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	bin := (uint32(sum[offset])&0x7f)<<24 |
+		uint32(sum[offset+1])<<16 |
+		uint32(sum[offset+2])<<8 |
+		uint32(sum[offset+3])
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, bin%mod)
+}
@@ -0,0 +1,99 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Vectores de RFC 6238 (apéndice B) para SHA1, truncados a 6 dígitos.
+func TestCode_RFC6238Vectors(t *testing.T) {
+	t.Parallel()
+
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(secret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(%d) error: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Fatalf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate_AcceptsSkewAndRejectsOthers(t *testing.T) {
+	t.Parallel()
+
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	prev, _ := Code(secret, Step(now)-1)
+	far, _ := Code(secret, Step(now)-3)
+
+	if step, ok := Validate(secret, prev, now); !ok || step != Step(now)-1 {
+		t.Fatalf("Validate(prev) = (%d, %v), want (%d, true)", step, ok, Step(now)-1)
+	}
+	if _, ok := Validate(secret, far, now); ok && far != prev {
+		t.Fatal("Validate should reject codes outside the skew window")
+	}
+	if _, ok := Validate(secret, "12345", now); ok {
+		t.Fatal("Validate should reject codes with wrong length")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	t.Parallel()
+
+	uri := ProvisioningURI("Powermix", "ana@example.com", "ABCDEF")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/Powermix:ana@example.com?") {
+		t.Fatalf("unexpected uri prefix: %s", uri)
+	}
+	if !strings.Contains(uri, "secret=ABCDEF") || !strings.Contains(uri, "issuer=Powermix") {
+		t.Fatalf("uri missing secret or issuer: %s", uri)
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes: %v", err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("len = %d, want 10", len(codes))
+	}
+
+	seen := map[string]bool{}
+	for _, c := range codes {
+		if len(c) != 11 || c[5] != '-' {
+			t.Fatalf("unexpected code format: %q", c)
+		}
+		if seen[c] {
+			t.Fatalf("duplicate code: %q", c)
+		}
+		seen[c] = true
+	}
+
+	if got := NormalizeRecoveryCode("  ABCDE-FGHJK "); got != "abcde-fghjk" {
+		t.Fatalf("NormalizeRecoveryCode = %q", got)
+	}
+}
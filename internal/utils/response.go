@@ -15,6 +15,14 @@ const (
 	ErrCodeTimeout         = "ERR_TIMEOUT"
 	ErrCodeInternal        = "ERR_INTERNAL"
 	ErrCodeExternalService = "ERR_EXTERNAL_SERVICE"
+	ErrCodeRateLimited     = "ERR_RATE_LIMITED"
+
+	// Reglas de elegibilidad de comprobantes.
+	ErrCodePaymentNotApproved = "ERR_PAYMENT_NOT_APPROVED"
+	ErrCodePaymentTooOld      = "ERR_PAYMENT_TOO_OLD"
+	ErrCodeAmountTooLow       = "ERR_AMOUNT_TOO_LOW"
+	ErrCodeForeignCollector   = "ERR_FOREIGN_COLLECTOR"
+	ErrCodeProductNotEligible = "ERR_PRODUCT_NOT_ELIGIBLE"
 )
 
 type APIResponse struct {
@@ -71,6 +79,8 @@ func codeFromHTTPStatus(statusCode int) string {
 		return ErrCodeDuplicateEntry
 	case http.StatusLocked:
 		return ErrCodeInvalidCreds
+	case http.StatusTooManyRequests:
+		return ErrCodeRateLimited
 	case http.StatusGatewayTimeout, http.StatusRequestTimeout:
 		return ErrCodeTimeout
 	default: